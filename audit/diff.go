@@ -0,0 +1,82 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/tat-101/bb-assignment-back/logging"
+)
+
+const redacted = "[REDACTED]"
+
+// ignoredFields change with every write and say nothing about it.
+var ignoredFields = map[string]bool{"UpdatedAt": true}
+
+// Change is a field's value before and after.
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Diff returns the fields that differ between the JSON forms of before and
+// after as a JSON object of Changes. Secrets such as passwords are redacted,
+// though that they changed is kept. Either side may be nil. It returns ""
+// when nothing differs.
+func Diff(before, after interface{}) (string, error) {
+	from, err := fields(before)
+	if err != nil {
+		return "", err
+	}
+	to, err := fields(after)
+	if err != nil {
+		return "", err
+	}
+
+	names := make([]string, 0, len(from)+len(to))
+	for name := range from {
+		names = append(names, name)
+	}
+	for name := range to {
+		if _, ok := from[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := map[string]Change{}
+	for _, name := range names {
+		if ignoredFields[name] || reflect.DeepEqual(from[name], to[name]) {
+			continue
+		}
+		change := Change{From: from[name], To: to[name]}
+		if logging.IsSensitive(name) {
+			change = Change{From: redactValue(from[name]), To: redactValue(to[name])}
+		}
+		changes[name] = change
+	}
+	if len(changes) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(changes)
+	return string(data), err
+}
+
+func fields(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil()) {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	return m, json.Unmarshal(data, &m)
+}
+
+func redactValue(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redacted
+}
@@ -0,0 +1,203 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// AuditRepository is an autogenerated mock type for the AuditRepository type
+type AuditRepository struct {
+	mock.Mock
+}
+
+type AuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditRepository) EXPECT() *AuditRepository_Expecter {
+	return &AuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// AppendAuditEvent provides a mock function with given fields: ctx, event
+func (_m *AuditRepository) AppendAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AppendAuditEvent")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.AuditEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AuditRepository_AppendAuditEvent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AppendAuditEvent'
+type AuditRepository_AppendAuditEvent_Call struct {
+	*mock.Call
+}
+
+// AppendAuditEvent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event *domain.AuditEvent
+func (_e *AuditRepository_Expecter) AppendAuditEvent(ctx interface{}, event interface{}) *AuditRepository_AppendAuditEvent_Call {
+	return &AuditRepository_AppendAuditEvent_Call{Call: _e.mock.On("AppendAuditEvent", ctx, event)}
+}
+
+func (_c *AuditRepository_AppendAuditEvent_Call) Run(run func(ctx context.Context, event *domain.AuditEvent)) *AuditRepository_AppendAuditEvent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.AuditEvent))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_AppendAuditEvent_Call) Return(_a0 error) *AuditRepository_AppendAuditEvent_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *AuditRepository_AppendAuditEvent_Call) RunAndReturn(run func(context.Context, *domain.AuditEvent) error) *AuditRepository_AppendAuditEvent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAuditEvents provides a mock function with given fields: ctx, query
+func (_m *AuditRepository) GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 *domain.AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) *domain.AuditPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditRepository_GetAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditEvents'
+type AuditRepository_GetAuditEvents_Call struct {
+	*mock.Call
+}
+
+// GetAuditEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query domain.AuditQuery
+func (_e *AuditRepository_Expecter) GetAuditEvents(ctx interface{}, query interface{}) *AuditRepository_GetAuditEvents_Call {
+	return &AuditRepository_GetAuditEvents_Call{Call: _e.mock.On("GetAuditEvents", ctx, query)}
+}
+
+func (_c *AuditRepository_GetAuditEvents_Call) Run(run func(ctx context.Context, query domain.AuditQuery)) *AuditRepository_GetAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.AuditQuery))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_GetAuditEvents_Call) Return(_a0 *domain.AuditPage, _a1 error) *AuditRepository_GetAuditEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditRepository_GetAuditEvents_Call) RunAndReturn(run func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)) *AuditRepository_GetAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAuditEventsAfter provides a mock function with given fields: ctx, afterID, limit
+func (_m *AuditRepository) ListAuditEventsAfter(ctx context.Context, afterID uint, limit int) ([]domain.AuditEvent, error) {
+	ret := _m.Called(ctx, afterID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAuditEventsAfter")
+	}
+
+	var r0 []domain.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) ([]domain.AuditEvent, error)); ok {
+		return rf(ctx, afterID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int) []domain.AuditEvent); ok {
+		r0 = rf(ctx, afterID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int) error); ok {
+		r1 = rf(ctx, afterID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditRepository_ListAuditEventsAfter_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAuditEventsAfter'
+type AuditRepository_ListAuditEventsAfter_Call struct {
+	*mock.Call
+}
+
+// ListAuditEventsAfter is a helper method to define mock.On call
+//   - ctx context.Context
+//   - afterID uint
+//   - limit int
+func (_e *AuditRepository_Expecter) ListAuditEventsAfter(ctx interface{}, afterID interface{}, limit interface{}) *AuditRepository_ListAuditEventsAfter_Call {
+	return &AuditRepository_ListAuditEventsAfter_Call{Call: _e.mock.On("ListAuditEventsAfter", ctx, afterID, limit)}
+}
+
+func (_c *AuditRepository_ListAuditEventsAfter_Call) Run(run func(ctx context.Context, afterID uint, limit int)) *AuditRepository_ListAuditEventsAfter_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *AuditRepository_ListAuditEventsAfter_Call) Return(_a0 []domain.AuditEvent, _a1 error) *AuditRepository_ListAuditEventsAfter_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditRepository_ListAuditEventsAfter_Call) RunAndReturn(run func(context.Context, uint, int) ([]domain.AuditEvent, error)) *AuditRepository_ListAuditEventsAfter_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditRepository creates a new instance of AuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRepository {
+	mock := &AuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
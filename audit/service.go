@@ -0,0 +1,149 @@
+// Package audit keeps an append-only, hash-chained log of who did what to
+// which user.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"unicode/utf8"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/logging"
+)
+
+//go:generate mockery --name AuditRepository
+type AuditRepository interface {
+	// AppendAuditEvent seals event onto the end of the chain and stores it.
+	// Appends are serialized so the chain has no forks.
+	AppendAuditEvent(ctx context.Context, event *domain.AuditEvent) error
+	GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error)
+	// ListAuditEventsAfter returns up to limit events with an ID above
+	// afterID, oldest first.
+	ListAuditEventsAfter(ctx context.Context, afterID uint, limit int) ([]domain.AuditEvent, error)
+}
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 200
+
+	verifyBatchSize = 500
+
+	// Sizes of the audit_events columns that hold values taken from the
+	// request; longer values are cut to fit.
+	maxEmailLength     = 255
+	maxTargetIDLength  = 255
+	maxUserAgentLength = 512
+)
+
+// Entry describes what happened. Record adds who did it, from where and
+// when.
+type Entry struct {
+	Action     string
+	TargetType string
+	TargetID   string
+	// Before and After are the target before and after the change, nil
+	// when it did not exist. Only the fields that differ are recorded.
+	Before interface{}
+	After  interface{}
+	// ActorID and ActorEmail name the actor when the request is not
+	// authenticated, such as the user logging in.
+	ActorID    *uint
+	ActorEmail string
+}
+
+type Service struct {
+	repo AuditRepository
+}
+
+func NewService(repo AuditRepository) *Service {
+	return &Service{repo: repo}
+}
+
+// Record appends entry to the audit log. The actor is the authenticated
+// principal of ctx, unless the entry names one.
+func (s *Service) Record(ctx context.Context, entry Entry) error {
+	changes, err := Diff(entry.Before, entry.After)
+	if err != nil {
+		return fmt.Errorf("diff audit event: %w", err)
+	}
+	info := domain.RequestInfoFrom(ctx)
+	event := &domain.AuditEvent{
+		ActorID:    entry.ActorID,
+		ActorEmail: entry.ActorEmail,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Changes:    changes,
+		ClientIP:   info.ClientIP,
+		UserAgent:  info.UserAgent,
+		RequestID:  logging.RequestID(ctx),
+		CreatedAt:  time.Now(),
+	}
+	if principal := domain.PrincipalFrom(ctx); principal != nil && event.ActorID == nil {
+		event.ActorID = &principal.UserID
+		event.ActorEmail = principal.Email
+	}
+	// Cut before the event is sealed, so the hash covers what is stored.
+	event.ActorEmail = truncate(event.ActorEmail, maxEmailLength)
+	event.TargetID = truncate(event.TargetID, maxTargetIDLength)
+	event.UserAgent = truncate(event.UserAgent, maxUserAgentLength)
+	return s.repo.AppendAuditEvent(ctx, event)
+}
+
+// truncate shortens s to at most n characters, as varchar(n) counts them.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	i := 0
+	for pos := range s {
+		if i == n {
+			return s[:pos]
+		}
+		i++
+	}
+	return s
+}
+
+// GetAuditEvents returns one page of events matching the query, newest
+// first.
+func (s *Service) GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	switch {
+	case query.Limit <= 0:
+		query.Limit = DefaultPageSize
+	case query.Limit > MaxPageSize:
+		query.Limit = MaxPageSize
+	}
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain.ErrBadParamInput)
+	}
+	return s.repo.GetAuditEvents(ctx, query)
+}
+
+// Verify walks the whole chain and reports the first event that was
+// changed, inserted or follows a removed one.
+func (s *Service) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	result := &domain.AuditVerification{Valid: true}
+	var afterID uint
+	prevHash := ""
+	for {
+		events, err := s.repo.ListAuditEventsAfter(ctx, afterID, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			result.Checked++
+			if event.PrevHash != prevHash || event.ComputeHash() != event.Hash {
+				result.Valid = false
+				result.BrokenAt = event.ID
+				return result, nil
+			}
+			prevHash = event.Hash
+			afterID = event.ID
+		}
+		if len(events) < verifyBatchSize {
+			return result, nil
+		}
+	}
+}
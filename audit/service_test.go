@@ -0,0 +1,161 @@
+package audit_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/audit/mocks"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/logging"
+)
+
+func TestService_Record(t *testing.T) {
+	repo := new(mocks.AuditRepository)
+	service := audit.NewService(repo)
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	ctx = domain.WithRequestInfo(ctx, domain.RequestInfo{ClientIP: "192.0.2.1", UserAgent: "test-agent"})
+	ctx = domain.WithPrincipal(ctx, &domain.Principal{UserID: 1, Email: "admin@example.com"})
+
+	var recorded *domain.AuditEvent
+	repo.On("AppendAuditEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*domain.AuditEvent)
+	}).Return(nil)
+
+	err := service.Record(ctx, audit.Entry{
+		Action:     domain.AuditUserUpdate,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   "7",
+		Before:     &domain.User{ID: 7, Name: "Old", Password: "hash-1"},
+		After:      &domain.User{ID: 7, Name: "New", Password: "hash-2"},
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, recorded.ActorID)
+	assert.Equal(t, uint(1), *recorded.ActorID)
+	assert.Equal(t, "admin@example.com", recorded.ActorEmail)
+	assert.Equal(t, "192.0.2.1", recorded.ClientIP)
+	assert.Equal(t, "test-agent", recorded.UserAgent)
+	assert.Equal(t, "req-1", recorded.RequestID)
+	assert.JSONEq(t, `{
+		"Name": {"from": "Old", "to": "New"},
+		"Password": {"from": "[REDACTED]", "to": "[REDACTED]"}
+	}`, recorded.Changes)
+	assert.NotContains(t, recorded.Changes, "hash-")
+}
+
+func TestService_Record_ExplicitActor(t *testing.T) {
+	repo := new(mocks.AuditRepository)
+	service := audit.NewService(repo)
+
+	repo.On("AppendAuditEvent", mock.Anything, mock.MatchedBy(func(e *domain.AuditEvent) bool {
+		return e.ActorID == nil && e.ActorEmail == "user@example.com" && e.Changes == ""
+	})).Return(nil)
+
+	err := service.Record(context.Background(), audit.Entry{Action: domain.AuditLoginFailed, ActorEmail: "user@example.com"})
+
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Record_TruncatesLongValues(t *testing.T) {
+	repo := new(mocks.AuditRepository)
+	service := audit.NewService(repo)
+
+	ctx := domain.WithRequestInfo(context.Background(), domain.RequestInfo{UserAgent: strings.Repeat("ü", 600)})
+
+	var recorded *domain.AuditEvent
+	repo.On("AppendAuditEvent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		recorded = args.Get(1).(*domain.AuditEvent)
+	}).Return(nil)
+
+	err := service.Record(ctx, audit.Entry{
+		Action:     domain.AuditLoginFailed,
+		TargetType: domain.AuditTargetLoginKey,
+		TargetID:   "account:" + strings.Repeat("a", 300),
+		ActorEmail: strings.Repeat("a", 300) + "@example.com",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 512, utf8.RuneCountInString(recorded.UserAgent))
+	assert.True(t, utf8.ValidString(recorded.UserAgent))
+	assert.Len(t, recorded.ActorEmail, 255)
+	assert.Len(t, recorded.TargetID, 255)
+}
+
+func chain(n int) []domain.AuditEvent {
+	events := make([]domain.AuditEvent, n)
+	prev := ""
+	for i := range events {
+		events[i] = domain.AuditEvent{ID: uint(i + 1), Action: domain.AuditLogin, TargetID: "7", CreatedAt: time.Now()}
+		events[i].Seal(prev)
+		prev = events[i].Hash
+	}
+	return events
+}
+
+func TestService_Verify(t *testing.T) {
+	for name, tc := range map[string]struct {
+		tamper   func(events []domain.AuditEvent) []domain.AuditEvent
+		brokenAt uint
+	}{
+		"intact": {tamper: func(e []domain.AuditEvent) []domain.AuditEvent { return e }},
+		"changed": {tamper: func(e []domain.AuditEvent) []domain.AuditEvent {
+			e[1].TargetID = "8"
+			return e
+		}, brokenAt: 2},
+		"removed": {tamper: func(e []domain.AuditEvent) []domain.AuditEvent {
+			return append(e[:1], e[2:]...)
+		}, brokenAt: 3},
+		"rehashed": {tamper: func(e []domain.AuditEvent) []domain.AuditEvent {
+			e[1].TargetID = "8"
+			e[1].Hash = e[1].ComputeHash()
+			return e
+		}, brokenAt: 3},
+	} {
+		t.Run(name, func(t *testing.T) {
+			repo := new(mocks.AuditRepository)
+			service := audit.NewService(repo)
+			repo.On("ListAuditEventsAfter", mock.Anything, uint(0), mock.Anything).Return(tc.tamper(chain(3)), nil)
+
+			result, err := service.Verify(context.Background())
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.brokenAt == 0, result.Valid)
+			assert.Equal(t, tc.brokenAt, result.BrokenAt)
+		})
+	}
+}
+
+func TestService_GetAuditEvents(t *testing.T) {
+	repo := new(mocks.AuditRepository)
+	service := audit.NewService(repo)
+
+	repo.On("GetAuditEvents", mock.Anything, domain.AuditQuery{Limit: audit.MaxPageSize, Action: domain.AuditLogin}).
+		Return(&domain.AuditPage{Total: 1}, nil)
+
+	page, err := service.GetAuditEvents(context.Background(), domain.AuditQuery{Limit: 1000, Action: domain.AuditLogin})
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+
+	_, err = service.GetAuditEvents(context.Background(), domain.AuditQuery{Offset: -1})
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+}
+
+func TestDiff(t *testing.T) {
+	created, err := audit.Diff(nil, &domain.User{ID: 7, Email: "user@example.com"})
+	require.NoError(t, err)
+	assert.Contains(t, created, `"Email":{"from":null,"to":"user@example.com"}`)
+
+	unchanged, err := audit.Diff(&domain.User{ID: 7, UpdatedAt: time.Now()}, &domain.User{ID: 7})
+	require.NoError(t, err)
+	assert.Empty(t, unchanged, "UpdatedAt alone is not a change")
+}
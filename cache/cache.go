@@ -0,0 +1,13 @@
+// Package cache keeps authenticated users close at hand, so validating an
+// access token does not have to load its user from the database every time.
+package cache
+
+import "github.com/tat-101/bb-assignment-back/domain"
+
+// cacheable returns the copy of user to store. The password hash is never
+// needed to authorize a request, so it is left out.
+func cacheable(user *domain.User) *domain.User {
+	copied := *user
+	copied.Password = ""
+	return &copied
+}
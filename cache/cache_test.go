@@ -0,0 +1,107 @@
+package cache_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/cache"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+type principalCache interface {
+	Get(ctx context.Context, id uint) (*domain.User, bool, error)
+	Set(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id uint) error
+}
+
+func testUser() *domain.User {
+	return &domain.User{
+		ID:       7,
+		Email:    "user@example.com",
+		Password: "hash",
+		Roles: []domain.Role{{Name: "admin", Permissions: []domain.Permission{
+			{Name: domain.PermissionUsersRead},
+		}}},
+	}
+}
+
+func testCache(t *testing.T, c principalCache) {
+	ctx := context.Background()
+
+	_, ok, err := c.Get(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, c.Set(ctx, testUser()))
+	cached, ok, err := c.Get(ctx, 7)
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, "user@example.com", cached.Email)
+	assert.Empty(t, cached.Password, "password hashes are not cached")
+	assert.True(t, cached.HasPermission(domain.PermissionUsersRead), "roles are cached")
+
+	require.NoError(t, c.Delete(ctx, 7))
+	_, ok, err = c.Get(ctx, 7)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestLRU(t *testing.T) {
+	testCache(t, cache.NewLRU(10, time.Minute))
+}
+
+func TestLRU_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(2, time.Minute)
+
+	for id := uint(1); id <= 2; id++ {
+		require.NoError(t, c.Set(ctx, &domain.User{ID: id}))
+	}
+	_, ok, _ := c.Get(ctx, 1)
+	require.True(t, ok)
+	require.NoError(t, c.Set(ctx, &domain.User{ID: 3}))
+
+	_, ok, _ = c.Get(ctx, 2)
+	assert.False(t, ok, "user 2 was used least recently")
+	_, ok, _ = c.Get(ctx, 1)
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestLRU_Expires(t *testing.T) {
+	ctx := context.Background()
+	c := cache.NewLRU(10, 10*time.Millisecond)
+
+	require.NoError(t, c.Set(ctx, testUser()))
+	time.Sleep(20 * time.Millisecond)
+
+	_, ok, _ := c.Get(ctx, 7)
+	assert.False(t, ok)
+	assert.Equal(t, 0, c.Len())
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	testCache(t, cache.NewRedis(client, time.Minute, "bb:principal:"))
+}
+
+func TestRedis_Expires(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	c := cache.NewRedis(client, time.Minute, "bb:principal:")
+
+	require.NoError(t, c.Set(context.Background(), testUser()))
+	assert.True(t, server.Exists("bb:principal:7"))
+	server.FastForward(time.Minute)
+
+	_, ok, err := c.Get(context.Background(), 7)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// Redis caches users in Redis, so every instance of the service sees the
+// same entries and an invalidation reaches all of them.
+type Redis struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+	prefix string
+}
+
+// NewRedis caches users in client for ttl under keys starting with prefix.
+func NewRedis(client redis.UniversalClient, ttl time.Duration, prefix string) *Redis {
+	return &Redis{client: client, ttl: ttl, prefix: prefix}
+}
+
+func (c *Redis) Get(ctx context.Context, id uint) (*domain.User, bool, error) {
+	data, err := c.client.Get(ctx, c.key(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	// gob, unlike JSON, keeps the roles that domain.User hides from JSON.
+	var user domain.User
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&user); err != nil {
+		return nil, false, err
+	}
+	return &user, true, nil
+}
+
+func (c *Redis) Set(ctx context.Context, user *domain.User) error {
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(cacheable(user)); err != nil {
+		return err
+	}
+	return c.client.Set(ctx, c.key(user.ID), data.Bytes(), c.ttl).Err()
+}
+
+func (c *Redis) Delete(ctx context.Context, id uint) error {
+	return c.client.Del(ctx, c.key(id)).Err()
+}
+
+func (c *Redis) key(id uint) string {
+	return c.prefix + strconv.FormatUint(uint64(id), 10)
+}
@@ -1,61 +1,522 @@
 package config
 
 import (
-	"log"
-	"os"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/tat-101/bb-assignment-back/logging"
+	"github.com/tat-101/bb-assignment-back/tools"
 )
 
+// MinJWTSecretLength is the shortest JWT_SECRET accepted, 256 bits.
+const MinJWTSecretLength = 32
+
+// Config is the service configuration. Every field is read from the
+// environment variable in its env tag. The same name in lower case is the
+// key in a config file, and in kebab case it is the command-line flag, so
+// DB_HOST can also be set with `db_host: ...` or `--db-host=...`.
 type Config struct {
-	DBHost        string
-	DBUser        string
-	DBPassword    string
-	DBName        string
-	DBPort        string
-	ServerAddress string
-	JWTSecret     string
-	Version       string
-}
-
-// LoadEnv loads env vars from .env
-func LoadConfig() Config {
-	// FIXME: wtf
-	path := ".env"
-	failCount := 0
-	for {
-		err := godotenv.Load(path)
-		if err != nil && failCount > 10 {
-			// log.Printf("Error loading .env file %v", err)
-			log.Printf("Error loading .env file %v", err)
-			os.Exit(-1)
-		}
-		if err == nil {
-			// log.Println("path", path)
-			break
-		}
-		path = "../" + path
-		failCount++
-	}
-
-	return Config{
-		DBHost:        getEnv("DB_HOST", "localhost"),
-		DBUser:        getEnv("DB_USER", "postgres"),
-		DBPassword:    getEnv("DB_PASSWORD", "password"),
-		DBName:        getEnv("DB_NAME", "bb-assignment"),
-		DBPort:        getEnv("DB_PORT", "5432"),
-		ServerAddress: getEnv("SERVER_ADDRESS", "3000"),
-		JWTSecret:     getEnv("JWT_SECRET", "my_secret"),
-		Version:       getEnv("API_VERSION", "v0"),
-	}
-}
-
-// getEnv fetches the value of an environment variable, or returns a default value if not set.
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
+	DBHost        string `env:"DB_HOST" default:"localhost"`
+	DBUser        string `env:"DB_USER" default:"postgres"`
+	DBPassword    string `env:"DB_PASSWORD" default:"password"`
+	DBName        string `env:"DB_NAME" default:"bb-assignment"`
+	DBPort        string `env:"DB_PORT" default:"5432"`
+	ServerAddress string `env:"SERVER_ADDRESS" default:"3000"`
+	Version       string `env:"API_VERSION" default:"v0"`
+	// Environment is production or development. Development relaxes the
+	// settings a real deployment must have, such as JWT_SIGNING_KEY.
+	Environment string `env:"APP_ENV" default:"production"`
+
+	// JWTSecret is the root key for the short lived tokens this service
+	// signs itself: registration, MFA challenges and OIDC login state. It
+	// has no default; each feature gets its own key from TokenKey.
+	JWTSecret string `env:"JWT_SECRET"`
+
+	// JWTSigningKey is the PEM encoded RSA or Ed25519 private key access
+	// tokens are signed with, best read from a file with
+	// JWT_SIGNING_KEY_FILE. JWTVerificationKeys holds more PEM encoded keys
+	// that are still accepted, such as the previous signing key during a
+	// rotation.
+	JWTSigningKey       string `env:"JWT_SIGNING_KEY" default:""`
+	JWTVerificationKeys string `env:"JWT_VERIFICATION_KEYS" default:""`
+	JWTIssuer           string `env:"JWT_ISSUER" default:""`
+	JWTAudience         string `env:"JWT_AUDIENCE" default:""`
+	// TokenValidation is stateful (load the user and check revocations for
+	// every request) or stateless (trust the token's claims).
+	TokenValidation string `env:"TOKEN_VALIDATION" default:"stateful"`
+
+	// PrincipalCache caches the users behind access tokens: lru (in this
+	// process), redis (shared through RedisURL) or none.
+	PrincipalCache     string        `env:"PRINCIPAL_CACHE" default:"lru"`
+	PrincipalCacheSize int           `env:"PRINCIPAL_CACHE_SIZE" default:"10000"`
+	PrincipalCacheTTL  time.Duration `env:"PRINCIPAL_CACHE_TTL" default:"1m"`
+	RedisURL           string        `env:"REDIS_URL" default:""`
+
+	// LoginLockoutStore keeps failed login counts in the database, shared by
+	// every instance, or in memory.
+	LoginLockoutStore string `env:"LOGIN_LOCKOUT_STORE" default:"database"`
+	// An account is locked for LoginLockoutDuration after LoginMaxFailures
+	// failed logins, a client IP after LoginIPMaxFailures. Failures older
+	// than LoginFailureWindow are forgotten.
+	LoginMaxFailures     int           `env:"LOGIN_MAX_FAILURES" default:"10"`
+	LoginIPMaxFailures   int           `env:"LOGIN_IP_MAX_FAILURES" default:"100"`
+	LoginLockoutDuration time.Duration `env:"LOGIN_LOCKOUT_DURATION" default:"15m"`
+	LoginFailureWindow   time.Duration `env:"LOGIN_FAILURE_WINDOW" default:"1h"`
+	// Rate limits look like "300/1m", optionally followed by "by ip", "by
+	// user" or "by api-key" to pick what is counted. RateLimitGlobal applies
+	// to every request, RateLimitAuth to the /auth routes and RateLimitUsers
+	// to the /users and /me routes. An empty limit turns it off.
+	// RateLimitBackend is memory (per instance) or redis (shared through
+	// RedisURL).
+	RateLimitBackend string `env:"RATE_LIMIT_BACKEND" default:"memory"`
+	RateLimitGlobal  string `env:"RATE_LIMIT_GLOBAL" default:"600/1m by ip"`
+	RateLimitAuth    string `env:"RATE_LIMIT_AUTH" default:"30/1m by ip"`
+	RateLimitUsers   string `env:"RATE_LIMIT_USERS" default:"300/1m by user"`
+
+	// TrustedProxies is a comma separated list of proxy IPs or CIDRs whose
+	// X-Forwarded-For header is believed. When empty no proxy is trusted and
+	// the client IP is the address of the connection.
+	TrustedProxies string `env:"TRUSTED_PROXIES" default:""`
+
+	// DeletedUserRetention is how long deleted users can be restored before
+	// they are purged for good.
+	DeletedUserRetention time.Duration `env:"DELETED_USER_RETENTION" default:"720h"`
+
+	AccessTokenTTL  time.Duration `env:"ACCESS_TOKEN_TTL" default:"15m"`
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" default:"720h"`
+
+	MigrateOnBoot bool `env:"MIGRATE_ON_BOOT" default:"true"`
+
+	ReadTimeout     time.Duration `env:"READ_TIMEOUT" default:"15s"`
+	WriteTimeout    time.Duration `env:"WRITE_TIMEOUT" default:"30s"`
+	IdleTimeout     time.Duration `env:"IDLE_TIMEOUT" default:"60s"`
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" default:"10s"`
+
+	// RequestTimeout bounds the work done for each request. RouteTimeouts
+	// overrides it per route as a comma-separated list such as
+	// "GET /users=5s,POST /auth/login=3s".
+	RequestTimeout time.Duration `env:"REQUEST_TIMEOUT" default:"10s"`
+	RouteTimeouts  string        `env:"ROUTE_TIMEOUTS" default:""`
+
+	HealthCheckTimeout time.Duration `env:"HEALTH_CHECK_TIMEOUT" default:"2s"`
+
+	LogLevel string `env:"LOG_LEVEL" default:"info"`
+
+	TracingExporter string `env:"TRACING_EXPORTER" default:"none"`
+	OTLPEndpoint    string `env:"OTLP_ENDPOINT" default:"http://localhost:4318"`
+	TracingFile     string `env:"TRACING_FILE" default:"traces.json"`
+
+	// RegistrationMode is open, invite (invite-only) or disabled.
+	RegistrationMode     string        `env:"REGISTRATION_MODE" default:"open"`
+	VerificationTokenTTL time.Duration `env:"VERIFICATION_TOKEN_TTL" default:"24h"`
+	InvitationTTL        time.Duration `env:"INVITATION_TTL" default:"168h"`
+	PasswordResetTTL     time.Duration `env:"PASSWORD_RESET_TTL" default:"30m"`
+	// FrontendURL is where links in emails point to.
+	FrontendURL string `env:"FRONTEND_URL" default:"http://localhost:5173"`
+
+	MFAIssuer       string        `env:"MFA_ISSUER" default:"BB Assignment"`
+	MFAChallengeTTL time.Duration `env:"MFA_CHALLENGE_TTL" default:"5m"`
+	// MFARequiredRoles is a comma separated list of roles that must use MFA.
+	MFARequiredRoles string `env:"MFA_REQUIRED_ROLES" default:""`
+
+	// OIDCProviders is a JSON array of OIDCProvider. As it holds client
+	// secrets it is best read from a file with OIDC_PROVIDERS_FILE.
+	OIDCProviders string `env:"OIDC_PROVIDERS" default:""`
+
+	// Mailer is smtp, file (write to MailDir) or log.
+	Mailer       string `env:"MAILER" default:"log"`
+	MailFrom     string `env:"MAIL_FROM" default:"no-reply@localhost"`
+	MailDir      string `env:"MAIL_DIR" default:"mail"`
+	SMTPHost     string `env:"SMTP_HOST" default:""`
+	SMTPPort     string `env:"SMTP_PORT" default:"587"`
+	SMTPUsername string `env:"SMTP_USERNAME" default:""`
+	SMTPPassword string `env:"SMTP_PASSWORD" default:""`
+}
+
+// Load builds the configuration from, in increasing priority: defaults, an
+// optional YAML or TOML file, environment variables and command-line flags.
+//
+// The file is named by --config or CONFIG_FILE. A .env file in the working
+// directory or one of its parents is loaded into the environment if present.
+// For any variable, KEY_FILE may name a file holding the value instead, e.g.
+// JWT_SECRET_FILE for a mounted secret.
+func Load(args []string) (Config, error) {
+	var cfg Config
+	if err := load(&cfg, args); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate reports every invalid setting at once.
+func (cfg Config) Validate() error {
+	var errs []error
+	require := func(key, value string) {
+		if value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", key))
+		}
+	}
+	port := func(key, value string) {
+		if p, err := strconv.Atoi(value); err != nil || p < 1 || p > 65535 {
+			errs = append(errs, fmt.Errorf("%s must be a port number, got %q", key, value))
+		}
+	}
+	positive := func(key string, d time.Duration) {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", key, d))
+		}
+	}
+
+	require("DB_HOST", cfg.DBHost)
+	require("DB_USER", cfg.DBUser)
+	require("DB_NAME", cfg.DBName)
+	port("DB_PORT", cfg.DBPort)
+	port("SERVER_ADDRESS", cfg.ServerAddress)
+	switch cfg.Environment {
+	case "production", "development":
+	default:
+		errs = append(errs, fmt.Errorf("APP_ENV must be production or development, got %q", cfg.Environment))
+	}
+	if _, err := cfg.JWTKeyRing(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch cfg.TokenValidation {
+	case "stateful", "stateless":
+	default:
+		errs = append(errs, fmt.Errorf("TOKEN_VALIDATION must be stateful or stateless, got %q", cfg.TokenValidation))
+	}
+	switch cfg.PrincipalCache {
+	case "none":
+	case "lru":
+		if cfg.PrincipalCacheSize < 1 {
+			errs = append(errs, fmt.Errorf("PRINCIPAL_CACHE_SIZE must be positive, got %d", cfg.PrincipalCacheSize))
+		}
+		positive("PRINCIPAL_CACHE_TTL", cfg.PrincipalCacheTTL)
+	case "redis":
+		positive("PRINCIPAL_CACHE_TTL", cfg.PrincipalCacheTTL)
+	default:
+		errs = append(errs, fmt.Errorf("PRINCIPAL_CACHE must be lru, redis or none, got %q", cfg.PrincipalCache))
+	}
+	positive("ACCESS_TOKEN_TTL", cfg.AccessTokenTTL)
+	if cfg.RefreshTokenTTL <= cfg.AccessTokenTTL {
+		errs = append(errs, fmt.Errorf("REFRESH_TOKEN_TTL must be longer than ACCESS_TOKEN_TTL, got %s", cfg.RefreshTokenTTL))
+	}
+
+	switch cfg.LoginLockoutStore {
+	case "database", "memory":
+	default:
+		errs = append(errs, fmt.Errorf("LOGIN_LOCKOUT_STORE must be database or memory, got %q", cfg.LoginLockoutStore))
+	}
+	if cfg.LoginMaxFailures < 1 {
+		errs = append(errs, fmt.Errorf("LOGIN_MAX_FAILURES must be positive, got %d", cfg.LoginMaxFailures))
+	}
+	if cfg.LoginIPMaxFailures < 1 {
+		errs = append(errs, fmt.Errorf("LOGIN_IP_MAX_FAILURES must be positive, got %d", cfg.LoginIPMaxFailures))
+	}
+	positive("LOGIN_LOCKOUT_DURATION", cfg.LoginLockoutDuration)
+	positive("LOGIN_FAILURE_WINDOW", cfg.LoginFailureWindow)
+	positive("DELETED_USER_RETENTION", cfg.DeletedUserRetention)
+	switch cfg.RateLimitBackend {
+	case "memory", "redis":
+	default:
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_BACKEND must be memory or redis, got %q", cfg.RateLimitBackend))
+	}
+	if _, err := cfg.RateLimitRules(); err != nil {
+		errs = append(errs, err)
+	}
+	if cfg.UsesRedis() {
+		require("REDIS_URL", cfg.RedisURL)
+	}
+	if _, err := cfg.TrustedProxyList(); err != nil {
+		errs = append(errs, err)
+	}
+
+	positive("READ_TIMEOUT", cfg.ReadTimeout)
+	positive("WRITE_TIMEOUT", cfg.WriteTimeout)
+	positive("IDLE_TIMEOUT", cfg.IdleTimeout)
+	positive("SHUTDOWN_TIMEOUT", cfg.ShutdownTimeout)
+	positive("REQUEST_TIMEOUT", cfg.RequestTimeout)
+	if _, err := cfg.RouteTimeoutMap(); err != nil {
+		errs = append(errs, err)
+	}
+	positive("HEALTH_CHECK_TIMEOUT", cfg.HealthCheckTimeout)
+
+	if _, err := logging.ParseLevel(cfg.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("LOG_LEVEL must be debug, info, warn or error, got %q", cfg.LogLevel))
+	}
+
+	switch cfg.TracingExporter {
+	case "none", "stdout":
+	case "otlp":
+		require("OTLP_ENDPOINT", cfg.OTLPEndpoint)
+	case "file":
+		require("TRACING_FILE", cfg.TracingFile)
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER must be none, otlp, stdout or file, got %q", cfg.TracingExporter))
+	}
+
+	switch cfg.RegistrationMode {
+	case "open", "invite", "disabled":
+	default:
+		errs = append(errs, fmt.Errorf("REGISTRATION_MODE must be open, invite or disabled, got %q", cfg.RegistrationMode))
+	}
+	positive("VERIFICATION_TOKEN_TTL", cfg.VerificationTokenTTL)
+	positive("INVITATION_TTL", cfg.InvitationTTL)
+	positive("PASSWORD_RESET_TTL", cfg.PasswordResetTTL)
+	require("MFA_ISSUER", cfg.MFAIssuer)
+	positive("MFA_CHALLENGE_TTL", cfg.MFAChallengeTTL)
+	if u, err := url.Parse(cfg.FrontendURL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("FRONTEND_URL must be an absolute URL, got %q", cfg.FrontendURL))
+	}
+
+	switch cfg.Mailer {
+	case "log":
+	case "file":
+		require("MAIL_DIR", cfg.MailDir)
+	case "smtp":
+		require("SMTP_HOST", cfg.SMTPHost)
+		port("SMTP_PORT", cfg.SMTPPort)
+		require("MAIL_FROM", cfg.MailFrom)
+	default:
+		errs = append(errs, fmt.Errorf("MAILER must be smtp, file or log, got %q", cfg.Mailer))
+	}
+
+	if _, err := cfg.OIDCProviderList(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// ValidateServer reports the settings that only the API server needs and
+// that Validate leaves out, so tools such as migrate and seed run without
+// them.
+func (cfg Config) ValidateServer() error {
+	var errs []error
+	if len(cfg.JWTSecret) < MinJWTSecretLength {
+		errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes, e.g. from `openssl rand -hex 32`", MinJWTSecretLength))
+	}
+	// A generated key is lost on restart and unknown to other instances.
+	if strings.TrimSpace(cfg.JWTSigningKey) == "" && !cfg.IsDevelopment() {
+		errs = append(errs, errors.New("JWT_SIGNING_KEY is required unless APP_ENV is development, e.g. from `openssl genpkey -algorithm ed25519`"))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// IsDevelopment reports whether APP_ENV is development.
+func (cfg Config) IsDevelopment() bool {
+	return cfg.Environment == "development"
+}
+
+// RouteTimeoutMap parses RouteTimeouts into timeouts keyed by "METHOD /path".
+func (cfg Config) RouteTimeoutMap() (map[string]time.Duration, error) {
+	routes := make(map[string]time.Duration)
+	for _, entry := range strings.Split(cfg.RouteTimeouts, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, value, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath || method == "" || !strings.HasPrefix(strings.TrimSpace(path), "/") {
+			return nil, fmt.Errorf("ROUTE_TIMEOUTS entry %q must look like \"GET /users=5s\"", entry)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("ROUTE_TIMEOUTS entry %q must have a positive duration", entry)
+		}
+		routes[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = d
+	}
+	return routes, nil
+}
+
+// TokenKey derives the HMAC key for the tokens of one feature from
+// JWT_SECRET, so a key leaked or misused by one feature is useless to the
+// others.
+func (cfg Config) TokenKey(feature string) []byte {
+	mac := hmac.New(sha256.New, []byte(cfg.JWTSecret))
+	mac.Write([]byte("bb-assignment/" + feature))
+	return mac.Sum(nil)
+}
+
+// JWTKeyRing parses the JWT keys. It returns nil when no signing key is set.
+func (cfg Config) JWTKeyRing() (*tools.KeyRing, error) {
+	if strings.TrimSpace(cfg.JWTSigningKey) == "" {
+		if strings.TrimSpace(cfg.JWTVerificationKeys) != "" {
+			return nil, errors.New("JWT_VERIFICATION_KEYS needs a JWT_SIGNING_KEY")
+		}
+		return nil, nil
+	}
+	signingKey, err := tools.ParsePrivateKeyPEM([]byte(cfg.JWTSigningKey))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_SIGNING_KEY must be a PEM encoded private key: %w", err)
+	}
+	verificationKeys, err := tools.ParsePublicKeysPEM([]byte(cfg.JWTVerificationKeys))
+	if err != nil {
+		return nil, fmt.Errorf("JWT_VERIFICATION_KEYS must be PEM encoded keys: %w", err)
+	}
+	keys, err := tools.NewKeyRing(tools.KeyRingConfig{
+		SigningKey:       signingKey,
+		VerificationKeys: verificationKeys,
+		Issuer:           cfg.JWTIssuer,
+		Audience:         cfg.JWTAudience,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("JWT keys: %w", err)
+	}
+	return keys, nil
+}
+
+// OIDCProvider is an OpenID Connect provider users can sign in with.
+type OIDCProvider struct {
+	// Name identifies the provider in URLs, e.g. /auth/oidc/google/login.
+	Name         string   `json:"name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"clientId"`
+	ClientSecret string   `json:"clientSecret"`
+	RedirectURL  string   `json:"redirectUrl"`
+	Scopes       []string `json:"scopes"`
+}
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// OIDCProviderList parses OIDCProviders.
+func (cfg Config) OIDCProviderList() ([]OIDCProvider, error) {
+	if strings.TrimSpace(cfg.OIDCProviders) == "" {
+		return nil, nil
+	}
+	var providers []OIDCProvider
+	if err := json.Unmarshal([]byte(cfg.OIDCProviders), &providers); err != nil {
+		return nil, fmt.Errorf("OIDC_PROVIDERS must be a JSON array of providers: %w", err)
+	}
+	seen := map[string]bool{}
+	for _, p := range providers {
+		switch {
+		case !providerNamePattern.MatchString(p.Name):
+			return nil, fmt.Errorf("OIDC_PROVIDERS name %q must be lowercase letters, digits and dashes", p.Name)
+		case seen[p.Name]:
+			return nil, fmt.Errorf("OIDC_PROVIDERS name %q is used twice", p.Name)
+		case p.Issuer == "" || p.ClientID == "" || p.RedirectURL == "":
+			return nil, fmt.Errorf("OIDC_PROVIDERS %q needs issuer, clientId and redirectUrl", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	return providers, nil
+}
+
+// UsesRedis reports whether any feature is configured to use Redis.
+func (cfg Config) UsesRedis() bool {
+	return cfg.PrincipalCache == "redis" || cfg.RateLimitBackend == "redis"
+}
+
+// RateLimit is a parsed rate limit. Key is ip, user or api-key.
+type RateLimit struct {
+	Requests int
+	Period   time.Duration
+	Key      string
+}
+
+// RateLimits are the parsed rate limits. Unset limits are zero.
+type RateLimits struct {
+	Global RateLimit
+	Auth   RateLimit
+	Users  RateLimit
+}
+
+// RateLimitRules parses the rate limits.
+func (cfg Config) RateLimitRules() (RateLimits, error) {
+	var limits RateLimits
+	var errs []error
+	for _, setting := range []struct {
+		key, value string
+		limit      *RateLimit
+	}{
+		{"RATE_LIMIT_GLOBAL", cfg.RateLimitGlobal, &limits.Global},
+		{"RATE_LIMIT_AUTH", cfg.RateLimitAuth, &limits.Auth},
+		{"RATE_LIMIT_USERS", cfg.RateLimitUsers, &limits.Users},
+	} {
+		limit, err := parseRateLimit(setting.key, setting.value)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		*setting.limit = limit
+	}
+	return limits, errors.Join(errs...)
+}
+
+func parseRateLimit(key, value string) (RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return RateLimit{}, nil
+	}
+	invalid := fmt.Errorf("%s must look like \"300/1m by ip\", got %q", key, value)
+	rate, by, hasKey := strings.Cut(value, " by ")
+	limit := RateLimit{Key: "ip"}
+	if hasKey {
+		limit.Key = strings.TrimSpace(by)
+	}
+	switch limit.Key {
+	case "ip", "user", "api-key":
+	default:
+		return RateLimit{}, invalid
+	}
+	requests, period, ok := strings.Cut(strings.TrimSpace(rate), "/")
+	if !ok {
+		return RateLimit{}, invalid
+	}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 1 {
+		return RateLimit{}, invalid
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period < time.Millisecond {
+		return RateLimit{}, invalid
+	}
+	return limit, nil
+}
+
+// TrustedProxyList splits TrustedProxies. It returns nil when no proxy is
+// listed.
+func (cfg Config) TrustedProxyList() ([]string, error) {
+	var proxies []string
+	for _, proxy := range strings.Split(cfg.TrustedProxies, ",") {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES entry %q must be an IP address or CIDR", proxy)
+		}
+		proxies = append(proxies, proxy)
+	}
+	return proxies, nil
+}
+
+// MFARequiredRoleList splits MFARequiredRoles.
+func (cfg Config) MFARequiredRoleList() []string {
+	var roles []string
+	for _, role := range strings.Split(cfg.MFARequiredRoles, ",") {
+		if role = strings.TrimSpace(role); role != "" {
+			roles = append(roles, role)
+		}
+	}
+	return roles
 }
 
 func (cfg Config) GetDBConfig() string {
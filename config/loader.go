@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// field is one Config field and the names it is known by in each source.
+type field struct {
+	key   string // environment variable, e.g. DB_HOST
+	value reflect.Value
+	def   string
+}
+
+func (f field) fileKey() string { return strings.ToLower(f.key) }
+func (f field) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(f.key), "_", "-")
+}
+
+func fields(cfg *Config) []field {
+	v := reflect.ValueOf(cfg).Elem()
+	t := v.Type()
+	out := make([]field, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		key := t.Field(i).Tag.Get("env")
+		if key == "" {
+			continue
+		}
+		out = append(out, field{key: key, value: v.Field(i), def: t.Field(i).Tag.Get("default")})
+	}
+	return out
+}
+
+func load(cfg *Config, args []string) error {
+	fs := fields(cfg)
+
+	flags := flag.NewFlagSet("bb-assignment-back", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML or TOML config file")
+	flagValues := make(map[string]*string, len(fs))
+	for _, f := range fs {
+		flagValues[f.key] = flags.String(f.flagName(), "", "overrides "+f.key)
+	}
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("invalid flags: %w", err)
+	}
+
+	loadDotEnv()
+
+	fileValues := map[string]string{}
+	if *configFile != "" {
+		var err error
+		if fileValues, err = readConfigFile(*configFile); err != nil {
+			return err
+		}
+	}
+
+	setFlags := map[string]bool{}
+	flags.Visit(func(fl *flag.Flag) { setFlags[fl.Name] = true })
+
+	var errs []error
+	for _, f := range fs {
+		value, ok := f.def, true
+		if v, found := fileValues[f.fileKey()]; found {
+			value = v
+		}
+		if v, found := os.LookupEnv(f.key); found {
+			value = v
+		} else if path, found := os.LookupEnv(f.key + "_FILE"); found {
+			secret, err := os.ReadFile(path)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s_FILE: %w", f.key, err))
+				ok = false
+			}
+			value = strings.TrimSpace(string(secret))
+		}
+		if setFlags[f.flagName()] {
+			value = *flagValues[f.key]
+		}
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// loadDotEnv loads the nearest .env file from the working directory or its
+// parents, so that tests running inside a package directory find the one at
+// the repository root. Variables already set in the environment win.
+func loadDotEnv() {
+	dir, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	for {
+		path := filepath.Join(dir, ".env")
+		if _, err := os.Stat(path); err == nil {
+			_ = godotenv.Load(path)
+			return
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return
+		}
+		dir = parent
+	}
+}
+
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file: %w", err)
+	}
+
+	raw := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	case ".toml":
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for key, value := range raw {
+		values[strings.ToLower(key)] = fmt.Sprint(value)
+	}
+	return values, nil
+}
+
+func setValue(v reflect.Value, value string) error {
+	switch v.Interface().(type) {
+	case string:
+		v.SetString(value)
+	case bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("must be true or false, got %q", value)
+		}
+		v.SetBool(b)
+	case int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("must be a number, got %q", value)
+		}
+		v.SetInt(int64(n))
+	case time.Duration:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("must be a duration such as 15m, got %q", value)
+		}
+		v.SetInt(int64(d))
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
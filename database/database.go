@@ -1,30 +1,31 @@
 package database
 
 import (
-	"log"
+	"log/slog"
+	"os"
+	"time"
 
 	"github.com/tat-101/bb-assignment-back/config"
+	"github.com/tat-101/bb-assignment-back/logging"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
-var db *gorm.DB
+// slowQueryThreshold is how long a query may take before it is logged as a
+// warning.
+const slowQueryThreshold = 200 * time.Millisecond
 
 func Initialize(cfg config.Config) *gorm.DB {
-	config.LoadConfig()
-
 	dsn := cfg.GetDBConfig()
 
 	// fmt.Println("dsn", dsn)
-	database, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: logging.NewGormLogger(slog.Default(), slowQueryThreshold),
+	})
 	if err != nil {
-		log.Fatalf("failed to connect database: %v", err)
+		slog.Error("Failed to connect database", "error", err)
+		os.Exit(1)
 	}
-	db = database
-
-	return db
-}
 
-func GetDB() *gorm.DB {
 	return db
 }
@@ -0,0 +1,66 @@
+package database_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/database"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX a ON b (c);")},
+		"0002_add_index.down.sql":    {Data: []byte("DROP INDEX a;")},
+		"0001_create_users.up.sql":   {Data: []byte("CREATE TABLE users ();")},
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+		"README.md":                  {Data: []byte("ignored")},
+	}
+
+	migrations, err := database.LoadMigrations(fsys)
+
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	assert.Equal(t, int64(1), migrations[0].Version)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	assert.Equal(t, "DROP TABLE users;", migrations[0].Down)
+	assert.Equal(t, int64(2), migrations[1].Version)
+}
+
+func TestLoadMigrations_MissingUp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0001_create_users.down.sql": {Data: []byte("DROP TABLE users;")},
+	}
+
+	_, err := database.LoadMigrations(fsys)
+
+	assert.Error(t, err)
+}
+
+func TestLoadMigrations_Embedded(t *testing.T) {
+	migrations, err := database.LoadMigrations(os.DirFS("migrations"))
+
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+	assert.Equal(t, "create_users", migrations[0].Name)
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, "migration versions must not have gaps")
+		assert.NotEmpty(t, migration.Down, "migration %d needs a down script", migration.Version)
+	}
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0007_existing.up.sql"), []byte("SELECT 1;"), 0o644))
+
+	up, down, err := database.CreateMigration(dir, "Add User Status")
+
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0008_add_user_status.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0008_add_user_status.down.sql"), down)
+	assert.FileExists(t, up)
+	assert.FileExists(t, down)
+}
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS users (
+    id bigserial PRIMARY KEY,
+    name varchar(255) NOT NULL,
+    email varchar(255),
+    password varchar(255) NOT NULL,
+    role varchar(50) DEFAULT 'user',
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_users_email UNIQUE (email)
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS token_revocations (
+    id bigserial PRIMARY KEY,
+    jti varchar(64),
+    user_id bigint NOT NULL,
+    issued_before timestamptz,
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_revocations_jti ON token_revocations (jti);
+CREATE INDEX IF NOT EXISTS idx_token_revocations_user_id ON token_revocations (user_id);
+CREATE INDEX IF NOT EXISTS idx_token_revocations_expires_at ON token_revocations (expires_at);
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS permissions (
+    id bigserial PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    description varchar(255),
+    CONSTRAINT uni_permissions_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id bigserial PRIMARY KEY,
+    name varchar(50) NOT NULL,
+    description varchar(255),
+    created_at timestamptz,
+    updated_at timestamptz,
+    CONSTRAINT uni_roles_name UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_id bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id)
+);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id)
+);
+
+-- Recreate the join table keys so that deleting a user or role also removes
+-- its assignments. Databases created by gorm's AutoMigrate lack the cascade.
+ALTER TABLE role_permissions
+    DROP CONSTRAINT IF EXISTS fk_role_permissions_role,
+    DROP CONSTRAINT IF EXISTS fk_role_permissions_permission,
+    ADD CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id) ON DELETE CASCADE;
+
+ALTER TABLE user_roles
+    DROP CONSTRAINT IF EXISTS fk_user_roles_user,
+    DROP CONSTRAINT IF EXISTS fk_user_roles_role,
+    ADD CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE,
+    ADD CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id) ON DELETE CASCADE;
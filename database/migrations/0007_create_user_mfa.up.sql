@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id bigint PRIMARY KEY,
+    secret varchar(64) NOT NULL,
+    last_used_step bigint NOT NULL DEFAULT 0,
+    enabled_at timestamptz,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_at timestamptz,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_recovery_codes_user_id ON recovery_codes (user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_recovery_codes_code_hash ON recovery_codes (code_hash);
@@ -0,0 +1,32 @@
+CREATE TABLE IF NOT EXISTS audit_events (
+    id bigserial PRIMARY KEY,
+    actor_id bigint,
+    actor_email varchar(255),
+    action varchar(64) NOT NULL,
+    target_type varchar(64),
+    target_id varchar(255),
+    changes text,
+    client_ip varchar(64),
+    user_agent varchar(512),
+    request_id varchar(128),
+    created_at timestamptz NOT NULL,
+    prev_hash varchar(64),
+    hash varchar(64) NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_id ON audit_events (actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target ON audit_events (target_type, target_id);
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at ON audit_events (created_at);
+
+-- The log is append-only: rows can not be changed or removed short of
+-- dropping the trigger, which the hash chain would still reveal.
+CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_events_append_only ON audit_events;
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE ON audit_events
+    FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
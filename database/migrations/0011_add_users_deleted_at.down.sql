@@ -0,0 +1,4 @@
+-- Soft deleted users would come back to life without the column.
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+-- uni_users_email stays on every row, deleted or not, so a deleted user can
+-- always be restored. Their address is free again once the row is purged.
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS user_status_changes;
+ALTER TABLE users DROP COLUMN IF EXISTS status;
@@ -0,0 +1,16 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS status varchar(20) NOT NULL DEFAULT 'active';
+
+-- Users who never verified their address have not been activated yet.
+UPDATE users SET status = 'pending' WHERE email_verified_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_status_changes (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL,
+    from_status varchar(20) NOT NULL,
+    to_status varchar(20) NOT NULL,
+    reason varchar(1000) NOT NULL,
+    actor_id bigint,
+    created_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_status_changes_user_id ON user_status_changes (user_id);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS role varchar(50) DEFAULT 'user';
+
+UPDATE users SET role = 'admin' WHERE id IN (
+    SELECT ur.user_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE r.name = 'admin'
+);
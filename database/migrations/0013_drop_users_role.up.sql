@@ -0,0 +1,13 @@
+-- Roles now only come from user_roles. Users without an assignment get the
+-- role their legacy role column named, or the default role, before the
+-- column goes.
+INSERT INTO roles (name, created_at, updated_at)
+SELECT DISTINCT COALESCE(NULLIF(role, ''), 'user'), now(), now() FROM users
+UNION SELECT 'user', now(), now()
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO user_roles (user_id, role_id)
+SELECT u.id, r.id FROM users u JOIN roles r ON r.name = COALESCE(NULLIF(u.role, ''), 'user')
+WHERE NOT EXISTS (SELECT 1 FROM user_roles ur WHERE ur.user_id = u.id);
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
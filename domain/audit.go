@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Audited actions.
+const (
+	AuditUserCreate      = "user.create"
+	AuditUserUpdate      = "user.update"
+	AuditUserDelete      = "user.delete"
+	AuditUserRestore     = "user.restore"
+	AuditUserRegister    = "user.register"
+	AuditUserVerifyEmail = "user.verify_email"
+	AuditUserUnlock      = "user.unlock"
+	AuditUserStatus      = "user.status_change"
+	AuditUserRoles       = "user.roles_change"
+	AuditPasswordReset   = "user.password_reset"
+	AuditPasswordChange  = "user.password_change"
+	AuditMFAEnable       = "user.mfa_enable"
+	AuditMFADisable      = "user.mfa_disable"
+	AuditSessionsRevoke  = "user.sessions_revoke"
+	AuditLogin           = "auth.login"
+	AuditLoginFailed     = "auth.login_failed"
+	AuditLoginLocked     = "auth.login_locked"
+	AuditLockout         = "auth.lockout"
+)
+
+// Audited target types.
+const (
+	AuditTargetUser = "user"
+	// AuditTargetLoginKey is an account or client IP logins are counted
+	// for, such as "ip:192.0.2.1".
+	AuditTargetLoginKey = "login_key"
+)
+
+// AuditEvent records who did what to what. Events are chained: Hash covers
+// the event and the hash of the event before it, so changing, removing or
+// reordering stored events breaks the chain from there on.
+type AuditEvent struct {
+	ID uint `gorm:"primary_key"`
+	// ActorID is the authenticated user who acted, nil for anonymous
+	// requests. ActorEmail is their address, or the one a login was
+	// attempted for.
+	ActorID    *uint
+	ActorEmail string `gorm:"size:255"`
+	Action     string `gorm:"size:64;not null"`
+	TargetType string `gorm:"size:64"`
+	TargetID   string `gorm:"size:255"`
+	// Changes is a JSON object mapping each changed field to its "from" and
+	// "to" values, with secrets redacted.
+	Changes   string `gorm:"type:text"`
+	ClientIP  string `gorm:"size:64"`
+	UserAgent string `gorm:"size:512"`
+	RequestID string `gorm:"size:128"`
+	CreatedAt time.Time
+	PrevHash  string `gorm:"size:64"`
+	Hash      string `gorm:"size:64;not null"`
+}
+
+// Seal links the event to the event before it and computes its hash.
+// CreatedAt is rounded to the microsecond the database stores.
+func (e *AuditEvent) Seal(prevHash string) {
+	e.CreatedAt = e.CreatedAt.UTC().Truncate(time.Microsecond)
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash returns the hash the event should have.
+func (e *AuditEvent) ComputeHash() string {
+	var actorID string
+	if e.ActorID != nil {
+		actorID = strconv.FormatUint(uint64(*e.ActorID), 10)
+	}
+	// A JSON array keeps the fields apart whatever they contain.
+	content, _ := json.Marshal([]string{
+		e.PrevHash,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+		actorID,
+		e.ActorEmail,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.Changes,
+		e.ClientIP,
+		e.UserAgent,
+		e.RequestID,
+	})
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditQuery selects a page of audit events, newest first. Zero values mean
+// "no filter". When Cursor is set it takes precedence over Offset.
+type AuditQuery struct {
+	Limit      int
+	Offset     int
+	Cursor     string
+	ActorID    uint
+	Action     string
+	TargetType string
+	TargetID   string
+	From       time.Time
+	To         time.Time
+}
+
+type AuditPage struct {
+	Items      []AuditEvent
+	NextCursor string
+	Total      int64
+}
+
+// AuditVerification is the result of checking the audit event chain.
+type AuditVerification struct {
+	Valid   bool
+	Checked int64
+	// BrokenAt is the ID of the first event whose hash does not match.
+	BrokenAt uint
+}
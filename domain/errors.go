@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a requested item does not exist.
+	ErrNotFound = errors.New("your requested item is not found")
+	// ErrBadParamInput is returned when a request parameter is not valid.
+	ErrBadParamInput = errors.New("given param is not valid")
+	// ErrConflict is returned when an item would clash with an existing one.
+	ErrConflict = errors.New("your item already exists")
+	// ErrUnauthorized is returned when the caller could not be authenticated.
+	ErrUnauthorized = errors.New("authentication failed")
+	// ErrForbidden is returned when an action is not allowed for the caller.
+	ErrForbidden = errors.New("you are not allowed to do this")
+	// ErrEmailNotVerified is returned when an unverified user tries to log in.
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	// ErrAccountInactive is returned when a user who is not active tries to
+	// log in or use a token.
+	ErrAccountInactive = errors.New("account is not active")
+	// ErrTooManyRequests is returned when the caller must wait before trying
+	// again.
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// TooManyRequestsError is returned when the caller must wait RetryAfter
+// before trying again. It matches ErrTooManyRequests.
+type TooManyRequestsError struct {
+	Message    string
+	RetryAfter time.Duration
+}
+
+func (e *TooManyRequestsError) Error() string {
+	if e.Message == "" {
+		return ErrTooManyRequests.Error()
+	}
+	return e.Message
+}
+
+func (e *TooManyRequestsError) Unwrap() error {
+	return ErrTooManyRequests
+}
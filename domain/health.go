@@ -0,0 +1,21 @@
+package domain
+
+const (
+	HealthStatusUp   = "up"
+	HealthStatusDown = "down"
+)
+
+// HealthCheck is the outcome of probing a single dependency.
+type HealthCheck struct {
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthReport is the aggregated result of all registered checks. Status is
+// down if the service is starting or shutting down, or if any check failed.
+type HealthReport struct {
+	Status string                 `json:"status"`
+	Reason string                 `json:"reason,omitempty"`
+	Checks map[string]HealthCheck `json:"checks,omitempty"`
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// UserIdentity links a user to their account at an OpenID Connect provider.
+type UserIdentity struct {
+	ID        uint   `gorm:"primary_key"`
+	UserID    uint   `gorm:"not null;index"`
+	Provider  string `gorm:"size:64;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject   string `gorm:"size:255;not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email     string `gorm:"size:255"`
+	CreatedAt time.Time
+}
+
+// OIDCIdentity is who a provider's verified ID token says the user is.
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// OIDCLogin starts a login at a provider. LoginToken has to be presented
+// again with the provider's answer and binds it to this browser.
+type OIDCLogin struct {
+	AuthURL    string
+	LoginToken string
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// LoginAttempts counts the recent failed logins for one key, an account or a
+// client IP.
+type LoginAttempts struct {
+	Key           string `gorm:"primary_key;size:320"`
+	Failures      int    `gorm:"not null"`
+	LastFailureAt time.Time
+	// LockedUntil is when logins for the key are allowed again.
+	LockedUntil *time.Time
+}
+
+// IsLocked reports whether logins for the key are refused at now.
+func (a *LoginAttempts) IsLocked(now time.Time) bool {
+	return a.LockedUntil != nil && now.Before(*a.LockedUntil)
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// MFA is a user's TOTP enrollment. It is pending until EnabledAt is set by
+// confirming a first code.
+type MFA struct {
+	UserID uint   `gorm:"primary_key;autoIncrement:false"`
+	Secret string `gorm:"size:64;not null"`
+	// LastUsedStep is the TOTP time step of the last accepted code, so a
+	// code can not be used twice.
+	LastUsedStep int64
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (MFA) TableName() string {
+	return "user_mfa"
+}
+
+// IsEnabled reports whether the enrollment has been confirmed.
+func (m *MFA) IsEnabled() bool {
+	return m.EnabledAt != nil
+}
+
+// RecoveryCode can be used once instead of a TOTP code. Only the hash of the
+// code is stored.
+type RecoveryCode struct {
+	ID        uint   `gorm:"primary_key"`
+	UserID    uint   `gorm:"not null;index"`
+	CodeHash  string `gorm:"size:64;not null;uniqueIndex"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// MFAEnrollment is what a user needs to add their secret to an
+// authenticator app.
+type MFAEnrollment struct {
+	Secret string
+	URI    string
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is who a request is made by, as established by its access token.
+type Principal struct {
+	UserID      uint
+	Email       string
+	Roles       []string
+	Permissions []string
+	// SessionID identifies the login the token was issued for. Tokens
+	// refreshed from it share the session ID.
+	SessionID string
+	TokenID   string
+	IssuedAt  time.Time
+	ExpiresAt time.Time
+	// User is the user as currently stored. It is nil when tokens are
+	// validated from their claims alone.
+	User *User
+}
+
+// NewPrincipal returns the principal of user. Roles must be loaded.
+func NewPrincipal(user *User) *Principal {
+	return &Principal{
+		UserID:      user.ID,
+		Email:       user.Email,
+		Roles:       user.RoleNames(),
+		Permissions: user.PermissionNames(),
+		User:        user,
+	}
+}
+
+// HasPermission reports whether the principal was granted permission.
+func (p *Principal) HasPermission(permission string) bool {
+	for _, name := range p.Permissions {
+		if name == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal of its request.
+func WithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// PrincipalFrom returns the principal of ctx, or nil for anonymous requests.
+func PrincipalFrom(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
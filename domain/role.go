@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+const (
+	PermissionUsersRead   = "users:read"
+	PermissionUsersWrite  = "users:write"
+	PermissionUsersDelete = "users:delete"
+	PermissionRolesManage = "roles:manage"
+	PermissionAuditRead   = "audit:read"
+)
+
+// DefaultRoleName is the role every user is given when their account is
+// created.
+const DefaultRoleName = "user"
+
+type Permission struct {
+	ID          uint   `gorm:"primary_key"`
+	Name        string `gorm:"size:100;not null;unique"`
+	Description string `gorm:"size:255"`
+}
+
+type Role struct {
+	ID          uint         `gorm:"primary_key"`
+	Name        string       `gorm:"size:50;not null;unique"`
+	Description string       `gorm:"size:255"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (role *Role) HasPermission(name string) bool {
+	for _, permission := range role.Permissions {
+		if permission.Name == name {
+			return true
+		}
+	}
+	return false
+}
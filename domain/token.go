@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+// RefreshToken is a server-side record of an issued refresh token. Only the
+// SHA-256 hash of the token is stored. Tokens issued from the same login share
+// a FamilyID so that a replayed token can revoke the whole chain.
+type RefreshToken struct {
+	ID        uint      `gorm:"primary_key"`
+	UserID    uint      `gorm:"not null;index"`
+	FamilyID  string    `gorm:"size:64;not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+	CreatedAt time.Time
+}
+
+// AuthTokens is what a successful login or refresh hands back to the client.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresAt    time.Time
+	// MFAToken is set instead of the other fields when the user still has
+	// to pass a second factor. MFAEnrollmentRequired tells them they must
+	// enroll one first.
+	MFAToken              string
+	MFAEnrollmentRequired bool
+}
+
+// TokenRevocation blocks access tokens before they expire. A row with a JTI
+// revokes that single token; a row with IssuedBefore revokes every token of
+// UserID issued before that time. Rows are useless once ExpiresAt has passed
+// and are pruned.
+type TokenRevocation struct {
+	ID           uint   `gorm:"primary_key"`
+	JTI          string `gorm:"size:64;index"`
+	UserID       uint   `gorm:"not null;index"`
+	IssuedBefore *time.Time
+	ExpiresAt    time.Time `gorm:"not null;index"`
+	CreatedAt    time.Time
+}
+
+// PasswordResetToken lets the holder of the emailed token choose a new
+// password once. Only the hash of the token is stored.
+type PasswordResetToken struct {
+	ID        uint      `gorm:"primary_key"`
+	UserID    uint      `gorm:"not null;index"`
+	TokenHash string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
@@ -4,20 +4,80 @@ import (
 	"time"
 
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type User struct {
-	ID        uint   `gorm:"primary_key"`
-	Name      string `gorm:"size:255;not null" faker:"name"`
-	Email     string `gorm:"size:255;unique" faker:"email"`
-	Password  string `gorm:"size:255;not null" faker:"password"`
-	Role      string `gorm:"size:50;default:user"` // "admin" or "user"
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       uint   `gorm:"primary_key"`
+	Name     string `gorm:"size:255;not null" faker:"name"`
+	Email    string `gorm:"size:255;unique" faker:"email"`
+	Password string `gorm:"size:255;not null" faker:"password"`
+	// Status is one of the UserStatus constants.
+	Status string `gorm:"size:20;not null;default:active"`
+	Roles  []Role `gorm:"many2many:user_roles" json:"-"`
+	// EmailVerifiedAt is nil until the user follows their verification link.
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	// DeletedAt is set when the user is deleted. Queries skip deleted users
+	// until they are restored or purged.
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TODO: validation request, tag binding:"required"
 
+// IsPrivileged reports whether the user holds a role other than the default
+// one every user gets. Roles must be loaded.
+func (user *User) IsPrivileged() bool {
+	for _, role := range user.Roles {
+		if role.Name != DefaultRoleName {
+			return true
+		}
+	}
+	return false
+}
+
+// RoleNames returns the names of the user's roles. Roles must be loaded.
+func (user *User) RoleNames() []string {
+	names := make([]string, len(user.Roles))
+	for i, role := range user.Roles {
+		names[i] = role.Name
+	}
+	return names
+}
+
+// HasPermission reports whether any of the user's roles grants permission.
+// Roles must be loaded.
+func (user *User) HasPermission(permission string) bool {
+	for _, role := range user.Roles {
+		if role.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionNames returns the permissions the user's roles grant, each once.
+// Roles must be loaded.
+func (user *User) PermissionNames() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, role := range user.Roles {
+		for _, permission := range role.Permissions {
+			if !seen[permission.Name] {
+				seen[permission.Name] = true
+				names = append(names, permission.Name)
+			}
+		}
+	}
+	return names
+}
+
+// IsVerified reports whether the user has verified their email address.
+func (user *User) IsVerified() bool {
+	return user.EmailVerifiedAt != nil
+}
+
 func (user *User) HashPassword() error {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
 	if err != nil {
@@ -26,3 +86,32 @@ func (user *User) HashPassword() error {
 	user.Password = string(hashedPassword)
 	return nil
 }
+
+// UserSortColumns maps the sort fields accepted by UserQuery to columns.
+var UserSortColumns = map[string]string{
+	"id":        "id",
+	"name":      "name",
+	"email":     "email",
+	"createdAt": "created_at",
+}
+
+// UserQuery selects a page of users. Zero values mean "no filter". When
+// Cursor is set it takes precedence over Offset.
+type UserQuery struct {
+	Limit       int
+	Offset      int
+	Cursor      string
+	Role        string
+	Email       string
+	Name        string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Sort        string
+	Order       string
+}
+
+type UserPage struct {
+	Items      []User
+	NextCursor string
+	Total      int64
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Account statuses. Only active users can log in or use their tokens.
+// Pending users have not verified their email address yet, suspended users
+// were shut out by an administrator, and locked users are held for security
+// reasons such as a suspected account takeover.
+const (
+	UserStatusPending   = "pending"
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusLocked    = "locked"
+)
+
+// UserStatusTransitions lists the statuses each status can change to.
+var UserStatusTransitions = map[string][]string{
+	UserStatusPending:   {UserStatusActive, UserStatusSuspended},
+	UserStatusActive:    {UserStatusSuspended, UserStatusLocked},
+	UserStatusSuspended: {UserStatusActive, UserStatusLocked},
+	UserStatusLocked:    {UserStatusActive, UserStatusSuspended},
+}
+
+// CanChangeUserStatus reports whether a user can go from one status to
+// another.
+func CanChangeUserStatus(from, to string) bool {
+	for _, status := range UserStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// UserStatusChange is one entry in a user's status history.
+type UserStatusChange struct {
+	ID         uint   `gorm:"primary_key"`
+	UserID     uint   `gorm:"not null;index"`
+	FromStatus string `gorm:"size:20;not null"`
+	ToStatus   string `gorm:"size:20;not null"`
+	Reason     string `gorm:"size:1000;not null"`
+	// ActorID is the user who made the change, nil when the system did.
+	ActorID   *uint
+	CreatedAt time.Time
+}
+
+// AccountStatus returns the user's status. A user that has none yet, such
+// as one about to be created, is active.
+func (user *User) AccountStatus() string {
+	if user.Status == "" {
+		return UserStatusActive
+	}
+	return user.Status
+}
+
+// CheckStatus returns an error matching ErrAccountInactive unless the user
+// is active.
+func (user *User) CheckStatus() error {
+	if status := user.AccountStatus(); status != UserStatusActive {
+		return fmt.Errorf("%w (%s)", ErrAccountInactive, status)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/tat-101/bb-assignment-back/database"
+	"gorm.io/gorm"
+)
+
+// Database pings the connection pool behind db.
+func Database(db *gorm.DB) Checker {
+	return CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// Migrations fails while any embedded migration is still pending. Once all
+// of them have been applied the result is remembered and the database is no
+// longer queried.
+func Migrations(db *gorm.DB) Checker {
+	var done atomic.Bool
+	return CheckerFunc(func(ctx context.Context) error {
+		if done.Load() {
+			return nil
+		}
+		migrator, err := database.NewMigrator(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		statuses, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		pending := 0
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				pending++
+			}
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d migration(s) pending", pending)
+		}
+		done.Store(true)
+		return nil
+	})
+}
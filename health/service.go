@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// DefaultTimeout bounds a single check when none is configured.
+const DefaultTimeout = 2 * time.Second
+
+// Checker probes one dependency. It returns an error if the dependency is
+// unusable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to the Checker interface.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type Service struct {
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	checkers map[string]Checker
+
+	ready  atomic.Bool
+	reason atomic.Value
+}
+
+// NewService returns a Service that is not ready until SetReady is called.
+// Each check gets timeout to finish.
+func NewService(timeout time.Duration) *Service {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	s := &Service{
+		timeout:  timeout,
+		checkers: make(map[string]Checker),
+	}
+	s.reason.Store("starting")
+	return s
+}
+
+// Register adds a dependency check to readiness. Registering a name twice
+// replaces the earlier check.
+func (s *Service) Register(name string, checker Checker) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checkers[name] = checker
+}
+
+// SetReady marks the service as able to take traffic, or not. reason is
+// reported while the service is not ready.
+func (s *Service) SetReady(ready bool, reason string) {
+	s.reason.Store(reason)
+	s.ready.Store(ready)
+}
+
+// Ready runs every registered check concurrently and aggregates the results.
+func (s *Service) Ready(ctx context.Context) domain.HealthReport {
+	s.mu.RLock()
+	names := make([]string, 0, len(s.checkers))
+	for name := range s.checkers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	checkers := make([]Checker, len(names))
+	for i, name := range names {
+		checkers[i] = s.checkers[name]
+	}
+	s.mu.RUnlock()
+
+	results := make([]domain.HealthCheck, len(names))
+	var wg sync.WaitGroup
+	for i, checker := range checkers {
+		wg.Add(1)
+		go func(i int, checker Checker) {
+			defer wg.Done()
+			results[i] = s.run(ctx, checker)
+		}(i, checker)
+	}
+	wg.Wait()
+
+	report := domain.HealthReport{
+		Status: domain.HealthStatusUp,
+		Checks: make(map[string]domain.HealthCheck, len(names)),
+	}
+	for i, name := range names {
+		report.Checks[name] = results[i]
+		if results[i].Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+	if !s.ready.Load() {
+		report.Status = domain.HealthStatusDown
+		report.Reason, _ = s.reason.Load().(string)
+	}
+	return report
+}
+
+func (s *Service) run(ctx context.Context, checker Checker) domain.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	errc := make(chan error, 1)
+	go func() { errc <- checker.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-errc:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := domain.HealthCheck{
+		Status:  domain.HealthStatusUp,
+		Latency: time.Since(start).String(),
+	}
+	if err != nil {
+		result.Status = domain.HealthStatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/health"
+)
+
+func ok(ctx context.Context) error { return nil }
+
+func TestReady_NotReadyUntilSet(t *testing.T) {
+	svc := health.NewService(time.Second)
+	svc.Register("database", health.CheckerFunc(ok))
+
+	report := svc.Ready(context.Background())
+
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Equal(t, "starting", report.Reason)
+	assert.Equal(t, domain.HealthStatusUp, report.Checks["database"].Status)
+
+	svc.SetReady(true, "")
+	report = svc.Ready(context.Background())
+
+	assert.Equal(t, domain.HealthStatusUp, report.Status)
+	assert.Empty(t, report.Reason)
+
+	svc.SetReady(false, "shutting down")
+	report = svc.Ready(context.Background())
+
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Equal(t, "shutting down", report.Reason)
+}
+
+func TestReady_FailingCheck(t *testing.T) {
+	svc := health.NewService(time.Second)
+	svc.SetReady(true, "")
+	svc.Register("database", health.CheckerFunc(ok))
+	svc.Register("cache", health.CheckerFunc(func(ctx context.Context) error {
+		return errors.New("connection refused")
+	}))
+
+	report := svc.Ready(context.Background())
+
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Equal(t, domain.HealthStatusUp, report.Checks["database"].Status)
+	assert.Equal(t, domain.HealthStatusDown, report.Checks["cache"].Status)
+	assert.Equal(t, "connection refused", report.Checks["cache"].Error)
+	assert.NotEmpty(t, report.Checks["cache"].Latency)
+}
+
+func TestReady_CheckTimeout(t *testing.T) {
+	svc := health.NewService(50 * time.Millisecond)
+	svc.SetReady(true, "")
+	block := make(chan struct{})
+	defer close(block)
+	svc.Register("slow", health.CheckerFunc(func(ctx context.Context) error {
+		<-block
+		return nil
+	}))
+
+	start := time.Now()
+	report := svc.Ready(context.Background())
+
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), report.Checks["slow"].Error)
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/config"
+	"github.com/tat-101/bb-assignment-back/health"
+	"gorm.io/gorm"
+)
+
+// Worker is a background job that runs until its context is cancelled.
+type Worker func(ctx context.Context)
+
+// ShutdownHook releases a resource once the server has stopped serving.
+type ShutdownHook func(ctx context.Context) error
+
+type shutdownHook struct {
+	name string
+	fn   ShutdownHook
+}
+
+// App is the HTTP server together with the database and the background
+// workers that live and die with it.
+type App struct {
+	Engine *gin.Engine
+	DB     *gorm.DB
+	// Health, if set, is marked not ready as soon as shutdown begins so that
+	// load balancers stop routing new traffic here.
+	Health *health.Service
+
+	cfg     config.Config
+	workers []Worker
+	hooks   []shutdownHook
+}
+
+func NewApp(cfg config.Config, engine *gin.Engine, db *gorm.DB) *App {
+	return &App{
+		Engine: engine,
+		DB:     db,
+		cfg:    cfg,
+	}
+}
+
+// AddWorker registers a job that starts with Run and is stopped during
+// shutdown, after the server has stopped accepting requests.
+func (a *App) AddWorker(worker Worker) {
+	a.workers = append(a.workers, worker)
+}
+
+// OnShutdown registers a hook that runs after the server and the workers
+// have stopped. Hooks run in reverse order of registration, before the
+// database is closed.
+func (a *App) OnShutdown(name string, hook ShutdownHook) {
+	a.hooks = append(a.hooks, shutdownHook{name: name, fn: hook})
+}
+
+// Run listens on the configured address and serves until ctx is cancelled,
+// then shuts down gracefully.
+func (a *App) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", ":"+a.cfg.ServerAddress)
+	if err != nil {
+		return err
+	}
+	return a.Serve(ctx, listener)
+}
+
+// Serve serves on listener until ctx is cancelled. In-flight requests then
+// get ShutdownTimeout to finish before workers are stopped, shutdown hooks
+// run and the database connection pool is closed, in that order.
+func (a *App) Serve(ctx context.Context, listener net.Listener) error {
+	server := &http.Server{
+		Handler:           a.Engine,
+		ReadTimeout:       a.cfg.ReadTimeout,
+		ReadHeaderTimeout: a.cfg.ReadTimeout,
+		WriteTimeout:      a.cfg.WriteTimeout,
+		IdleTimeout:       a.cfg.IdleTimeout,
+	}
+
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	var workers sync.WaitGroup
+	for _, worker := range a.workers {
+		workers.Add(1)
+		go func(worker Worker) {
+			defer workers.Done()
+			worker(workerCtx)
+		}(worker)
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Listening", "address", listener.Addr().String())
+		serveErr <- server.Serve(listener)
+	}()
+
+	var errs []error
+	select {
+	case err := <-serveErr:
+		errs = append(errs, fmt.Errorf("http server: %w", err))
+	case <-ctx.Done():
+		if a.Health != nil {
+			a.Health.SetReady(false, "shutting down")
+		}
+		slog.Info("Shutting down, waiting for in-flight requests", "timeout", a.cfg.ShutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), a.cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		errs = append(errs, fmt.Errorf("http server: %w", err))
+	}
+
+	stopWorkers()
+	workersDone := make(chan struct{})
+	go func() {
+		workers.Wait()
+		close(workersDone)
+	}()
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		errs = append(errs, errors.New("workers: did not stop before the shutdown timeout"))
+	}
+
+	for i := len(a.hooks) - 1; i >= 0; i-- {
+		if err := a.hooks[i].fn(shutdownCtx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", a.hooks[i].name, err))
+		}
+	}
+
+	if a.DB != nil {
+		if sqlDB, err := a.DB.DB(); err == nil {
+			if err := sqlDB.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("database: %w", err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,115 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/config"
+	"github.com/tat-101/bb-assignment-back/health"
+)
+
+func testConfig() config.Config {
+	return config.Config{
+		ReadTimeout:     time.Second,
+		WriteTimeout:    time.Second,
+		IdleTimeout:     time.Second,
+		ShutdownTimeout: 2 * time.Second,
+	}
+}
+
+func TestServe_GracefulShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	started := make(chan struct{})
+	r := gin.New()
+	r.GET("/slow", func(c *gin.Context) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+
+	app := NewApp(testConfig(), r, nil)
+	app.Health = health.NewService(time.Second)
+	app.Health.SetReady(true, "")
+
+	workerStopped := make(chan struct{})
+	app.AddWorker(func(ctx context.Context) {
+		<-ctx.Done()
+		close(workerStopped)
+	})
+	var order []string
+	app.OnShutdown("first", func(ctx context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	app.OnShutdown("second", func(ctx context.Context) error {
+		order = append(order, "second")
+		return nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- app.Serve(ctx, ln) }()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/slow")
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	<-started
+	cancel()
+
+	res := <-responses
+	require.NoError(t, res.err)
+	assert.Equal(t, "done", res.body)
+
+	require.NoError(t, <-served)
+	select {
+	case <-workerStopped:
+	default:
+		t.Fatal("worker was not stopped")
+	}
+	assert.Equal(t, []string{"second", "first"}, order)
+	assert.Equal(t, "shutting down", app.Health.Ready(context.Background()).Reason)
+
+	_, err = http.Get("http://" + ln.Addr().String() + "/slow")
+	assert.Error(t, err)
+}
+
+func TestServe_ShutdownHookErrors(t *testing.T) {
+	app := NewApp(testConfig(), gin.New(), nil)
+	app.OnShutdown("cache", func(ctx context.Context) error {
+		return assert.AnError
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = app.Serve(ctx, ln)
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, "cache")
+}
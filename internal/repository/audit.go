@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+// auditChainLockID is the Postgres advisory lock key held while appending
+// to the audit chain.
+const auditChainLockID = 72404174
+
+type AuditRepository struct {
+	DB *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{DB: db}
+}
+
+func (r *AuditRepository) AppendAuditEvent(ctx context.Context, event *domain.AuditEvent) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLockID).Error; err != nil {
+			return err
+		}
+		var last domain.AuditEvent
+		err := tx.Select("hash").Order("id DESC").Take(&last).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		event.Seal(last.Hash)
+		return tx.Create(event).Error
+	})
+}
+
+func (r *AuditRepository) GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	db := r.DB.WithContext(ctx)
+	page := &domain.AuditPage{}
+	if err := filterAuditEvents(db, query).Model(&domain.AuditEvent{}).Count(&page.Total).Error; err != nil {
+		return nil, err
+	}
+
+	tx := filterAuditEvents(db, query).Order("id DESC").Limit(query.Limit + 1)
+	if query.Cursor != "" {
+		cursor, err := decodeUserCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		tx = tx.Where("id < ?", cursor.ID)
+	} else if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+
+	if err := tx.Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+	if len(page.Items) > query.Limit {
+		page.Items = page.Items[:query.Limit]
+		raw, _ := json.Marshal(userCursor{ID: page.Items[len(page.Items)-1].ID})
+		page.NextCursor = base64.RawURLEncoding.EncodeToString(raw)
+	}
+	return page, nil
+}
+
+func filterAuditEvents(db *gorm.DB, query domain.AuditQuery) *gorm.DB {
+	if query.ActorID != 0 {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.TargetType != "" {
+		db = db.Where("target_type = ?", query.TargetType)
+	}
+	if query.TargetID != "" {
+		db = db.Where("target_id = ?", query.TargetID)
+	}
+	if !query.From.IsZero() {
+		db = db.Where("created_at >= ?", query.From)
+	}
+	if !query.To.IsZero() {
+		db = db.Where("created_at < ?", query.To)
+	}
+	return db
+}
+
+func (r *AuditRepository) ListAuditEventsAfter(ctx context.Context, afterID uint, limit int) ([]domain.AuditEvent, error) {
+	var events []domain.AuditEvent
+	err := r.DB.WithContext(ctx).Where("id > ?", afterID).Order("id").Limit(limit).Find(&events).Error
+	return events, err
+}
@@ -0,0 +1,39 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestAuditRepository_Append(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	auditRepo := repository.NewAuditRepository(db)
+	ctx := context.Background()
+
+	first := &domain.AuditEvent{Action: domain.AuditUserCreate, TargetType: domain.AuditTargetUser, TargetID: "7", CreatedAt: time.Now()}
+	require.NoError(t, auditRepo.AppendAuditEvent(ctx, first))
+	second := &domain.AuditEvent{Action: domain.AuditLogin, TargetType: domain.AuditTargetUser, TargetID: "7", CreatedAt: time.Now()}
+	require.NoError(t, auditRepo.AppendAuditEvent(ctx, second))
+
+	assert.Equal(t, first.Hash, second.PrevHash)
+
+	events, err := auditRepo.ListAuditEventsAfter(ctx, first.ID-1, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, events[1].Hash, events[1].ComputeHash(), "stored events hash the same")
+
+	page, err := auditRepo.GetAuditEvents(ctx, domain.AuditQuery{Limit: 10, Action: domain.AuditLogin})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), page.Total)
+	assert.Equal(t, second.ID, page.Items[0].ID)
+
+	err = db.Model(&domain.AuditEvent{}).Where("id = ?", first.ID).Update("target_id", "8").Error
+	assert.Error(t, err, "events can not be changed")
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type IdentityRepository struct {
+	DB *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) *IdentityRepository {
+	return &IdentityRepository{DB: db}
+}
+
+func (r *IdentityRepository) GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	var identity domain.UserIdentity
+	err := r.DB.WithContext(ctx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *IdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	return r.DB.WithContext(ctx).Create(identity).Error
+}
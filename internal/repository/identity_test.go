@@ -0,0 +1,30 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestIdentityRepository_CreateAndGet(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	identityRepo := repository.NewIdentityRepository(db)
+
+	_, err := identityRepo.GetIdentity(context.Background(), "corp", "subject-1")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	identity := &domain.UserIdentity{UserID: 1, Provider: "corp", Subject: "subject-1", Email: "user@example.com"}
+	require.NoError(t, identityRepo.CreateIdentity(context.Background(), identity))
+
+	dbIdentity, err := identityRepo.GetIdentity(context.Background(), "corp", "subject-1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), dbIdentity.UserID)
+
+	err = identityRepo.CreateIdentity(context.Background(), &domain.UserIdentity{UserID: 2, Provider: "corp", Subject: "subject-1"})
+	assert.Error(t, err, "a provider account can only be linked once")
+}
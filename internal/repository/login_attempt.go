@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type LoginAttemptRepository struct {
+	DB *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{DB: db}
+}
+
+func (r *LoginAttemptRepository) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	var attempts domain.LoginAttempts
+	if err := r.DB.WithContext(ctx).First(&attempts, "key = ?", key).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &attempts, nil
+}
+
+// RecordLoginFailure counts the failure in a single statement, so concurrent
+// failures are all counted.
+func (r *LoginAttemptRepository) RecordLoginFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	var failures int
+	err := r.DB.WithContext(ctx).Raw(`
+		INSERT INTO login_attempts (key, failures, last_failure_at) VALUES (?, 1, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE WHEN login_attempts.last_failure_at < ? THEN 1 ELSE login_attempts.failures + 1 END,
+			last_failure_at = EXCLUDED.last_failure_at
+		RETURNING failures`, key, now, now.Add(-window)).Scan(&failures).Error
+	return failures, err
+}
+
+func (r *LoginAttemptRepository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	return r.DB.WithContext(ctx).Exec(`
+		INSERT INTO login_attempts (key, failures, last_failure_at, locked_until) VALUES (?, 0, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET locked_until = EXCLUDED.locked_until`, key, time.Time{}, until).Error
+}
+
+func (r *LoginAttemptRepository) ResetLoginAttempts(ctx context.Context, key string) error {
+	return r.DB.WithContext(ctx).Where("key = ?", key).Delete(&domain.LoginAttempts{}).Error
+}
+
+func (r *LoginAttemptRepository) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).
+		Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).
+		Delete(&domain.LoginAttempts{})
+	return result.RowsAffected, result.Error
+}
+
+// MemoryLoginAttemptRepository keeps login attempts in memory, for a single
+// instance that does not need them to survive a restart.
+type MemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+}
+
+func NewMemoryLoginAttemptRepository() *MemoryLoginAttemptRepository {
+	return &MemoryLoginAttemptRepository{attempts: map[string]domain.LoginAttempts{}}
+}
+
+func (r *MemoryLoginAttemptRepository) GetLoginAttempts(_ context.Context, key string) (*domain.LoginAttempts, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts, ok := r.attempts[key]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &attempts, nil
+}
+
+func (r *MemoryLoginAttemptRepository) RecordLoginFailure(_ context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts, ok := r.attempts[key]
+	if !ok || attempts.LastFailureAt.Before(now.Add(-window)) {
+		attempts = domain.LoginAttempts{Key: key, LockedUntil: attempts.LockedUntil}
+	}
+	attempts.Failures++
+	attempts.LastFailureAt = now
+	r.attempts[key] = attempts
+	return attempts.Failures, nil
+}
+
+func (r *MemoryLoginAttemptRepository) LockLogin(_ context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	attempts, ok := r.attempts[key]
+	if !ok {
+		attempts = domain.LoginAttempts{Key: key}
+	}
+	attempts.LockedUntil = &until
+	r.attempts[key] = attempts
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) ResetLoginAttempts(_ context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.attempts, key)
+	return nil
+}
+
+func (r *MemoryLoginAttemptRepository) DeleteStaleLoginAttempts(_ context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for key, attempts := range r.attempts {
+		if attempts.LastFailureAt.Before(before) && (attempts.LockedUntil == nil || attempts.LockedUntil.Before(before)) {
+			delete(r.attempts, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"github.com/tat-101/bb-assignment-back/user"
+)
+
+func testLoginAttemptStore(t *testing.T, store user.LoginAttemptStore) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	_, err := store.GetLoginAttempts(ctx, "account:user@example.com")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	for want := 1; want <= 3; want++ {
+		failures, err := store.RecordLoginFailure(ctx, "account:user@example.com", now, time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, want, failures)
+	}
+
+	until := now.Add(15 * time.Minute)
+	require.NoError(t, store.LockLogin(ctx, "account:user@example.com", until))
+	attempts, err := store.GetLoginAttempts(ctx, "account:user@example.com")
+	require.NoError(t, err)
+	assert.True(t, attempts.IsLocked(now))
+	assert.False(t, attempts.IsLocked(until))
+
+	require.NoError(t, store.LockLogin(ctx, "mfa-challenge:abc", until))
+	attempts, err = store.GetLoginAttempts(ctx, "mfa-challenge:abc")
+	require.NoError(t, err)
+	assert.True(t, attempts.IsLocked(now), "a key without failures can be locked")
+
+	failures, err := store.RecordLoginFailure(ctx, "account:user@example.com", now.Add(2*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, failures, "failures outside the window are forgotten")
+
+	_, err = store.RecordLoginFailure(ctx, "ip:192.0.2.1", now, time.Hour)
+	require.NoError(t, err)
+	deleted, err := store.DeleteStaleLoginAttempts(ctx, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	require.NoError(t, store.ResetLoginAttempts(ctx, "account:user@example.com"))
+	_, err = store.GetLoginAttempts(ctx, "account:user@example.com")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestLoginAttemptRepository(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+
+	testLoginAttemptStore(t, repository.NewLoginAttemptRepository(db))
+}
+
+func TestMemoryLoginAttemptRepository(t *testing.T) {
+	testLoginAttemptStore(t, repository.NewMemoryLoginAttemptRepository())
+}
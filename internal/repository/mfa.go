@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type MFARepository struct {
+	DB *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{DB: db}
+}
+
+func (r *MFARepository) GetMFA(ctx context.Context, userID uint) (*domain.MFA, error) {
+	var mfa domain.MFA
+	if err := r.DB.WithContext(ctx).First(&mfa, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *MFARepository) SaveMFA(ctx context.Context, mfa *domain.MFA) error {
+	return r.DB.WithContext(ctx).Save(mfa).Error
+}
+
+// DeleteMFA removes the user's enrollment together with their recovery codes.
+func (r *MFARepository) DeleteMFA(ctx context.Context, userID uint) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&domain.MFA{}).Error
+	})
+}
+
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.MFA{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReplaceRecoveryCodes discards the user's recovery codes and stores hashes
+// as the new ones.
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&domain.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		codes := make([]domain.RecoveryCode, len(hashes))
+		for i, hash := range hashes {
+			codes[i] = domain.RecoveryCode{UserID: userID, CodeHash: hash}
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, hash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
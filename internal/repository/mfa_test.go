@@ -0,0 +1,68 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestMFARepository_SaveAndGet(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	mfaRepo := repository.NewMFARepository(db)
+
+	_, err := mfaRepo.GetMFA(context.Background(), 1)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	require.NoError(t, mfaRepo.SaveMFA(context.Background(), &domain.MFA{UserID: 1, Secret: "SECRET"}))
+
+	mfa, err := mfaRepo.GetMFA(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "SECRET", mfa.Secret)
+	assert.False(t, mfa.IsEnabled())
+}
+
+func TestMFARepository_UseTOTPStep(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	mfaRepo := repository.NewMFARepository(db)
+	require.NoError(t, mfaRepo.SaveMFA(context.Background(), &domain.MFA{UserID: 1, Secret: "SECRET", LastUsedStep: 10}))
+
+	ok, err := mfaRepo.UseTOTPStep(context.Background(), 1, 11)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = mfaRepo.UseTOTPStep(context.Background(), 1, 11)
+	assert.NoError(t, err)
+	assert.False(t, ok, "a code can only be used once")
+}
+
+func TestMFARepository_RecoveryCodes(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	mfaRepo := repository.NewMFARepository(db)
+	require.NoError(t, mfaRepo.SaveMFA(context.Background(), &domain.MFA{UserID: 1, Secret: "SECRET"}))
+	require.NoError(t, mfaRepo.ReplaceRecoveryCodes(context.Background(), 1, []string{"old"}))
+	require.NoError(t, mfaRepo.ReplaceRecoveryCodes(context.Background(), 1, []string{"new-1", "new-2"}))
+
+	ok, err := mfaRepo.UseRecoveryCode(context.Background(), 1, "old")
+	assert.NoError(t, err)
+	assert.False(t, ok, "replaced codes no longer work")
+
+	ok, err = mfaRepo.UseRecoveryCode(context.Background(), 1, "new-1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = mfaRepo.UseRecoveryCode(context.Background(), 1, "new-1")
+	assert.NoError(t, err)
+	assert.False(t, ok, "a code can only be used once")
+
+	require.NoError(t, mfaRepo.DeleteMFA(context.Background(), 1))
+	var remaining int64
+	require.NoError(t, db.Model(&domain.RecoveryCode{}).Where("user_id = ?", 1).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type PasswordResetTokenRepository struct {
+	DB *gorm.DB
+}
+
+func NewPasswordResetTokenRepository(db *gorm.DB) *PasswordResetTokenRepository {
+	return &PasswordResetTokenRepository{DB: db}
+}
+
+func (r *PasswordResetTokenRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	return r.DB.WithContext(ctx).Create(token).Error
+}
+
+func (r *PasswordResetTokenRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	if err := r.DB.WithContext(ctx).Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *PasswordResetTokenRepository) MarkPasswordResetTokenUsed(ctx context.Context, id uint) (bool, error) {
+	result := r.DB.WithContext(ctx).Model(&domain.PasswordResetToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *PasswordResetTokenRepository) InvalidatePasswordResetTokens(ctx context.Context, userID uint) error {
+	return r.DB.WithContext(ctx).Model(&domain.PasswordResetToken{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Update("used_at", time.Now()).Error
+}
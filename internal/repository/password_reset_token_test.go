@@ -0,0 +1,52 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestPasswordResetTokenRepository_MarkPasswordResetTokenUsed(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	tokenRepo := repository.NewPasswordResetTokenRepository(db)
+
+	token := &domain.PasswordResetToken{UserID: 1, TokenHash: "reset-1", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, tokenRepo.CreatePasswordResetToken(context.Background(), token))
+
+	dbToken, err := tokenRepo.GetPasswordResetTokenByHash(context.Background(), "reset-1")
+	require.NoError(t, err)
+	assert.Equal(t, token.ID, dbToken.ID)
+
+	ok, err := tokenRepo.MarkPasswordResetTokenUsed(context.Background(), token.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = tokenRepo.MarkPasswordResetTokenUsed(context.Background(), token.ID)
+	assert.NoError(t, err)
+	assert.False(t, ok, "a token can only be used once")
+}
+
+func TestPasswordResetTokenRepository_InvalidatePasswordResetTokens(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	tokenRepo := repository.NewPasswordResetTokenRepository(db)
+
+	for _, hash := range []string{"reset-2", "reset-3"} {
+		token := &domain.PasswordResetToken{UserID: 2, TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, tokenRepo.CreatePasswordResetToken(context.Background(), token))
+	}
+
+	require.NoError(t, tokenRepo.InvalidatePasswordResetTokens(context.Background(), 2))
+
+	for _, hash := range []string{"reset-2", "reset-3"} {
+		dbToken, err := tokenRepo.GetPasswordResetTokenByHash(context.Background(), hash)
+		assert.NoError(t, err)
+		assert.NotNil(t, dbToken.UsedAt)
+	}
+}
@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestRefreshTokenRepository_CreateAndGet(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	tokenRepo := repository.NewRefreshTokenRepository(db)
+
+	token := &domain.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash-1", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, tokenRepo.CreateRefreshToken(context.Background(), token))
+
+	dbToken, err := tokenRepo.GetRefreshTokenByHash(context.Background(), "hash-1")
+
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, dbToken.ID)
+	assert.Nil(t, dbToken.UsedAt)
+}
+
+func TestRefreshTokenRepository_MarkRefreshTokenUsed(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	tokenRepo := repository.NewRefreshTokenRepository(db)
+
+	token := &domain.RefreshToken{UserID: 1, FamilyID: "family", TokenHash: "hash-2", ExpiresAt: time.Now().Add(time.Hour)}
+	require.NoError(t, tokenRepo.CreateRefreshToken(context.Background(), token))
+
+	ok, err := tokenRepo.MarkRefreshTokenUsed(context.Background(), token.ID)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = tokenRepo.MarkRefreshTokenUsed(context.Background(), token.ID)
+	assert.NoError(t, err)
+	assert.False(t, ok, "a token can only be used once")
+}
+
+func TestRefreshTokenRepository_RevokeRefreshTokenFamily(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	tokenRepo := repository.NewRefreshTokenRepository(db)
+
+	for _, hash := range []string{"hash-3", "hash-4"} {
+		token := &domain.RefreshToken{UserID: 1, FamilyID: "stolen", TokenHash: hash, ExpiresAt: time.Now().Add(time.Hour)}
+		require.NoError(t, tokenRepo.CreateRefreshToken(context.Background(), token))
+	}
+
+	require.NoError(t, tokenRepo.RevokeRefreshTokenFamily(context.Background(), "stolen"))
+
+	for _, hash := range []string{"hash-3", "hash-4"} {
+		dbToken, err := tokenRepo.GetRefreshTokenByHash(context.Background(), hash)
+		assert.NoError(t, err)
+		assert.NotNil(t, dbToken.RevokedAt)
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type RoleRepository struct {
+	DB *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) *RoleRepository {
+	return &RoleRepository{DB: db}
+}
+
+func (r *RoleRepository) GetAllRoles(ctx context.Context) ([]domain.Role, error) {
+	var roles []domain.Role
+	err := r.DB.WithContext(ctx).Preload("Permissions").Order("name").Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) GetRolesByNames(ctx context.Context, names []string) ([]domain.Role, error) {
+	var roles []domain.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	err := r.DB.WithContext(ctx).Where("name IN ?", names).Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) CreateRole(ctx context.Context, role *domain.Role) error {
+	return r.DB.WithContext(ctx).Create(role).Error
+}
+
+func (r *RoleRepository) GetAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	err := r.DB.WithContext(ctx).Order("name").Find(&permissions).Error
+	return permissions, err
+}
+
+func (r *RoleRepository) GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error) {
+	var permissions []domain.Permission
+	if len(names) == 0 {
+		return permissions, nil
+	}
+	err := r.DB.WithContext(ctx).Where("name IN ?", names).Find(&permissions).Error
+	return permissions, err
+}
+
+// GetUserRoles returns the roles of a user, without their permissions.
+func (r *RoleRepository) GetUserRoles(ctx context.Context, userID uint) ([]domain.Role, error) {
+	var roles []domain.Role
+	err := r.DB.WithContext(ctx).
+		Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error
+	return roles, err
+}
+
+func (r *RoleRepository) SetUserRoles(ctx context.Context, userID uint, roles []domain.Role) error {
+	db := r.DB.WithContext(ctx)
+	var user domain.User
+	if err := db.First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return domain.ErrNotFound
+		}
+		return err
+	}
+	return db.Model(&user).Association("Roles").Replace(roles)
+}
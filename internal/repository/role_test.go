@@ -0,0 +1,64 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestRoleRepository_CreateRole(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	roleRepo := repository.NewRoleRepository(db)
+
+	permission := domain.Permission{Name: "test:read"}
+	require.NoError(t, db.Create(&permission).Error)
+
+	role := &domain.Role{Name: "test-role", Permissions: []domain.Permission{permission}}
+	err := roleRepo.CreateRole(context.Background(), role)
+	assert.NoError(t, err)
+
+	roles, err := roleRepo.GetRolesByNames(context.Background(), []string{"test-role"})
+	assert.NoError(t, err)
+	assert.Len(t, roles, 1)
+}
+
+func TestRoleRepository_SetUserRoles(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	roleRepo := repository.NewRoleRepository(db)
+	userRepo := repository.NewUserRepository(db)
+
+	permission := domain.Permission{Name: "test:write"}
+	require.NoError(t, db.Create(&permission).Error)
+	role := &domain.Role{Name: "test-writer", Permissions: []domain.Permission{permission}}
+	require.NoError(t, roleRepo.CreateRole(context.Background(), role))
+
+	user := domain.User{Email: "roles@example.com", Name: "Role User"}
+	require.NoError(t, userRepo.CreateUser(context.Background(), &user))
+
+	err := roleRepo.SetUserRoles(context.Background(), user.ID, []domain.Role{*role})
+	assert.NoError(t, err)
+
+	roles, err := roleRepo.GetUserRoles(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"test-writer"}, (&domain.User{Roles: roles}).RoleNames())
+
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
+	assert.NoError(t, err)
+	assert.True(t, dbUser.HasPermission("test:write"))
+}
+
+func TestRoleRepository_SetUserRoles_UserNotFound(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	roleRepo := repository.NewRoleRepository(db)
+
+	err := roleRepo.SetUserRoles(context.Background(), 0, nil)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
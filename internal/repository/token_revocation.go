@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/gorm"
+)
+
+type TokenRevocationRepository struct {
+	DB *gorm.DB
+}
+
+func NewTokenRevocationRepository(db *gorm.DB) *TokenRevocationRepository {
+	return &TokenRevocationRepository{DB: db}
+}
+
+func (r *TokenRevocationRepository) RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	return r.DB.WithContext(ctx).Create(&domain.TokenRevocation{
+		JTI:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}).Error
+}
+
+// RevokeUserTokens rounds issuedBefore up to the next second, as the iat of
+// the tokens it is compared with has whole seconds only: every token issued
+// in the same second is revoked, including one issued just after the call.
+func (r *TokenRevocationRepository) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore, expiresAt time.Time) error {
+	issuedBefore = issuedBefore.Truncate(time.Second).Add(time.Second)
+	return r.DB.WithContext(ctx).Create(&domain.TokenRevocation{
+		UserID:       userID,
+		IssuedBefore: &issuedBefore,
+		ExpiresAt:    expiresAt,
+	}).Error
+}
+
+func (r *TokenRevocationRepository) IsTokenRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	db := r.DB.WithContext(ctx)
+	var count int64
+	match := db.Where("user_id = ? AND issued_before > ?", userID, issuedAt)
+	if jti != "" {
+		match = match.Or("jti = ?", jti)
+	}
+	err := db.Model(&domain.TokenRevocation{}).
+		Where("expires_at > ?", time.Now()).
+		Where(match).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *TokenRevocationRepository) DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).Where("expires_at <= ?", now).Delete(&domain.TokenRevocation{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,84 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+)
+
+func TestTokenRevocationRepository_RevokeToken(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+
+	issuedAt := time.Now().Add(-time.Minute)
+	require.NoError(t, revocationRepo.RevokeToken(context.Background(), "jti-1", 1, time.Now().Add(time.Hour)))
+
+	revoked, err := revocationRepo.IsTokenRevoked(context.Background(), "jti-1", 1, issuedAt)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = revocationRepo.IsTokenRevoked(context.Background(), "jti-2", 1, issuedAt)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationRepository_RevokeUserTokens(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+
+	now := time.Now()
+	require.NoError(t, revocationRepo.RevokeUserTokens(context.Background(), 2, now, now.Add(time.Hour)))
+
+	revoked, err := revocationRepo.IsTokenRevoked(context.Background(), "old", 2, now.Add(-time.Minute))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = revocationRepo.IsTokenRevoked(context.Background(), "new", 2, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+}
+
+func TestTokenRevocationRepository_RevokeUserTokens_SameSecond(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+
+	// iat only has whole seconds: a token issued earlier in the second of
+	// the revocation carries that second.
+	iat := time.Now().Truncate(time.Second)
+	revokedAt := iat.Add(999 * time.Millisecond)
+	require.NoError(t, revocationRepo.RevokeUserTokens(context.Background(), 4, revokedAt, revokedAt.Add(time.Hour)))
+
+	revoked, err := revocationRepo.IsTokenRevoked(context.Background(), "same-second", 4, iat)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = revocationRepo.IsTokenRevoked(context.Background(), "next-second", 4, iat.Add(time.Second))
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestTokenRevocationRepository_DeleteExpiredRevocations(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+
+	require.NoError(t, revocationRepo.RevokeToken(context.Background(), "expired", 3, time.Now().Add(-time.Minute)))
+	require.NoError(t, revocationRepo.RevokeToken(context.Background(), "live", 3, time.Now().Add(time.Hour)))
+
+	pruned, err := revocationRepo.DeleteExpiredRevocations(context.Background(), time.Now())
+
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, pruned, int64(1))
+
+	revoked, err := revocationRepo.IsTokenRevoked(context.Background(), "live", 3, time.Now())
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+}
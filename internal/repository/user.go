@@ -1,8 +1,16 @@
 package repository
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/tat-101/bb-assignment-back/domain"
-	"github.com/tat-101/bb-assignment-back/tools"
 	"gorm.io/gorm"
 )
 
@@ -14,48 +22,282 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{DB: db}
 }
 
-func (r *UserRepository) CreateUser(user *domain.User) error {
-	return r.DB.Create(user).Error
+// CreateUser creates user with the default role, in the same transaction,
+// unless user.Roles names other roles.
+func (r *UserRepository) CreateUser(ctx context.Context, user *domain.User) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// A deleted user keeps their address until they are purged.
+		var deleted int64
+		if err := tx.Unscoped().Model(&domain.User{}).
+			Where("email = ? AND deleted_at IS NOT NULL", user.Email).
+			Count(&deleted).Error; err != nil {
+			return err
+		}
+		if deleted > 0 {
+			return fmt.Errorf("%w: email belongs to a deleted user", domain.ErrConflict)
+		}
+
+		if len(user.Roles) == 0 {
+			var role domain.Role
+			if err := tx.Preload("Permissions").Where("name = ?", domain.DefaultRoleName).First(&role).Error; err != nil {
+				return fmt.Errorf("default role %q: %w", domain.DefaultRoleName, err)
+			}
+			user.Roles = []domain.Role{role}
+		}
+		return tx.Omit("Roles.*").Create(user).Error
+	})
 }
 
-func (r *UserRepository) GetAllUsers() ([]domain.User, error) {
-	var users []domain.User
-	err := r.DB.Order("id desc").Find(&users).Error
-	return users, err
+// userCursor points at the last user of a page. Value holds that user's sort
+// column so the next page can continue with a keyset condition.
+type userCursor struct {
+	Value string `json:"v,omitempty"`
+	ID    uint   `json:"id"`
 }
 
-func (r *UserRepository) GetUserByID(id uint) (*domain.User, error) {
-	var user domain.User
-	if err := r.DB.First(&user, id).Error; err != nil {
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (r *UserRepository) GetAllUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	db := r.DB.WithContext(ctx)
+	column, ok := domain.UserSortColumns[query.Sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid sort field %q", domain.ErrBadParamInput, query.Sort)
+	}
+
+	page := &domain.UserPage{}
+	if err := filterUsers(db, query).Model(&domain.User{}).Count(&page.Total).Error; err != nil {
 		return nil, err
 	}
-	return &user, nil
+
+	tx := filterUsers(db, query).
+		Order(fmt.Sprintf("%s %s, id %s", column, query.Order, query.Order)).
+		Limit(query.Limit + 1)
+
+	if query.Cursor != "" {
+		cursor, err := decodeUserCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		op := ">"
+		if query.Order == "desc" {
+			op = "<"
+		}
+		if column == "id" {
+			tx = tx.Where("id "+op+" ?", cursor.ID)
+		} else {
+			value, err := cursorValue(column, cursor.Value)
+			if err != nil {
+				return nil, err
+			}
+			tx = tx.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, op), value, cursor.ID)
+		}
+	} else if query.Offset > 0 {
+		tx = tx.Offset(query.Offset)
+	}
+
+	if err := tx.Find(&page.Items).Error; err != nil {
+		return nil, err
+	}
+
+	if len(page.Items) > query.Limit {
+		page.Items = page.Items[:query.Limit]
+		page.NextCursor = encodeUserCursor(column, page.Items[len(page.Items)-1])
+	}
+
+	return page, nil
+}
+
+func filterUsers(db *gorm.DB, query domain.UserQuery) *gorm.DB {
+	if query.Role != "" {
+		db = db.Where(`id IN (SELECT ur.user_id FROM user_roles ur JOIN roles r ON r.id = ur.role_id WHERE r.name = ?)`, query.Role)
+	}
+	if query.Email != "" {
+		db = db.Where("email ILIKE ?", "%"+likeEscaper.Replace(query.Email)+"%")
+	}
+	if query.Name != "" {
+		db = db.Where("name ILIKE ?", "%"+likeEscaper.Replace(query.Name)+"%")
+	}
+	if !query.CreatedFrom.IsZero() {
+		db = db.Where("created_at >= ?", query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		db = db.Where("created_at < ?", query.CreatedTo)
+	}
+	return db
 }
 
-func (r *UserRepository) GetUserByEmail(email string) (*domain.User, error) {
+func encodeUserCursor(column string, user domain.User) string {
+	cursor := userCursor{ID: user.ID}
+	switch column {
+	case "name":
+		cursor.Value = user.Name
+	case "email":
+		cursor.Value = user.Email
+	case "created_at":
+		cursor.Value = user.CreatedAt.Format(time.RFC3339Nano)
+	}
+	raw, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeUserCursor(encoded string) (*userCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", domain.ErrBadParamInput)
+	}
+	var cursor userCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", domain.ErrBadParamInput)
+	}
+	return &cursor, nil
+}
+
+func cursorValue(column, value string) (interface{}, error) {
+	if column != "created_at" {
+		return value, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", domain.ErrBadParamInput)
+	}
+	return t, nil
+}
+
+func (r *UserRepository) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
 	var user domain.User
-	if err := r.DB.Where("email = ?", email).First(&user).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Roles.Permissions").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, domain.ErrNotFound
+		}
 		return nil, err
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error) {
+func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
 	var user domain.User
-	if err := r.DB.First(&user, id).Error; err != nil {
+	if err := r.DB.WithContext(ctx).Preload("Roles.Permissions").Where("email = ?", email).First(&user).Error; err != nil {
 		return nil, err
 	}
-	user.Name = tools.Coalesce(updatedUser.Name, user.Name)
+	return &user, nil
+}
+
+// UpdateUserByID sets the name and password of updatedUser that are not
+// empty. Only those columns are written, so changes made to the user's other
+// columns meanwhile, such as their status, are kept.
+func (r *UserRepository) UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (*domain.User, error) {
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid user ID %q", domain.ErrBadParamInput, id)
+	}
+
+	updates := map[string]interface{}{}
+	if updatedUser.Name != "" {
+		updates["name"] = updatedUser.Name
+	}
 	if updatedUser.Password != "" {
-		user.Password = updatedUser.Password
-		if err := user.HashPassword(); err != nil {
+		if err := updatedUser.HashPassword(); err != nil {
 			return nil, err
 		}
+		updates["password"] = updatedUser.Password
 	}
+	if len(updates) > 0 {
+		result := r.DB.WithContext(ctx).Model(&domain.User{}).Where("id = ?", userID).Updates(updates)
+		if result.Error != nil {
+			return nil, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil, domain.ErrNotFound
+		}
+	}
+	return r.GetUserByID(ctx, uint(userID))
+}
 
-	return &user, r.DB.Save(&user).Error
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	result := r.DB.WithContext(ctx).Model(&domain.User{}).
+		Where("id = ? AND email_verified_at IS NULL", id).
+		Update("email_verified_at", time.Now())
+	return result.Error
 }
 
-func (r *UserRepository) DeleteUserByID(id string) error {
-	return r.DB.Delete(&domain.User{}, id).Error
+// ChangeUserStatus moves the user from change.FromStatus to change.ToStatus
+// and adds change to their history. It returns domain.ErrConflict when the
+// user's status is no longer change.FromStatus.
+func (r *UserRepository) ChangeUserStatus(ctx context.Context, change *domain.UserStatusChange) error {
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.User{}).
+			Where("id = ? AND status = ?", change.UserID, change.FromStatus).
+			Update("status", change.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: the user's status has changed", domain.ErrConflict)
+		}
+		return tx.Create(change).Error
+	})
+}
+
+// GetUserStatusHistory returns the user's status changes, newest first.
+func (r *UserRepository) GetUserStatusHistory(ctx context.Context, userID uint) ([]domain.UserStatusChange, error) {
+	var changes []domain.UserStatusChange
+	err := r.DB.WithContext(ctx).Where("user_id = ?", userID).Order("id desc").Find(&changes).Error
+	return changes, err
+}
+
+// DeleteUserByID soft deletes the user.
+func (r *UserRepository) DeleteUserByID(ctx context.Context, id uint) error {
+	result := r.DB.WithContext(ctx).Delete(&domain.User{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *UserRepository) RestoreUserByID(ctx context.Context, id uint) error {
+	result := r.DB.WithContext(ctx).Unscoped().Model(&domain.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// userTables are the tables without a foreign key that hold rows for a
+// user, which go when the user is purged.
+var userTables = []string{
+	"refresh_tokens", "password_reset_tokens", "recovery_codes", "user_mfa", "user_identities", "user_status_changes",
+}
+
+// PurgeDeletedUsers removes users deleted before deletedBefore for good,
+// with everything stored for them.
+func (r *UserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Unscoped().Model(&domain.User{}).
+			Where("deleted_at < ?", deletedBefore).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		for _, table := range userTables {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id IN ?", ids).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Unscoped().Delete(&domain.User{}, ids)
+		purged = result.RowsAffected
+		return result.Error
+	})
+	return purged, err
 }
@@ -1,8 +1,10 @@
 package repository_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -10,14 +12,16 @@ import (
 	"github.com/tat-101/bb-assignment-back/database"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
 func setupTestDB(t *testing.T) (*gorm.DB, func()) {
-	cfg := config.LoadConfig()
+	cfg, err := config.Load(nil)
+	require.NoError(t, err)
 	db := database.Initialize(cfg)
 
-	err := db.AutoMigrate(&domain.User{})
+	err = database.Migrate(db)
 	require.NoError(t, err)
 
 	tx := db.Begin()
@@ -38,14 +42,15 @@ func TestUserRepository_CreateUser(t *testing.T) {
 		Password: "password123",
 	}
 
-	err := userRepo.CreateUser(user)
+	err := userRepo.CreateUser(context.Background(), user)
 
 	assert.NoError(t, err)
 
 	var dbUser domain.User
-	err = db.First(&dbUser, user.ID).Error
+	err = db.Preload("Roles").First(&dbUser, user.ID).Error
 	assert.NoError(t, err)
 	assert.Equal(t, user.Email, dbUser.Email)
+	assert.Equal(t, []string{domain.DefaultRoleName}, dbUser.RoleNames(), "new users get the default role")
 }
 
 func TestUserRepository_GetAllUsers(t *testing.T) {
@@ -53,8 +58,10 @@ func TestUserRepository_GetAllUsers(t *testing.T) {
 	defer teardown()
 	userRepo := repository.NewUserRepository(db)
 
+	query := domain.UserQuery{Limit: 100, Sort: "id", Order: "desc"}
+
 	// previuos add
-	before, _ := userRepo.GetAllUsers()
+	before, _ := userRepo.GetAllUsers(context.Background(), query)
 
 	users := []domain.User{
 		{Email: "user1@example.com", Name: "User One"},
@@ -62,14 +69,64 @@ func TestUserRepository_GetAllUsers(t *testing.T) {
 	}
 
 	for _, user := range users {
-		err := userRepo.CreateUser(&user)
+		err := userRepo.CreateUser(context.Background(), &user)
 		require.NoError(t, err)
 	}
 
-	dbUsers, err := userRepo.GetAllUsers()
+	page, err := userRepo.GetAllUsers(context.Background(), query)
+
+	assert.NoError(t, err)
+	assert.Equal(t, before.Total+2, page.Total)
+}
+
+func TestUserRepository_GetAllUsers_Filters(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+	role := &domain.Role{Name: "filter-admin"}
+	require.NoError(t, repository.NewRoleRepository(db).CreateRole(context.Background(), role))
+
+	users := []domain.User{
+		{Email: "filter_admin@example.com", Name: "Filter Admin", Roles: []domain.Role{*role}},
+		{Email: "filter_user@example.com", Name: "Filter User"},
+	}
+	for _, user := range users {
+		require.NoError(t, userRepo.CreateUser(context.Background(), &user))
+	}
+
+	page, err := userRepo.GetAllUsers(context.Background(), domain.UserQuery{Limit: 10, Email: "filter_", Role: "filter-admin", Sort: "id", Order: "asc"})
 
 	assert.NoError(t, err)
-	assert.Len(t, dbUsers, len(before)+2)
+	assert.Equal(t, int64(1), page.Total)
+	require.Len(t, page.Items, 1)
+	assert.Equal(t, "filter_admin@example.com", page.Items[0].Email)
+}
+
+func TestUserRepository_GetAllUsers_Cursor(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+
+	for _, name := range []string{"Cursor C", "Cursor A", "Cursor B"} {
+		user := domain.User{Email: name + "@cursor.example.com", Name: name}
+		require.NoError(t, userRepo.CreateUser(context.Background(), &user))
+	}
+
+	query := domain.UserQuery{Limit: 2, Name: "Cursor", Sort: "name", Order: "asc"}
+	first, err := userRepo.GetAllUsers(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, first.Items, 2)
+	assert.Equal(t, "Cursor A", first.Items[0].Name)
+	assert.Equal(t, "Cursor B", first.Items[1].Name)
+	assert.NotEmpty(t, first.NextCursor)
+
+	query.Cursor = first.NextCursor
+	second, err := userRepo.GetAllUsers(context.Background(), query)
+	require.NoError(t, err)
+	require.Len(t, second.Items, 1)
+	assert.Equal(t, "Cursor C", second.Items[0].Name)
+	assert.Empty(t, second.NextCursor)
+	assert.Equal(t, int64(3), second.Total)
 }
 
 func TestUserRepository_GetUserByID(t *testing.T) {
@@ -78,10 +135,10 @@ func TestUserRepository_GetUserByID(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	user := domain.User{Email: "test@example.com", Name: "Test User"}
-	err := userRepo.CreateUser(&user)
+	err := userRepo.CreateUser(context.Background(), &user)
 	require.NoError(t, err)
 
-	dbUser, err := userRepo.GetUserByID(user.ID)
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
 
 	assert.NoError(t, err)
 	assert.Equal(t, user.Email, dbUser.Email)
@@ -93,10 +150,10 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	user := domain.User{Email: "test@example.com", Name: "Test User"}
-	err := userRepo.CreateUser(&user)
+	err := userRepo.CreateUser(context.Background(), &user)
 	require.NoError(t, err)
 
-	dbUser, err := userRepo.GetUserByEmail(user.Email)
+	dbUser, err := userRepo.GetUserByEmail(context.Background(), user.Email)
 
 	assert.NoError(t, err)
 	assert.Equal(t, user.Email, dbUser.Email)
@@ -108,17 +165,23 @@ func TestUserRepository_UpdateUserByID(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	user := domain.User{Email: "test@example.com", Name: "Test User", Password: "password123"}
-	err := userRepo.CreateUser(&user)
+	err := userRepo.CreateUser(context.Background(), &user)
 	require.NoError(t, err)
 
 	updatedUser := domain.User{Name: "Updated User", Password: "newpassword123"}
 
-	us, err := userRepo.UpdateUserByID(fmt.Sprint(user.ID), updatedUser)
+	us, err := userRepo.UpdateUserByID(context.Background(), fmt.Sprint(user.ID), updatedUser)
 	assert.NoError(t, err)
 
-	dbUser, err := userRepo.GetUserByID(user.ID)
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
 	assert.NoError(t, err)
 	assert.Equal(t, us.Name, dbUser.Name)
+	assert.NoError(t, bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte("newpassword123")))
+
+	_, err = userRepo.UpdateUserByID(context.Background(), "999999", updatedUser)
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = userRepo.UpdateUserByID(context.Background(), "abc", updatedUser)
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
 }
 
 func TestUserRepository_DeleteUserByID(t *testing.T) {
@@ -127,12 +190,113 @@ func TestUserRepository_DeleteUserByID(t *testing.T) {
 	userRepo := repository.NewUserRepository(db)
 
 	user := domain.User{Email: "test@example.com", Name: "Test User"}
-	err := userRepo.CreateUser(&user)
+	err := userRepo.CreateUser(context.Background(), &user)
 	require.NoError(t, err)
 
-	err = userRepo.DeleteUserByID(fmt.Sprint(user.ID))
+	err = userRepo.DeleteUserByID(context.Background(), user.ID)
 	assert.NoError(t, err)
 
-	_, err = userRepo.GetUserByID(user.ID)
+	_, err = userRepo.GetUserByID(context.Background(), user.ID)
 	assert.Error(t, err) // Should return an error because the user has been deleted
+	_, err = userRepo.GetUserByEmail(context.Background(), user.Email)
+	assert.Error(t, err)
+	page, err := userRepo.GetAllUsers(context.Background(), domain.UserQuery{Limit: 10, Sort: "id", Order: "asc", Email: user.Email})
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	assert.Zero(t, page.Total)
+
+	assert.ErrorIs(t, userRepo.DeleteUserByID(context.Background(), user.ID), domain.ErrNotFound)
+	assert.ErrorIs(t, userRepo.CreateUser(context.Background(), &domain.User{Email: user.Email, Name: "Again"}), domain.ErrConflict)
+}
+
+func TestUserRepository_RestoreUserByID(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+
+	user := domain.User{Email: "restore@example.com", Name: "Restored"}
+	require.NoError(t, userRepo.CreateUser(context.Background(), &user))
+
+	assert.ErrorIs(t, userRepo.RestoreUserByID(context.Background(), user.ID), domain.ErrNotFound, "the user is not deleted")
+
+	require.NoError(t, userRepo.DeleteUserByID(context.Background(), user.ID))
+	require.NoError(t, userRepo.RestoreUserByID(context.Background(), user.ID))
+
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Restored", dbUser.Name)
+}
+
+func TestUserRepository_PurgeDeletedUsers(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+
+	old := domain.User{Email: "old@example.com", Name: "Old"}
+	recent := domain.User{Email: "recent@example.com", Name: "Recent"}
+	active := domain.User{Email: "active@example.com", Name: "Active"}
+	for _, user := range []*domain.User{&old, &recent, &active} {
+		require.NoError(t, userRepo.CreateUser(context.Background(), user))
+	}
+	require.NoError(t, userRepo.DeleteUserByID(context.Background(), recent.ID))
+	require.NoError(t, db.Model(&domain.User{}).Where("id = ?", old.ID).
+		Update("deleted_at", time.Now().Add(-48*time.Hour)).Error)
+	require.NoError(t, db.Create(&domain.RefreshToken{UserID: old.ID, TokenHash: "purged", FamilyID: "f", ExpiresAt: time.Now().Add(time.Hour)}).Error)
+
+	purged, err := userRepo.PurgeDeletedUsers(context.Background(), time.Now().Add(-24*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var tokens int64
+	require.NoError(t, db.Model(&domain.RefreshToken{}).Where("user_id = ?", old.ID).Count(&tokens).Error)
+	assert.Zero(t, tokens)
+	assert.ErrorIs(t, userRepo.RestoreUserByID(context.Background(), old.ID), domain.ErrNotFound)
+	require.NoError(t, userRepo.RestoreUserByID(context.Background(), recent.ID))
+
+	// The address is free again.
+	assert.NoError(t, userRepo.CreateUser(context.Background(), &domain.User{Email: old.Email, Name: "New"}))
+}
+
+func TestUserRepository_ChangeUserStatus(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+
+	user := domain.User{Email: "status@example.com", Name: "Status"}
+	require.NoError(t, userRepo.CreateUser(context.Background(), &user))
+
+	actorID := uint(1)
+	require.NoError(t, userRepo.ChangeUserStatus(context.Background(), &domain.UserStatusChange{
+		UserID: user.ID, FromStatus: domain.UserStatusActive, ToStatus: domain.UserStatusSuspended, Reason: "Spam", ActorID: &actorID,
+	}))
+	err := userRepo.ChangeUserStatus(context.Background(), &domain.UserStatusChange{
+		UserID: user.ID, FromStatus: domain.UserStatusActive, ToStatus: domain.UserStatusLocked, Reason: "Stale",
+	})
+	assert.ErrorIs(t, err, domain.ErrConflict, "the user is no longer active")
+
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusSuspended, dbUser.Status)
+
+	history, err := userRepo.GetUserStatusHistory(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "Spam", history[0].Reason)
+	assert.Equal(t, &actorID, history[0].ActorID)
+}
+
+func TestUserRepository_MarkEmailVerified(t *testing.T) {
+	db, teardown := setupTestDB(t)
+	defer teardown()
+	userRepo := repository.NewUserRepository(db)
+
+	user := &domain.User{Email: "unverified@example.com", Name: "Unverified", Password: "password123"}
+	require.NoError(t, userRepo.CreateUser(context.Background(), user))
+
+	err := userRepo.MarkEmailVerified(context.Background(), user.ID)
+	assert.NoError(t, err)
+
+	dbUser, err := userRepo.GetUserByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	assert.True(t, dbUser.IsVerified())
 }
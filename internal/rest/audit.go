@@ -0,0 +1,67 @@
+package rest
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/dto"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+)
+
+type AuditHandler struct {
+	Service service.AuditService
+}
+
+type ListAuditEventsQuery struct {
+	Limit      int       `form:"limit" binding:"omitempty,min=1"`
+	Offset     int       `form:"offset" binding:"omitempty,min=0"`
+	Cursor     string    `form:"cursor"`
+	ActorID    uint      `form:"actorId"`
+	Action     string    `form:"action"`
+	TargetType string    `form:"targetType"`
+	TargetID   string    `form:"targetId"`
+	From       time.Time `form:"from"`
+	To         time.Time `form:"to"`
+}
+
+func NewAuditHandler(r *gin.Engine, svc service.AuditService, userSvc service.UserService) {
+	handler := &AuditHandler{
+		Service: svc,
+	}
+
+	auditRoutes := r.Group("/audit",
+		middleware.AuthMiddleware(userSvc),
+		middleware.RequirePermission(domain.PermissionAuditRead))
+	{
+		auditRoutes.GET("", handler.GetAuditEvents)
+		auditRoutes.GET("/verify", handler.VerifyAuditLog)
+	}
+}
+
+func (h *AuditHandler) GetAuditEvents(c *gin.Context) {
+	var query ListAuditEventsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.Service.GetAuditEvents(c.Request.Context(), domain.AuditQuery(query))
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromAuditPage(page))
+}
+
+// VerifyAuditLog checks that no audit event was changed or removed.
+func (h *AuditHandler) VerifyAuditLog(c *gin.Context) {
+	result, err := h.Service.Verify(c.Request.Context())
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromAuditVerification(result))
+}
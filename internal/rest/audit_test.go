@@ -0,0 +1,107 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+)
+
+func TestAuditHandler_GetAuditEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuditService := new(mocks.AuditService)
+	auditHandler := rest.AuditHandler{Service: mockAuditService}
+
+	actorID := uint(1)
+	createdAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockAuditService.On("GetAuditEvents", mock.Anything, domain.AuditQuery{
+		Limit:      10,
+		Action:     domain.AuditUserUpdate,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   "7",
+		From:       time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+	}).Return(&domain.AuditPage{
+		Items: []domain.AuditEvent{{
+			ID:         3,
+			ActorID:    &actorID,
+			ActorEmail: "admin@example.com",
+			Action:     domain.AuditUserUpdate,
+			TargetType: domain.AuditTargetUser,
+			TargetID:   "7",
+			Changes:    `{"name":{"from":"Old","to":"New"}}`,
+			CreatedAt:  createdAt,
+			Hash:       "abc",
+		}},
+		Total: 1,
+	}, nil)
+
+	router := gin.Default()
+	router.GET("/audit", auditHandler.GetAuditEvents)
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit?limit=10&action=user.update&targetType=user&targetId=7&from=2024-05-01T00:00:00Z", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[{"id":3,"actorId":1,"actorEmail":"admin@example.com","action":"user.update",
+		"targetType":"user","targetId":"7","changes":{"name":{"from":"Old","to":"New"}},
+		"createdAt":"2024-05-01T12:00:00Z","hash":"abc"}],"total":1}`, w.Body.String())
+	mockAuditService.AssertExpectations(t)
+}
+
+func TestAuditHandler_GetAuditEvents_BadQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuditService := new(mocks.AuditService)
+	auditHandler := rest.AuditHandler{Service: mockAuditService}
+
+	router := gin.Default()
+	router.GET("/audit", auditHandler.GetAuditEvents)
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit?from=yesterday", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockAuditService.AssertNotCalled(t, "GetAuditEvents", mock.Anything, mock.Anything)
+}
+
+func TestNewAuditHandler_RequiresPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockAuditService := new(mocks.AuditService)
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("ValidateToken", mock.Anything, "auditor").
+		Return(&domain.Principal{UserID: 1, Permissions: []string{domain.PermissionAuditRead}}, nil)
+	mockUserService.On("ValidateToken", mock.Anything, "user").
+		Return(&domain.Principal{UserID: 2, Permissions: []string{domain.PermissionUsersRead}}, nil)
+	mockAuditService.On("Verify", mock.Anything).
+		Return(&domain.AuditVerification{Valid: false, Checked: 4, BrokenAt: 4}, nil)
+
+	router := gin.New()
+	rest.NewAuditHandler(router, mockAuditService, mockUserService)
+
+	verify := func(token string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodGet, "/audit/verify", nil)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusForbidden, verify("user").Code)
+	w := verify("auditor")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"valid":false,"checked":4,"brokenAt":4}`, w.Body.String())
+	mockAuditService.AssertNumberOfCalls(t, "Verify", 1)
+}
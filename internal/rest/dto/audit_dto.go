@@ -0,0 +1,76 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+type AuditEventDTO struct {
+	ID         uint            `json:"id"`
+	ActorID    *uint           `json:"actorId"`
+	ActorEmail string          `json:"actorEmail,omitempty"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"targetType,omitempty"`
+	TargetID   string          `json:"targetId,omitempty"`
+	Changes    json.RawMessage `json:"changes,omitempty"`
+	ClientIP   string          `json:"clientIp,omitempty"`
+	UserAgent  string          `json:"userAgent,omitempty"`
+	RequestID  string          `json:"requestId,omitempty"`
+	CreatedAt  time.Time       `json:"createdAt"`
+	Hash       string          `json:"hash"`
+}
+
+func FromAuditEventEntity(event *domain.AuditEvent) AuditEventDTO {
+	var changes json.RawMessage
+	if event.Changes != "" {
+		changes = json.RawMessage(event.Changes)
+	}
+	return AuditEventDTO{
+		ID:         event.ID,
+		ActorID:    event.ActorID,
+		ActorEmail: event.ActorEmail,
+		Action:     event.Action,
+		TargetType: event.TargetType,
+		TargetID:   event.TargetID,
+		Changes:    changes,
+		ClientIP:   event.ClientIP,
+		UserAgent:  event.UserAgent,
+		RequestID:  event.RequestID,
+		CreatedAt:  event.CreatedAt,
+		Hash:       event.Hash,
+	}
+}
+
+type AuditPageDTO struct {
+	Items      []AuditEventDTO `json:"items"`
+	NextCursor string          `json:"nextCursor,omitempty"`
+	Total      int64           `json:"total"`
+}
+
+func FromAuditPage(page *domain.AuditPage) AuditPageDTO {
+	items := make([]AuditEventDTO, len(page.Items))
+	for i, event := range page.Items {
+		items[i] = FromAuditEventEntity(&event)
+	}
+	return AuditPageDTO{
+		Items:      items,
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
+
+type AuditVerificationDTO struct {
+	Valid    bool  `json:"valid"`
+	Checked  int64 `json:"checked"`
+	BrokenAt uint  `json:"brokenAt,omitempty"`
+}
+
+func FromAuditVerification(result *domain.AuditVerification) AuditVerificationDTO {
+	return AuditVerificationDTO{
+		Valid:    result.Valid,
+		Checked:  result.Checked,
+		BrokenAt: result.BrokenAt,
+	}
+}
@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+type AuthTokensDTO struct {
+	Token        string    `json:"token"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+func FromAuthTokens(tokens *domain.AuthTokens) AuthTokensDTO {
+	return AuthTokensDTO{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresAt:    tokens.ExpiresAt,
+	}
+}
+
+// MFAChallengeDTO answers a login that needs a second factor.
+type MFAChallengeDTO struct {
+	MFARequired        bool   `json:"mfaRequired"`
+	MFAToken           string `json:"mfaToken"`
+	EnrollmentRequired bool   `json:"enrollmentRequired,omitempty"`
+}
+
+func FromMFAChallenge(tokens *domain.AuthTokens) MFAChallengeDTO {
+	return MFAChallengeDTO{
+		MFARequired:        true,
+		MFAToken:           tokens.MFAToken,
+		EnrollmentRequired: tokens.MFAEnrollmentRequired,
+	}
+}
+
+type MFAEnrollmentDTO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+func FromMFAEnrollment(enrollment *domain.MFAEnrollment) MFAEnrollmentDTO {
+	return MFAEnrollmentDTO{
+		Secret: enrollment.Secret,
+		URI:    enrollment.URI,
+	}
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
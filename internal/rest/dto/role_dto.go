@@ -0,0 +1,44 @@
+package dto
+
+import "github.com/tat-101/bb-assignment-back/domain"
+
+type RoleDTO struct {
+	ID          uint     `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type PermissionDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func FromRoleEntity(role *domain.Role) RoleDTO {
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Name
+	}
+	return RoleDTO{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+	}
+}
+
+func FromRoleEntities(roles []domain.Role) []RoleDTO {
+	roleDTOs := make([]RoleDTO, len(roles))
+	for i, role := range roles {
+		roleDTOs[i] = FromRoleEntity(&role)
+	}
+	return roleDTOs
+}
+
+func FromPermissionEntities(permissions []domain.Permission) []PermissionDTO {
+	permissionDTOs := make([]PermissionDTO, len(permissions))
+	for i, permission := range permissions {
+		permissionDTOs[i] = PermissionDTO{Name: permission.Name, Description: permission.Description}
+	}
+	return permissionDTOs
+}
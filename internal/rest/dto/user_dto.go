@@ -10,6 +10,7 @@ type UserDTO struct {
 	ID        uint      `json:"id"`
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
+	Status    string    `json:"status"`
 	CreatedAt time.Time `json:"createdAt"`
 }
 
@@ -18,6 +19,7 @@ func FromUserEntity(user *domain.User) UserDTO {
 		ID:        user.ID,
 		Email:     user.Email,
 		Name:      user.Name,
+		Status:    user.AccountStatus(),
 		CreatedAt: user.CreatedAt,
 	}
 }
@@ -29,3 +31,68 @@ func FromUserEntities(users []domain.User) []UserDTO {
 	}
 	return userDTOs
 }
+
+type UserPageDTO struct {
+	Items      []UserDTO `json:"items"`
+	NextCursor string    `json:"nextCursor,omitempty"`
+	Total      int64     `json:"total"`
+}
+
+func FromUserPage(page *domain.UserPage) UserPageDTO {
+	return UserPageDTO{
+		Items:      FromUserEntities(page.Items),
+		NextCursor: page.NextCursor,
+		Total:      page.Total,
+	}
+}
+
+type UserStatusChangeDTO struct {
+	ID         uint      `json:"id"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Reason     string    `json:"reason"`
+	ActorID    *uint     `json:"actorId"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func FromUserStatusChanges(changes []domain.UserStatusChange) []UserStatusChangeDTO {
+	changeDTOs := make([]UserStatusChangeDTO, len(changes))
+	for i, change := range changes {
+		changeDTOs[i] = UserStatusChangeDTO{
+			ID:         change.ID,
+			FromStatus: change.FromStatus,
+			ToStatus:   change.ToStatus,
+			Reason:     change.Reason,
+			ActorID:    change.ActorID,
+			CreatedAt:  change.CreatedAt,
+		}
+	}
+	return changeDTOs
+}
+
+// PrincipalDTO is the authenticated user. Name and Status are only known
+// when tokens are validated against the database.
+type PrincipalDTO struct {
+	ID          uint     `json:"id"`
+	Email       string   `json:"email"`
+	Name        string   `json:"name,omitempty"`
+	Roles       []string `json:"roles"`
+	Permissions []string `json:"permissions"`
+	Status      string   `json:"status,omitempty"`
+	SessionID   string   `json:"sessionId,omitempty"`
+}
+
+func FromPrincipal(principal *domain.Principal) PrincipalDTO {
+	principalDTO := PrincipalDTO{
+		ID:          principal.UserID,
+		Email:       principal.Email,
+		Roles:       append([]string{}, principal.Roles...),
+		Permissions: append([]string{}, principal.Permissions...),
+		SessionID:   principal.SessionID,
+	}
+	if principal.User != nil {
+		principalDTO.Name = principal.User.Name
+		principalDTO.Status = principal.User.AccountStatus()
+	}
+	return principalDTO
+}
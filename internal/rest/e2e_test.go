@@ -1,6 +1,7 @@
 package rest_test
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
@@ -11,22 +12,29 @@ import (
 
 	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/config"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/internal"
 	"github.com/tat-101/bb-assignment-back/tools/seed/seed"
 )
 
 func TestMain(m *testing.M) {
+	cfg, err := config.Load(nil)
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	cfg.ServerAddress = "8080"
 	go func() {
-		r := internal.SetupServer()
-		if err := r.Run(":8080"); err != nil {
+		app := internal.SetupServer(cfg)
+		if err := app.Run(context.Background()); err != nil {
 			log.Fatalf("Failed to run server: %v", err)
 		}
 	}()
 
 	time.Sleep(2 * time.Second)
 
-	seed.SeedAdminUser()
+	seed.SeedAdminUser(cfg)
 
 	code := m.Run()
 
@@ -43,6 +51,21 @@ func TestGetVersion(t *testing.T) {
 	assert.JSONEq(t, `{"version":"v0"}`, string(resp.Body()))
 }
 
+func TestReadyz(t *testing.T) {
+	client := resty.New()
+
+	resp, err := client.R().Get("http://localhost:8080/readyz")
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	var report domain.HealthReport
+	assert.NoError(t, json.Unmarshal(resp.Body(), &report))
+	assert.Equal(t, domain.HealthStatusUp, report.Status)
+	assert.Equal(t, domain.HealthStatusUp, report.Checks["database"].Status)
+	assert.Equal(t, domain.HealthStatusUp, report.Checks["migrations"].Status)
+}
+
 func TestLogin_Fail(t *testing.T) {
 	client := resty.New()
 
@@ -83,20 +106,83 @@ func TestLogin_Success(t *testing.T) {
 	os.Setenv("TOKEN", token)
 }
 
+func TestRefreshToken_Rotation(t *testing.T) {
+	client := resty.New()
+
+	var login map[string]string
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"email": "admin@bb.com", "password": "123456"}`).
+		SetResult(&login).
+		Post("http://localhost:8080/auth/login")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.NotEmpty(t, login["refreshToken"])
+
+	var refreshed map[string]string
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"refreshToken": login["refreshToken"]}).
+		SetResult(&refreshed).
+		Post("http://localhost:8080/auth/refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.NotEmpty(t, refreshed["token"])
+
+	// Replaying the spent token fails and revokes the rotated one as well.
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"refreshToken": login["refreshToken"]}).
+		Post("http://localhost:8080/auth/refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(map[string]string{"refreshToken": refreshed["refreshToken"]}).
+		Post("http://localhost:8080/auth/refresh")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+}
+
+func TestLogout_RevokesToken(t *testing.T) {
+	client := resty.New()
+
+	var login map[string]string
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"email": "admin@bb.com", "password": "123456"}`).
+		SetResult(&login).
+		Post("http://localhost:8080/auth/login")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().SetHeader("Authorization", login["token"]).Post("http://localhost:8080/auth/logout")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode())
+
+	resp, err = client.R().SetHeader("Authorization", login["token"]).Get("http://localhost:8080/users")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+}
+
 func TestGetUsers(t *testing.T) {
 	client := resty.New()
 
 	token := os.Getenv("TOKEN")
 	// fmt.Println("Token:", token)
 
-	resp, err := client.R().SetHeader("Authorization", token).Get("http://localhost:8080/users")
+	resp, err := client.R().SetHeader("Authorization", token).Get("http://localhost:8080/users?email=admin@bb.com")
 	body := string(resp.Body())
 
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, resp.StatusCode())
-	var users []domain.User
-	err = json.Unmarshal([]byte(body), &users)
+	var page struct {
+		Items []domain.User `json:"items"`
+	}
+	err = json.Unmarshal([]byte(body), &page)
 	assert.NoError(t, err)
+	users := page.Items
 
 	assert.GreaterOrEqual(t, len(users), 1, "The length of users should be at least 1")
 
@@ -155,13 +241,16 @@ func TestCreateUser_Success(t *testing.T) {
 	assert.Equal(t, http.StatusCreated, resp.StatusCode())
 
 	// Verify that the user exists in the list of users
-	usersResp, err := client.R().SetHeader("Authorization", token).Get("http://localhost:8080/users")
+	usersResp, err := client.R().SetHeader("Authorization", token).Get("http://localhost:8080/users?email=test_no1@example.com")
 	assert.NoError(t, err)
 	assert.Equal(t, http.StatusOK, usersResp.StatusCode())
 
-	var users []domain.User
-	err = json.Unmarshal([]byte(usersResp.Body()), &users)
+	var page struct {
+		Items []domain.User `json:"items"`
+	}
+	err = json.Unmarshal([]byte(usersResp.Body()), &page)
 	assert.NoError(t, err)
+	users := page.Items
 
 	found := false
 	for _, user := range users {
@@ -204,6 +293,28 @@ func TestUpdateUser(t *testing.T) {
 	assert.Equal(t, "updated_name", user.Name)
 }
 
+func TestUpdateUser_OtherUserForbidden(t *testing.T) {
+	client := resty.New()
+
+	var login map[string]string
+	resp, err := client.R().
+		SetHeader("Content-Type", "application/json").
+		SetBody(`{"email": "test_no1@example.com", "password": "123456"}`).
+		SetResult(&login).
+		Post("http://localhost:8080/auth/login")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+	resp, err = client.R().
+		SetHeader("Content-Type", "application/json").
+		SetHeader("Authorization", login["token"]).
+		SetBody(`{"name": "not_admin"}`).
+		Put("http://localhost:8080/users/" + os.Getenv("ADMIN_ID"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode())
+}
+
 func TestDeleteUser(t *testing.T) {
 	client := resty.New()
 
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+)
+
+type HealthHandler struct {
+	Service service.HealthService
+}
+
+func NewHealthHandler(r *gin.Engine, svc service.HealthService) {
+	handler := &HealthHandler{
+		Service: svc,
+	}
+
+	r.GET("/healthz", handler.Live)
+	r.GET("/readyz", handler.Ready)
+}
+
+// Live reports that the process is running. It never touches dependencies so
+// a slow database does not get the process restarted.
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": domain.HealthStatusUp})
+}
+
+// Ready reports whether the service can take traffic.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	report := h.Service.Ready(c.Request.Context())
+	status := http.StatusOK
+	if report.Status != domain.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -0,0 +1,67 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+)
+
+func TestHealthHandler_Live(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockHealthService := new(mocks.HealthService)
+	router := gin.Default()
+	rest.NewHealthHandler(router, mockHealthService)
+
+	req, _ := http.NewRequest(http.MethodGet, "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up"}`, w.Body.String())
+	mockHealthService.AssertNotCalled(t, "Ready", mock.Anything)
+}
+
+func TestHealthHandler_Ready(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockHealthService := new(mocks.HealthService)
+	router := gin.Default()
+	rest.NewHealthHandler(router, mockHealthService)
+
+	mockHealthService.On("Ready", mock.Anything).Return(domain.HealthReport{
+		Status: domain.HealthStatusUp,
+		Checks: map[string]domain.HealthCheck{
+			"database": {Status: domain.HealthStatusUp, Latency: "1ms"},
+		},
+	}).Once()
+
+	req, _ := http.NewRequest(http.MethodGet, "/readyz", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"up","checks":{"database":{"status":"up","latency":"1ms"}}}`, w.Body.String())
+
+	mockHealthService.On("Ready", mock.Anything).Return(domain.HealthReport{
+		Status: domain.HealthStatusDown,
+		Reason: "shutting down",
+	}).Once()
+
+	req, _ = http.NewRequest(http.MethodGet, "/readyz", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.JSONEq(t, `{"status":"down","reason":"shutting down"}`, w.Body.String())
+
+	mockHealthService.AssertExpectations(t)
+}
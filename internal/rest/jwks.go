@@ -0,0 +1,28 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+)
+
+type JWKSHandler struct {
+	Service service.KeyService
+}
+
+func NewJWKSHandler(r *gin.Engine, svc service.KeyService) {
+	handler := &JWKSHandler{
+		Service: svc,
+	}
+
+	r.GET("/.well-known/jwks.json", handler.JWKS)
+}
+
+// JWKS publishes the public keys access tokens are signed with, so other
+// services can verify them. Verifiers may cache the set for a few minutes;
+// a new signing key is published before it is used.
+func (h *JWKSHandler) JWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, h.Service.JWKS())
+}
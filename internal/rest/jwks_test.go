@@ -0,0 +1,36 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+	"github.com/tat-101/bb-assignment-back/tools"
+)
+
+func TestJWKSHandler_JWKS(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockKeyService := new(mocks.KeyService)
+	router := gin.Default()
+	rest.NewJWKSHandler(router, mockKeyService)
+
+	mockKeyService.On("JWKS").Return(tools.JWKS{Keys: []map[string]string{{
+		"kty": "OKP", "crv": "Ed25519", "x": "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+		"kid": "kid-1", "alg": "EdDSA", "use": "sig",
+	}}})
+
+	req, _ := http.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"keys":[{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+		"kid":"kid-1","alg":"EdDSA","use":"sig"}]}`, w.Body.String())
+}
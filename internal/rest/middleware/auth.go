@@ -6,8 +6,26 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+	"github.com/tat-101/bb-assignment-back/logging"
 )
 
+const principalKey = "principal"
+
+// SetPrincipal authenticates the request as principal.
+func SetPrincipal(c *gin.Context, principal *domain.Principal) {
+	logging.SetUserID(c.Request.Context(), principal.UserID)
+	c.Request = c.Request.WithContext(domain.WithPrincipal(c.Request.Context(), principal))
+	c.Set(principalKey, principal)
+}
+
+// CurrentPrincipal returns the principal stored in the context by
+// AuthMiddleware.
+func CurrentPrincipal(c *gin.Context) (*domain.Principal, bool) {
+	value, _ := c.Get(principalKey)
+	principal, ok := value.(*domain.Principal)
+	return principal, ok
+}
+
 func AuthMiddleware(svc service.UserService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		token := c.GetHeader("Authorization")
@@ -17,35 +35,49 @@ func AuthMiddleware(svc service.UserService) gin.HandlerFunc {
 			return
 		}
 
-		// TODO: improve cache
-		user, err := svc.ValidateToken(token)
+		principal, err := svc.ValidateToken(c.Request.Context(), token)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
-		c.Set("user", user)
+		SetPrincipal(c, principal)
 		c.Next()
 	}
 }
 
-func AdminMiddleware() gin.HandlerFunc {
+// MFATokenHeader carries the enrollment token a login hands out to users who
+// must enroll in MFA before they get an access token.
+const MFATokenHeader = "X-MFA-Token"
+
+// MFAEnrollmentAuth authenticates the user with an MFA enrollment token when
+// one is sent, and like AuthMiddleware otherwise.
+func MFAEnrollmentAuth(svc service.UserService) gin.HandlerFunc {
+	auth := AuthMiddleware(svc)
 	return func(c *gin.Context) {
-		user, exists := c.Get("user")
-		if !exists {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found in context"})
-			c.Abort()
+		token := c.GetHeader(MFATokenHeader)
+		if token == "" {
+			auth(c)
 			return
 		}
 
-		userStruct, ok := user.(*domain.User)
-		if !ok || userStruct.Role != "admin" {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied, admin role required"})
+		user, err := svc.ValidateMFAEnrollmentToken(c.Request.Context(), token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 			c.Abort()
 			return
 		}
 
+		SetPrincipal(c, domain.NewPrincipal(user))
 		c.Next()
 	}
 }
+
+// RequirePermission only lets users through whose roles grant permission.
+// It must run after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return Authorize(func(actor *domain.Principal, c *gin.Context) bool {
+		return actor.HasPermission(permission)
+	})
+}
@@ -0,0 +1,79 @@
+package middleware_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+)
+
+func TestRequirePermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(actor *domain.User) int {
+		router := gin.New()
+		router.DELETE("/users/:id", func(c *gin.Context) {
+			middleware.SetPrincipal(c, domain.NewPrincipal(actor))
+		}, middleware.RequirePermission(domain.PermissionUsersDelete), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+
+		req, _ := http.NewRequest(http.MethodDelete, "/users/1", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	admin := &domain.User{Roles: []domain.Role{
+		{Name: "admin", Permissions: []domain.Permission{{Name: domain.PermissionUsersDelete}}},
+	}}
+	user := &domain.User{Roles: []domain.Role{
+		{Name: "user", Permissions: []domain.Permission{{Name: domain.PermissionUsersRead}}},
+	}}
+
+	assert.Equal(t, http.StatusNoContent, serve(admin))
+	assert.Equal(t, http.StatusForbidden, serve(user))
+}
+
+func TestMFAEnrollmentAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	svc := new(mocks.UserService)
+	svc.On("ValidateMFAEnrollmentToken", mock.Anything, "enrollment").Return(&domain.User{ID: 1}, nil)
+	svc.On("ValidateMFAEnrollmentToken", mock.Anything, mock.Anything).Return(nil, errors.New("invalid token"))
+	svc.On("ValidateToken", mock.Anything, "access").Return(&domain.Principal{UserID: 2}, nil)
+
+	router := gin.New()
+	router.POST("/auth/mfa/enroll", middleware.MFAEnrollmentAuth(svc), func(c *gin.Context) {
+		actor, _ := middleware.CurrentPrincipal(c)
+		c.JSON(http.StatusOK, gin.H{"id": actor.UserID})
+	})
+
+	serve := func(header, value string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve(middleware.MFATokenHeader, "enrollment")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":1}`, w.Body.String())
+
+	w = serve("Authorization", "access")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":2}`, w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, serve(middleware.MFATokenHeader, "forged").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve("", "").Code)
+}
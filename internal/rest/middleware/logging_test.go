@@ -0,0 +1,110 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/mock"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+	"github.com/tat-101/bb-assignment-back/logging"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen string
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.GET("/", func(c *gin.Context) {
+		seen = logging.RequestID(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "abc-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "abc-123", seen)
+	assert.Equal(t, "abc-123", w.Header().Get(middleware.RequestIDHeader))
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(middleware.RequestIDHeader, "bad id\n"+strings.Repeat("x", 200))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.NotEmpty(t, seen)
+	assert.NotContains(t, seen, " ")
+	assert.Equal(t, seen, w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestRequestInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var seen domain.RequestInfo
+	router := gin.New()
+	router.Use(middleware.RequestInfo())
+	router.GET("/", func(c *gin.Context) {
+		seen = domain.RequestInfoFrom(c.Request.Context())
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.1:4321"
+	req.Header.Set("User-Agent", "test-agent")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, domain.RequestInfo{ClientIP: "192.0.2.1", UserAgent: "test-agent"}, seen)
+}
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("ValidateToken", mock.Anything, "token").Return(&domain.Principal{UserID: 7}, nil)
+
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.RequestLogger(logger), middleware.Recovery(logger))
+	router.GET("/users/:id", middleware.AuthMiddleware(mockUserService), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users/7", nil)
+	req.Header.Set("Authorization", "token")
+	req.Header.Set(middleware.RequestIDHeader, "req-7")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "request", record["msg"])
+	assert.Equal(t, "/users/:id", record["route"])
+	assert.Equal(t, "/users/7", record["path"])
+	assert.Equal(t, float64(http.StatusOK), record["status"])
+	assert.Equal(t, "req-7", record["request_id"])
+	assert.Equal(t, float64(7), record["user_id"])
+
+	buf.Reset()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"msg":"panic recovered"`)
+	assert.Contains(t, lines[0], `"error":"boom"`)
+	assert.Contains(t, lines[1], `"level":"ERROR"`)
+}
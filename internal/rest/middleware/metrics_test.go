@@ -0,0 +1,46 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+)
+
+type observation struct {
+	method, route string
+	status        int
+}
+
+type fakeHTTPMetrics struct {
+	observed []observation
+}
+
+func (f *fakeHTTPMetrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	f.observed = append(f.observed, observation{method, route, status})
+}
+
+func TestMetrics_UsesRouteTemplate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := &fakeHTTPMetrics{}
+	router := gin.New()
+	router.Use(middleware.Metrics(m))
+	router.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/nope"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, []observation{
+		{http.MethodGet, "/users/:id", http.StatusNoContent},
+		{http.MethodGet, "/users/:id", http.StatusNoContent},
+		{http.MethodGet, "unmatched", http.StatusNotFound},
+	}, m.observed)
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// Policy decides whether the authenticated principal may perform the
+// request.
+type Policy func(actor *domain.Principal, c *gin.Context) bool
+
+// Authorize only lets the request through when policy allows the
+// authenticated user. It must run after AuthMiddleware.
+func Authorize(policy Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := CurrentPrincipal(c)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "User not found in context"})
+			c.Abort()
+			return
+		}
+
+		if !policy(actor, c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// SelfOrAdmin allows users with the users:write permission, and users acting
+// on their own account as identified by the user ID in the named path
+// parameter.
+func SelfOrAdmin(param string) Policy {
+	return func(actor *domain.Principal, c *gin.Context) bool {
+		targetID, err := strconv.ParseUint(c.Param(param), 10, 32)
+		if err != nil {
+			return actor.HasPermission(domain.PermissionUsersWrite)
+		}
+		return CanModifyUser(actor, uint(targetID))
+	}
+}
+
+// CanModifyUser reports whether actor may change the account targetID.
+func CanModifyUser(actor *domain.Principal, targetID uint) bool {
+	return actor.UserID == targetID || actor.HasPermission(domain.PermissionUsersWrite)
+}
+
+// CanChangePassword reports whether actor may set a new password for the
+// account targetID without knowing the current one. Only users with
+// users:write may, and not for themselves: their own password is changed
+// through POST /me/password, which checks the current password.
+func CanChangePassword(actor *domain.Principal, targetID uint) bool {
+	return actor.UserID != targetID && actor.HasPermission(domain.PermissionUsersWrite)
+}
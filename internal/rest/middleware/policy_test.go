@@ -0,0 +1,67 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+)
+
+func serveAs(actor *domain.User, path string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.PUT("/users/:id", func(c *gin.Context) {
+		middleware.SetPrincipal(c, domain.NewPrincipal(actor))
+	}, middleware.Authorize(middleware.SelfOrAdmin("id")), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, path, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestSelfOrAdmin(t *testing.T) {
+	user := &domain.User{ID: 10}
+	admin := &domain.User{ID: 1, Roles: []domain.Role{
+		{Name: "admin", Permissions: []domain.Permission{{Name: domain.PermissionUsersWrite}}},
+	}}
+
+	assert.Equal(t, http.StatusOK, serveAs(user, "/users/10").Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(user, "/users/11").Code)
+	assert.Equal(t, http.StatusForbidden, serveAs(user, "/users/abc").Code)
+	assert.Equal(t, http.StatusOK, serveAs(admin, "/users/11").Code)
+}
+
+func TestAuthorize_WithoutUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/", middleware.Authorize(middleware.SelfOrAdmin("id")), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
+func TestCanChangePassword(t *testing.T) {
+	user := &domain.User{ID: 10}
+	admin := &domain.User{ID: 1, Roles: []domain.Role{
+		{Name: "admin", Permissions: []domain.Permission{{Name: domain.PermissionUsersWrite}}},
+	}}
+
+	assert.False(t, middleware.CanChangePassword(domain.NewPrincipal(user), 10))
+	assert.False(t, middleware.CanChangePassword(domain.NewPrincipal(user), 11))
+	assert.True(t, middleware.CanChangePassword(domain.NewPrincipal(admin), 11))
+	assert.False(t, middleware.CanChangePassword(domain.NewPrincipal(admin), 1))
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+// RateLimitKey names the bucket a request counts against.
+type RateLimitKey func(c *gin.Context) string
+
+// KeyByIP counts requests per client IP.
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser counts requests per authenticated user, and per client IP before
+// authentication.
+func KeyByUser(c *gin.Context) string {
+	if principal, ok := CurrentPrincipal(c); ok {
+		return "user:" + strconv.FormatUint(uint64(principal.UserID), 10)
+	}
+	return KeyByIP(c)
+}
+
+// KeyByAPIKey counts requests per X-API-Key header, and per client IP when
+// there is none. The key is not checked, so only use it where a gateway in
+// front has verified it.
+func KeyByAPIKey(c *gin.Context) string {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		return KeyByIP(c)
+	}
+	sum := sha256.Sum256([]byte(key))
+	return "api-key:" + hex.EncodeToString(sum[:])
+}
+
+// RateLimitRule is a limit and how to key it.
+type RateLimitRule struct {
+	Limit ratelimit.Limit
+	Key   RateLimitKey
+}
+
+// RateLimit refuses requests over rule's limit with 429 and a Retry-After
+// header. Every response carries RateLimit-Limit, RateLimit-Remaining and
+// RateLimit-Reset headers. name keeps the buckets of different rules apart.
+// When the limiter fails the request is let through.
+func RateLimit(limiter ratelimit.Limiter, name string, rule RateLimitRule) gin.HandlerFunc {
+	if limiter == nil || rule.Limit.IsZero() {
+		return func(c *gin.Context) { c.Next() }
+	}
+	return func(c *gin.Context) {
+		result, err := limiter.Allow(c.Request.Context(), name+":"+rule.Key(c), rule.Limit)
+		if err != nil {
+			slog.WarnContext(c.Request.Context(), "Rate limiter failed", "limit", name, "error", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.ResetAfter))
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,96 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
+)
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RateLimit(ratelimit.NewMemory(), "test", middleware.RateLimitRule{
+		Limit: ratelimit.Limit{Requests: 2, Period: time.Minute},
+		Key:   middleware.KeyByIP,
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	send := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("192.0.2.1")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("RateLimit-Reset"))
+
+	send("192.0.2.1")
+	w = send("192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+
+	assert.Equal(t, http.StatusOK, send("192.0.2.2").Code)
+}
+
+func TestRateLimit_Keys(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	keyOf := func(key middleware.RateLimitKey, prepare func(c *gin.Context)) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.RemoteAddr = "192.0.2.1:1234"
+		prepare(c)
+		return key(c)
+	}
+	none := func(*gin.Context) {}
+
+	assert.Equal(t, "ip:192.0.2.1", keyOf(middleware.KeyByIP, none))
+	assert.Equal(t, "ip:192.0.2.1", keyOf(middleware.KeyByUser, none))
+	assert.Equal(t, "user:7", keyOf(middleware.KeyByUser, func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{UserID: 7})
+	}))
+	assert.Equal(t, "ip:192.0.2.1", keyOf(middleware.KeyByAPIKey, none))
+	apiKey := keyOf(middleware.KeyByAPIKey, func(c *gin.Context) {
+		c.Request.Header.Set(middleware.APIKeyHeader, "secret-key")
+	})
+	assert.Regexp(t, "^api-key:[0-9a-f]{64}$", apiKey)
+}
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(context.Context, string, ratelimit.Limit) (ratelimit.Result, error) {
+	return ratelimit.Result{}, errors.New("redis is down")
+}
+
+func TestRateLimit_FailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RateLimit(failingLimiter{}, "test", middleware.RateLimitRule{
+		Limit: ratelimit.Limit{Requests: 1, Period: time.Minute},
+		Key:   middleware.KeyByIP,
+	}))
+	router.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+}
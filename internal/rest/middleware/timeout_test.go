@@ -0,0 +1,42 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	deadlines := map[string]time.Duration{}
+	record := func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if ok {
+			deadlines[c.Request.URL.Path] = time.Until(deadline)
+		}
+	}
+
+	router := gin.New()
+	router.Use(middleware.Timeout(10*time.Second, map[string]time.Duration{
+		"GET /users/:id": time.Second,
+	}))
+	router.GET("/users", record)
+	router.GET("/users/:id", record)
+	router.POST("/users/:id", record)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	assert.InDelta(t, 10*time.Second, deadlines["/users"], float64(time.Second))
+	assert.InDelta(t, time.Second, deadlines["/users/1"], float64(500*time.Millisecond))
+
+	delete(deadlines, "/users/1")
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/1", nil))
+	assert.InDelta(t, 10*time.Second, deadlines["/users/1"], float64(time.Second))
+}
@@ -0,0 +1,95 @@
+package rest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest/dto"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+)
+
+type RoleHandler struct {
+	Service service.RoleService
+}
+
+type CreateRoleData struct {
+	Name        string   `json:"name" binding:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+type AssignRolesData struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+func NewRoleHandler(r *gin.Engine, svc service.RoleService, userSvc service.UserService) {
+	handler := &RoleHandler{
+		Service: svc,
+	}
+
+	authMiddleware := middleware.AuthMiddleware(userSvc)
+	manageRoles := middleware.RequirePermission(domain.PermissionRolesManage)
+	roleRoutes := r.Group("/roles", authMiddleware, manageRoles)
+	{
+		roleRoutes.GET("", handler.GetRoles)
+		roleRoutes.POST("", handler.CreateRole)
+	}
+	r.GET("/permissions", authMiddleware, manageRoles, handler.GetPermissions)
+	r.PUT("/users/:id/roles", authMiddleware, manageRoles, handler.AssignRoles)
+}
+
+func (h *RoleHandler) GetRoles(c *gin.Context) {
+	roles, err := h.Service.GetAllRoles(c.Request.Context())
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromRoleEntities(roles))
+}
+
+func (h *RoleHandler) GetPermissions(c *gin.Context) {
+	permissions, err := h.Service.GetAllPermissions(c.Request.Context())
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromPermissionEntities(permissions))
+}
+
+func (h *RoleHandler) CreateRole(c *gin.Context) {
+	var data CreateRoleData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	role := domain.Role{Name: data.Name, Description: data.Description}
+	if err := h.Service.CreateRole(c.Request.Context(), &role, data.Permissions); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.FromRoleEntity(&role))
+}
+
+func (h *RoleHandler) AssignRoles(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var data AssignRolesData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.AssignRoles(c.Request.Context(), uint(id), data.Roles); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package rest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+)
+
+func TestRoleHandler_GetRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRoleService := new(mocks.RoleService)
+	roleHandler := rest.RoleHandler{Service: mockRoleService}
+
+	mockRoles := []domain.Role{
+		{ID: 1, Name: "admin", Permissions: []domain.Permission{{Name: "users:delete"}}},
+	}
+	mockRoleService.On("GetAllRoles", mock.Anything, mock.Anything).Return(mockRoles, nil)
+
+	router := gin.Default()
+	router.GET("/roles", roleHandler.GetRoles)
+
+	req, _ := http.NewRequest(http.MethodGet, "/roles", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[{"id":1,"name":"admin","description":"","permissions":["users:delete"]}]`, w.Body.String())
+
+	mockRoleService.AssertExpectations(t)
+}
+
+func TestRoleHandler_CreateRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRoleService := new(mocks.RoleService)
+	roleHandler := rest.RoleHandler{Service: mockRoleService}
+
+	mockRoleService.On("CreateRole", mock.Anything, mock.AnythingOfType("*domain.Role"), []string{"users:read"}).
+		Run(func(args mock.Arguments) {
+			r := args.Get(1).(*domain.Role)
+			r.ID = 3
+			r.Permissions = []domain.Permission{{Name: "users:read"}}
+		}).
+		Return(nil)
+
+	router := gin.Default()
+	router.POST("/roles", roleHandler.CreateRole)
+
+	body := `{"name":"auditor","description":"Read only","permissions":["users:read"]}`
+	req, _ := http.NewRequest(http.MethodPost, "/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"id":3,"name":"auditor","description":"Read only","permissions":["users:read"]}`, w.Body.String())
+
+	mockRoleService.AssertExpectations(t)
+}
+
+func TestRoleHandler_AssignRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRoleService := new(mocks.RoleService)
+	roleHandler := rest.RoleHandler{Service: mockRoleService}
+
+	mockRoleService.On("AssignRoles", mock.Anything, uint(10), []string{"admin"}).Return(nil)
+
+	router := gin.Default()
+	router.PUT("/users/:id/roles", roleHandler.AssignRoles)
+
+	body := `{"roles":["admin"]}`
+	req, _ := http.NewRequest(http.MethodPut, "/users/10/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockRoleService.AssertExpectations(t)
+}
+
+func TestRoleHandler_AssignRoles_UserNotFound(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockRoleService := new(mocks.RoleService)
+	roleHandler := rest.RoleHandler{Service: mockRoleService}
+
+	mockRoleService.On("AssignRoles", mock.Anything, uint(99), []string{"admin"}).Return(domain.ErrNotFound)
+
+	router := gin.Default()
+	router.PUT("/users/:id/roles", roleHandler.AssignRoles)
+
+	body := `{"roles":["admin"]}`
+	req, _ := http.NewRequest(http.MethodPut, "/users/99/roles", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mockRoleService.AssertExpectations(t)
+}
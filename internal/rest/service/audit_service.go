@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+//go:generate mockery --name AuditService
+type AuditService interface {
+	GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error)
+	Verify(ctx context.Context) (*domain.AuditVerification, error)
+}
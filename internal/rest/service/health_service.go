@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+//go:generate mockery --name HealthService
+type HealthService interface {
+	Ready(ctx context.Context) domain.HealthReport
+}
@@ -0,0 +1,8 @@
+package service
+
+import "github.com/tat-101/bb-assignment-back/tools"
+
+//go:generate mockery --name KeyService
+type KeyService interface {
+	JWKS() tools.JWKS
+}
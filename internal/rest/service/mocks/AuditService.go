@@ -0,0 +1,154 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// AuditService is an autogenerated mock type for the AuditService type
+type AuditService struct {
+	mock.Mock
+}
+
+type AuditService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *AuditService) EXPECT() *AuditService_Expecter {
+	return &AuditService_Expecter{mock: &_m.Mock}
+}
+
+// GetAuditEvents provides a mock function with given fields: ctx, query
+func (_m *AuditService) GetAuditEvents(ctx context.Context, query domain.AuditQuery) (*domain.AuditPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAuditEvents")
+	}
+
+	var r0 *domain.AuditPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditQuery) *domain.AuditPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.AuditQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditService_GetAuditEvents_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAuditEvents'
+type AuditService_GetAuditEvents_Call struct {
+	*mock.Call
+}
+
+// GetAuditEvents is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query domain.AuditQuery
+func (_e *AuditService_Expecter) GetAuditEvents(ctx interface{}, query interface{}) *AuditService_GetAuditEvents_Call {
+	return &AuditService_GetAuditEvents_Call{Call: _e.mock.On("GetAuditEvents", ctx, query)}
+}
+
+func (_c *AuditService_GetAuditEvents_Call) Run(run func(ctx context.Context, query domain.AuditQuery)) *AuditService_GetAuditEvents_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.AuditQuery))
+	})
+	return _c
+}
+
+func (_c *AuditService_GetAuditEvents_Call) Return(_a0 *domain.AuditPage, _a1 error) *AuditService_GetAuditEvents_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditService_GetAuditEvents_Call) RunAndReturn(run func(context.Context, domain.AuditQuery) (*domain.AuditPage, error)) *AuditService_GetAuditEvents_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Verify provides a mock function with given fields: ctx
+func (_m *AuditService) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 *domain.AuditVerification
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (*domain.AuditVerification, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) *domain.AuditVerification); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditVerification)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// AuditService_Verify_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Verify'
+type AuditService_Verify_Call struct {
+	*mock.Call
+}
+
+// Verify is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *AuditService_Expecter) Verify(ctx interface{}) *AuditService_Verify_Call {
+	return &AuditService_Verify_Call{Call: _e.mock.On("Verify", ctx)}
+}
+
+func (_c *AuditService_Verify_Call) Run(run func(ctx context.Context)) *AuditService_Verify_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *AuditService_Verify_Call) Return(_a0 *domain.AuditVerification, _a1 error) *AuditService_Verify_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *AuditService_Verify_Call) RunAndReturn(run func(context.Context) (*domain.AuditVerification, error)) *AuditService_Verify_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditService creates a new instance of AuditService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditService {
+	mock := &AuditService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
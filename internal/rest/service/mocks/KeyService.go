@@ -0,0 +1,81 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	tools "github.com/tat-101/bb-assignment-back/tools"
+)
+
+// KeyService is an autogenerated mock type for the KeyService type
+type KeyService struct {
+	mock.Mock
+}
+
+type KeyService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *KeyService) EXPECT() *KeyService_Expecter {
+	return &KeyService_Expecter{mock: &_m.Mock}
+}
+
+// JWKS provides a mock function with given fields:
+func (_m *KeyService) JWKS() tools.JWKS {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for JWKS")
+	}
+
+	var r0 tools.JWKS
+	if rf, ok := ret.Get(0).(func() tools.JWKS); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(tools.JWKS)
+	}
+
+	return r0
+}
+
+// KeyService_JWKS_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'JWKS'
+type KeyService_JWKS_Call struct {
+	*mock.Call
+}
+
+// JWKS is a helper method to define mock.On call
+func (_e *KeyService_Expecter) JWKS() *KeyService_JWKS_Call {
+	return &KeyService_JWKS_Call{Call: _e.mock.On("JWKS")}
+}
+
+func (_c *KeyService_JWKS_Call) Run(run func()) *KeyService_JWKS_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *KeyService_JWKS_Call) Return(_a0 tools.JWKS) *KeyService_JWKS_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *KeyService_JWKS_Call) RunAndReturn(run func() tools.JWKS) *KeyService_JWKS_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewKeyService creates a new instance of KeyService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewKeyService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *KeyService {
+	mock := &KeyService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,249 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// RoleService is an autogenerated mock type for the RoleService type
+type RoleService struct {
+	mock.Mock
+}
+
+type RoleService_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RoleService) EXPECT() *RoleService_Expecter {
+	return &RoleService_Expecter{mock: &_m.Mock}
+}
+
+// AssignRoles provides a mock function with given fields: ctx, userID, roleNames
+func (_m *RoleService) AssignRoles(ctx context.Context, userID uint, roleNames []string) error {
+	ret := _m.Called(ctx, userID, roleNames)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) error); ok {
+		r0 = rf(ctx, userID, roleNames)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoleService_AssignRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignRoles'
+type RoleService_AssignRoles_Call struct {
+	*mock.Call
+}
+
+// AssignRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - roleNames []string
+func (_e *RoleService_Expecter) AssignRoles(ctx interface{}, userID interface{}, roleNames interface{}) *RoleService_AssignRoles_Call {
+	return &RoleService_AssignRoles_Call{Call: _e.mock.On("AssignRoles", ctx, userID, roleNames)}
+}
+
+func (_c *RoleService_AssignRoles_Call) Run(run func(ctx context.Context, userID uint, roleNames []string)) *RoleService_AssignRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *RoleService_AssignRoles_Call) Return(_a0 error) *RoleService_AssignRoles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoleService_AssignRoles_Call) RunAndReturn(run func(context.Context, uint, []string) error) *RoleService_AssignRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateRole provides a mock function with given fields: ctx, role, permissions
+func (_m *RoleService) CreateRole(ctx context.Context, role *domain.Role, permissions []string) error {
+	ret := _m.Called(ctx, role, permissions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Role, []string) error); ok {
+		r0 = rf(ctx, role, permissions)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoleService_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type RoleService_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - role *domain.Role
+//   - permissions []string
+func (_e *RoleService_Expecter) CreateRole(ctx interface{}, role interface{}, permissions interface{}) *RoleService_CreateRole_Call {
+	return &RoleService_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, role, permissions)}
+}
+
+func (_c *RoleService_CreateRole_Call) Run(run func(ctx context.Context, role *domain.Role, permissions []string)) *RoleService_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Role), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *RoleService_CreateRole_Call) Return(_a0 error) *RoleService_CreateRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoleService_CreateRole_Call) RunAndReturn(run func(context.Context, *domain.Role, []string) error) *RoleService_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllPermissions provides a mock function with given fields: ctx
+func (_m *RoleService) GetAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPermissions")
+	}
+
+	var r0 []domain.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Permission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Permission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleService_GetAllPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllPermissions'
+type RoleService_GetAllPermissions_Call struct {
+	*mock.Call
+}
+
+// GetAllPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RoleService_Expecter) GetAllPermissions(ctx interface{}) *RoleService_GetAllPermissions_Call {
+	return &RoleService_GetAllPermissions_Call{Call: _e.mock.On("GetAllPermissions", ctx)}
+}
+
+func (_c *RoleService_GetAllPermissions_Call) Run(run func(ctx context.Context)) *RoleService_GetAllPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RoleService_GetAllPermissions_Call) Return(_a0 []domain.Permission, _a1 error) *RoleService_GetAllPermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleService_GetAllPermissions_Call) RunAndReturn(run func(context.Context) ([]domain.Permission, error)) *RoleService_GetAllPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllRoles provides a mock function with given fields: ctx
+func (_m *RoleService) GetAllRoles(ctx context.Context) ([]domain.Role, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllRoles")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleService_GetAllRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllRoles'
+type RoleService_GetAllRoles_Call struct {
+	*mock.Call
+}
+
+// GetAllRoles is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RoleService_Expecter) GetAllRoles(ctx interface{}) *RoleService_GetAllRoles_Call {
+	return &RoleService_GetAllRoles_Call{Call: _e.mock.On("GetAllRoles", ctx)}
+}
+
+func (_c *RoleService_GetAllRoles_Call) Run(run func(ctx context.Context)) *RoleService_GetAllRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RoleService_GetAllRoles_Call) Return(_a0 []domain.Role, _a1 error) *RoleService_GetAllRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleService_GetAllRoles_Call) RunAndReturn(run func(context.Context) ([]domain.Role, error)) *RoleService_GetAllRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRoleService creates a new instance of RoleService. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleService(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleService {
+	mock := &RoleService{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
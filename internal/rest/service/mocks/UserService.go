@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/tat-101/bb-assignment-back/domain"
 )
@@ -20,27 +22,29 @@ func (_m *UserService) EXPECT() *UserService_Expecter {
 	return &UserService_Expecter{mock: &_m.Mock}
 }
 
-// AuthenticateUser provides a mock function with given fields: email, password
-func (_m *UserService) AuthenticateUser(email string, password string) (string, error) {
-	ret := _m.Called(email, password)
+// AuthenticateUser provides a mock function with given fields: ctx, email, password
+func (_m *UserService) AuthenticateUser(ctx context.Context, email string, password string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, email, password)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AuthenticateUser")
 	}
 
-	var r0 string
+	var r0 *domain.AuthTokens
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, string) (string, error)); ok {
-		return rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, email, password)
 	}
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(email, password)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, email, password)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(email, password)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, email, password)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -54,86 +58,100 @@ type UserService_AuthenticateUser_Call struct {
 }
 
 // AuthenticateUser is a helper method to define mock.On call
+//   - ctx context.Context
 //   - email string
 //   - password string
-func (_e *UserService_Expecter) AuthenticateUser(email interface{}, password interface{}) *UserService_AuthenticateUser_Call {
-	return &UserService_AuthenticateUser_Call{Call: _e.mock.On("AuthenticateUser", email, password)}
+func (_e *UserService_Expecter) AuthenticateUser(ctx interface{}, email interface{}, password interface{}) *UserService_AuthenticateUser_Call {
+	return &UserService_AuthenticateUser_Call{Call: _e.mock.On("AuthenticateUser", ctx, email, password)}
 }
 
-func (_c *UserService_AuthenticateUser_Call) Run(run func(email string, password string)) *UserService_AuthenticateUser_Call {
+func (_c *UserService_AuthenticateUser_Call) Run(run func(ctx context.Context, email string, password string)) *UserService_AuthenticateUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_AuthenticateUser_Call) Return(_a0 string, _a1 error) *UserService_AuthenticateUser_Call {
+func (_c *UserService_AuthenticateUser_Call) Return(_a0 *domain.AuthTokens, _a1 error) *UserService_AuthenticateUser_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_AuthenticateUser_Call) RunAndReturn(run func(string, string) (string, error)) *UserService_AuthenticateUser_Call {
+func (_c *UserService_AuthenticateUser_Call) RunAndReturn(run func(context.Context, string, string) (*domain.AuthTokens, error)) *UserService_AuthenticateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// CreateUser provides a mock function with given fields: user
-func (_m *UserService) CreateUser(user *domain.User) error {
-	ret := _m.Called(user)
+// BeginOIDCLogin provides a mock function with given fields: ctx, provider
+func (_m *UserService) BeginOIDCLogin(ctx context.Context, provider string) (*domain.OIDCLogin, error) {
+	ret := _m.Called(ctx, provider)
 
 	if len(ret) == 0 {
-		panic("no return value specified for CreateUser")
+		panic("no return value specified for BeginOIDCLogin")
 	}
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(user)
+	var r0 *domain.OIDCLogin
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.OIDCLogin, error)); ok {
+		return rf(ctx, provider)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.OIDCLogin); ok {
+		r0 = rf(ctx, provider)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCLogin)
+		}
 	}
 
-	return r0
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, provider)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
 }
 
-// UserService_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
-type UserService_CreateUser_Call struct {
+// UserService_BeginOIDCLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'BeginOIDCLogin'
+type UserService_BeginOIDCLogin_Call struct {
 	*mock.Call
 }
 
-// CreateUser is a helper method to define mock.On call
-//   - user *domain.User
-func (_e *UserService_Expecter) CreateUser(user interface{}) *UserService_CreateUser_Call {
-	return &UserService_CreateUser_Call{Call: _e.mock.On("CreateUser", user)}
+// BeginOIDCLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+func (_e *UserService_Expecter) BeginOIDCLogin(ctx interface{}, provider interface{}) *UserService_BeginOIDCLogin_Call {
+	return &UserService_BeginOIDCLogin_Call{Call: _e.mock.On("BeginOIDCLogin", ctx, provider)}
 }
 
-func (_c *UserService_CreateUser_Call) Run(run func(user *domain.User)) *UserService_CreateUser_Call {
+func (_c *UserService_BeginOIDCLogin_Call) Run(run func(ctx context.Context, provider string)) *UserService_BeginOIDCLogin_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*domain.User))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_CreateUser_Call) Return(_a0 error) *UserService_CreateUser_Call {
-	_c.Call.Return(_a0)
+func (_c *UserService_BeginOIDCLogin_Call) Return(_a0 *domain.OIDCLogin, _a1 error) *UserService_BeginOIDCLogin_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_CreateUser_Call) RunAndReturn(run func(*domain.User) error) *UserService_CreateUser_Call {
+func (_c *UserService_BeginOIDCLogin_Call) RunAndReturn(run func(context.Context, string) (*domain.OIDCLogin, error)) *UserService_BeginOIDCLogin_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUserByID provides a mock function with given fields: id
-func (_m *UserService) DeleteUserByID(id string) error {
-	ret := _m.Called(id)
+// ChangePassword provides a mock function with given fields: ctx, userID, currentPassword, newPassword
+func (_m *UserService) ChangePassword(ctx context.Context, userID uint, currentPassword string, newPassword string) error {
+	ret := _m.Called(ctx, userID, currentPassword, newPassword)
 
 	if len(ret) == 0 {
-		panic("no return value specified for DeleteUserByID")
+		panic("no return value specified for ChangePassword")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) error); ok {
+		r0 = rf(ctx, userID, currentPassword, newPassword)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -141,57 +159,60 @@ func (_m *UserService) DeleteUserByID(id string) error {
 	return r0
 }
 
-// UserService_DeleteUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserByID'
-type UserService_DeleteUserByID_Call struct {
+// UserService_ChangePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangePassword'
+type UserService_ChangePassword_Call struct {
 	*mock.Call
 }
 
-// DeleteUserByID is a helper method to define mock.On call
-//   - id string
-func (_e *UserService_Expecter) DeleteUserByID(id interface{}) *UserService_DeleteUserByID_Call {
-	return &UserService_DeleteUserByID_Call{Call: _e.mock.On("DeleteUserByID", id)}
+// ChangePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - currentPassword string
+//   - newPassword string
+func (_e *UserService_Expecter) ChangePassword(ctx interface{}, userID interface{}, currentPassword interface{}, newPassword interface{}) *UserService_ChangePassword_Call {
+	return &UserService_ChangePassword_Call{Call: _e.mock.On("ChangePassword", ctx, userID, currentPassword, newPassword)}
 }
 
-func (_c *UserService_DeleteUserByID_Call) Run(run func(id string)) *UserService_DeleteUserByID_Call {
+func (_c *UserService_ChangePassword_Call) Run(run func(ctx context.Context, userID uint, currentPassword string, newPassword string)) *UserService_ChangePassword_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(uint), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_DeleteUserByID_Call) Return(_a0 error) *UserService_DeleteUserByID_Call {
+func (_c *UserService_ChangePassword_Call) Return(_a0 error) *UserService_ChangePassword_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *UserService_DeleteUserByID_Call) RunAndReturn(run func(string) error) *UserService_DeleteUserByID_Call {
+func (_c *UserService_ChangePassword_Call) RunAndReturn(run func(context.Context, uint, string, string) error) *UserService_ChangePassword_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllUsers provides a mock function with given fields:
-func (_m *UserService) GetAllUsers() ([]domain.User, error) {
-	ret := _m.Called()
+// ChangeUserStatus provides a mock function with given fields: ctx, id, status, reason
+func (_m *UserService) ChangeUserStatus(ctx context.Context, id uint, status string, reason string) (*domain.User, error) {
+	ret := _m.Called(ctx, id, status, reason)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetAllUsers")
+		panic("no return value specified for ChangeUserStatus")
 	}
 
-	var r0 []domain.User
+	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.User, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) (*domain.User, error)); ok {
+		return rf(ctx, id, status, reason)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string, string) *domain.User); ok {
+		r0 = rf(ctx, id, status, reason)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.User)
+			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string, string) error); ok {
+		r1 = rf(ctx, id, status, reason)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -199,56 +220,60 @@ func (_m *UserService) GetAllUsers() ([]domain.User, error) {
 	return r0, r1
 }
 
-// UserService_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
-type UserService_GetAllUsers_Call struct {
+// UserService_ChangeUserStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeUserStatus'
+type UserService_ChangeUserStatus_Call struct {
 	*mock.Call
 }
 
-// GetAllUsers is a helper method to define mock.On call
-func (_e *UserService_Expecter) GetAllUsers() *UserService_GetAllUsers_Call {
-	return &UserService_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers")}
+// ChangeUserStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+//   - status string
+//   - reason string
+func (_e *UserService_Expecter) ChangeUserStatus(ctx interface{}, id interface{}, status interface{}, reason interface{}) *UserService_ChangeUserStatus_Call {
+	return &UserService_ChangeUserStatus_Call{Call: _e.mock.On("ChangeUserStatus", ctx, id, status, reason)}
 }
 
-func (_c *UserService_GetAllUsers_Call) Run(run func()) *UserService_GetAllUsers_Call {
+func (_c *UserService_ChangeUserStatus_Call) Run(run func(ctx context.Context, id uint, status string, reason string)) *UserService_ChangeUserStatus_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(uint), args[2].(string), args[3].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_GetAllUsers_Call) Return(_a0 []domain.User, _a1 error) *UserService_GetAllUsers_Call {
+func (_c *UserService_ChangeUserStatus_Call) Return(_a0 *domain.User, _a1 error) *UserService_ChangeUserStatus_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_GetAllUsers_Call) RunAndReturn(run func() ([]domain.User, error)) *UserService_GetAllUsers_Call {
+func (_c *UserService_ChangeUserStatus_Call) RunAndReturn(run func(context.Context, uint, string, string) (*domain.User, error)) *UserService_ChangeUserStatus_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByEmail provides a mock function with given fields: email
-func (_m *UserService) GetUserByEmail(email string) (*domain.User, error) {
-	ret := _m.Called(email)
+// CompleteOIDCLogin provides a mock function with given fields: ctx, provider, code, state, loginToken
+func (_m *UserService) CompleteOIDCLogin(ctx context.Context, provider string, code string, state string, loginToken string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, provider, code, state, loginToken)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByEmail")
+		panic("no return value specified for CompleteOIDCLogin")
 	}
 
-	var r0 *domain.User
+	var r0 *domain.AuthTokens
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*domain.User, error)); ok {
-		return rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, provider, code, state, loginToken)
 	}
-	if rf, ok := ret.Get(0).(func(string) *domain.User); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, provider, code, state, loginToken)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.User)
+			r0 = ret.Get(0).(*domain.AuthTokens)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(email)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string, string) error); ok {
+		r1 = rf(ctx, provider, code, state, loginToken)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -256,57 +281,61 @@ func (_m *UserService) GetUserByEmail(email string) (*domain.User, error) {
 	return r0, r1
 }
 
-// UserService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
-type UserService_GetUserByEmail_Call struct {
+// UserService_CompleteOIDCLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteOIDCLogin'
+type UserService_CompleteOIDCLogin_Call struct {
 	*mock.Call
 }
 
-// GetUserByEmail is a helper method to define mock.On call
-//   - email string
-func (_e *UserService_Expecter) GetUserByEmail(email interface{}) *UserService_GetUserByEmail_Call {
-	return &UserService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", email)}
+// CompleteOIDCLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - code string
+//   - state string
+//   - loginToken string
+func (_e *UserService_Expecter) CompleteOIDCLogin(ctx interface{}, provider interface{}, code interface{}, state interface{}, loginToken interface{}) *UserService_CompleteOIDCLogin_Call {
+	return &UserService_CompleteOIDCLogin_Call{Call: _e.mock.On("CompleteOIDCLogin", ctx, provider, code, state, loginToken)}
 }
 
-func (_c *UserService_GetUserByEmail_Call) Run(run func(email string)) *UserService_GetUserByEmail_Call {
+func (_c *UserService_CompleteOIDCLogin_Call) Run(run func(ctx context.Context, provider string, code string, state string, loginToken string)) *UserService_CompleteOIDCLogin_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string), args[4].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_GetUserByEmail_Call) Return(_a0 *domain.User, _a1 error) *UserService_GetUserByEmail_Call {
+func (_c *UserService_CompleteOIDCLogin_Call) Return(_a0 *domain.AuthTokens, _a1 error) *UserService_CompleteOIDCLogin_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_GetUserByEmail_Call) RunAndReturn(run func(string) (*domain.User, error)) *UserService_GetUserByEmail_Call {
+func (_c *UserService_CompleteOIDCLogin_Call) RunAndReturn(run func(context.Context, string, string, string, string) (*domain.AuthTokens, error)) *UserService_CompleteOIDCLogin_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByID provides a mock function with given fields: id
-func (_m *UserService) GetUserByID(id uint) (*domain.User, error) {
-	ret := _m.Called(id)
+// ConfirmMFA provides a mock function with given fields: ctx, userID, code
+func (_m *UserService) ConfirmMFA(ctx context.Context, userID uint, code string) ([]string, error) {
+	ret := _m.Called(ctx, userID, code)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetUserByID")
+		panic("no return value specified for ConfirmMFA")
 	}
 
-	var r0 *domain.User
+	var r0 []string
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*domain.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) ([]string, error)); ok {
+		return rf(ctx, userID, code)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *domain.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) []string); ok {
+		r0 = rf(ctx, userID, code)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.User)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, userID, code)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -314,57 +343,201 @@ func (_m *UserService) GetUserByID(id uint) (*domain.User, error) {
 	return r0, r1
 }
 
-// UserService_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
-type UserService_GetUserByID_Call struct {
+// UserService_ConfirmMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmMFA'
+type UserService_ConfirmMFA_Call struct {
 	*mock.Call
 }
 
-// GetUserByID is a helper method to define mock.On call
-//   - id uint
-func (_e *UserService_Expecter) GetUserByID(id interface{}) *UserService_GetUserByID_Call {
-	return &UserService_GetUserByID_Call{Call: _e.mock.On("GetUserByID", id)}
+// ConfirmMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - code string
+func (_e *UserService_Expecter) ConfirmMFA(ctx interface{}, userID interface{}, code interface{}) *UserService_ConfirmMFA_Call {
+	return &UserService_ConfirmMFA_Call{Call: _e.mock.On("ConfirmMFA", ctx, userID, code)}
 }
 
-func (_c *UserService_GetUserByID_Call) Run(run func(id uint)) *UserService_GetUserByID_Call {
+func (_c *UserService_ConfirmMFA_Call) Run(run func(ctx context.Context, userID uint, code string)) *UserService_ConfirmMFA_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_GetUserByID_Call) Return(_a0 *domain.User, _a1 error) *UserService_GetUserByID_Call {
+func (_c *UserService_ConfirmMFA_Call) Return(_a0 []string, _a1 error) *UserService_ConfirmMFA_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_GetUserByID_Call) RunAndReturn(run func(uint) (*domain.User, error)) *UserService_GetUserByID_Call {
+func (_c *UserService_ConfirmMFA_Call) RunAndReturn(run func(context.Context, uint, string) ([]string, error)) *UserService_ConfirmMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUserByID provides a mock function with given fields: id, updatedUser
-func (_m *UserService) UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error) {
-	ret := _m.Called(id, updatedUser)
+// CreateUser provides a mock function with given fields: ctx, user
+func (_m *UserService) CreateUser(ctx context.Context, user *domain.User) error {
+	ret := _m.Called(ctx, user)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateUserByID")
+		panic("no return value specified for CreateUser")
 	}
 
-	var r0 *domain.User
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_CreateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateUser'
+type UserService_CreateUser_Call struct {
+	*mock.Call
+}
+
+// CreateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *domain.User
+func (_e *UserService_Expecter) CreateUser(ctx interface{}, user interface{}) *UserService_CreateUser_Call {
+	return &UserService_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, user)}
+}
+
+func (_c *UserService_CreateUser_Call) Run(run func(ctx context.Context, user *domain.User)) *UserService_CreateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.User))
+	})
+	return _c
+}
+
+func (_c *UserService_CreateUser_Call) Return(_a0 error) *UserService_CreateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_CreateUser_Call) RunAndReturn(run func(context.Context, *domain.User) error) *UserService_CreateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteUserByID provides a mock function with given fields: ctx, id
+func (_m *UserService) DeleteUserByID(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteUserByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_DeleteUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteUserByID'
+type UserService_DeleteUserByID_Call struct {
+	*mock.Call
+}
+
+// DeleteUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *UserService_Expecter) DeleteUserByID(ctx interface{}, id interface{}) *UserService_DeleteUserByID_Call {
+	return &UserService_DeleteUserByID_Call{Call: _e.mock.On("DeleteUserByID", ctx, id)}
+}
+
+func (_c *UserService_DeleteUserByID_Call) Run(run func(ctx context.Context, id string)) *UserService_DeleteUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_DeleteUserByID_Call) Return(_a0 error) *UserService_DeleteUserByID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_DeleteUserByID_Call) RunAndReturn(run func(context.Context, string) error) *UserService_DeleteUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DisableMFA provides a mock function with given fields: ctx, userID, code
+func (_m *UserService) DisableMFA(ctx context.Context, userID uint, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DisableMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_DisableMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DisableMFA'
+type UserService_DisableMFA_Call struct {
+	*mock.Call
+}
+
+// DisableMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - code string
+func (_e *UserService_Expecter) DisableMFA(ctx interface{}, userID interface{}, code interface{}) *UserService_DisableMFA_Call {
+	return &UserService_DisableMFA_Call{Call: _e.mock.On("DisableMFA", ctx, userID, code)}
+}
+
+func (_c *UserService_DisableMFA_Call) Run(run func(ctx context.Context, userID uint, code string)) *UserService_DisableMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_DisableMFA_Call) Return(_a0 error) *UserService_DisableMFA_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_DisableMFA_Call) RunAndReturn(run func(context.Context, uint, string) error) *UserService_DisableMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// EnrollMFA provides a mock function with given fields: ctx, userID
+func (_m *UserService) EnrollMFA(ctx context.Context, userID uint) (*domain.MFAEnrollment, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for EnrollMFA")
+	}
+
+	var r0 *domain.MFAEnrollment
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, domain.User) (*domain.User, error)); ok {
-		return rf(id, updatedUser)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domain.MFAEnrollment, error)); ok {
+		return rf(ctx, userID)
 	}
-	if rf, ok := ret.Get(0).(func(string, domain.User) *domain.User); ok {
-		r0 = rf(id, updatedUser)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.MFAEnrollment); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.User)
+			r0 = ret.Get(0).(*domain.MFAEnrollment)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, domain.User) error); ok {
-		r1 = rf(id, updatedUser)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -372,58 +545,164 @@ func (_m *UserService) UpdateUserByID(id string, updatedUser domain.User) (*doma
 	return r0, r1
 }
 
-// UserService_UpdateUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserByID'
-type UserService_UpdateUserByID_Call struct {
+// UserService_EnrollMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'EnrollMFA'
+type UserService_EnrollMFA_Call struct {
 	*mock.Call
 }
 
-// UpdateUserByID is a helper method to define mock.On call
-//   - id string
-//   - updatedUser domain.User
-func (_e *UserService_Expecter) UpdateUserByID(id interface{}, updatedUser interface{}) *UserService_UpdateUserByID_Call {
-	return &UserService_UpdateUserByID_Call{Call: _e.mock.On("UpdateUserByID", id, updatedUser)}
+// EnrollMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *UserService_Expecter) EnrollMFA(ctx interface{}, userID interface{}) *UserService_EnrollMFA_Call {
+	return &UserService_EnrollMFA_Call{Call: _e.mock.On("EnrollMFA", ctx, userID)}
 }
 
-func (_c *UserService_UpdateUserByID_Call) Run(run func(id string, updatedUser domain.User)) *UserService_UpdateUserByID_Call {
+func (_c *UserService_EnrollMFA_Call) Run(run func(ctx context.Context, userID uint)) *UserService_EnrollMFA_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(domain.User))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
 
-func (_c *UserService_UpdateUserByID_Call) Return(_a0 *domain.User, _a1 error) *UserService_UpdateUserByID_Call {
+func (_c *UserService_EnrollMFA_Call) Return(_a0 *domain.MFAEnrollment, _a1 error) *UserService_EnrollMFA_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_UpdateUserByID_Call) RunAndReturn(run func(string, domain.User) (*domain.User, error)) *UserService_UpdateUserByID_Call {
+func (_c *UserService_EnrollMFA_Call) RunAndReturn(run func(context.Context, uint) (*domain.MFAEnrollment, error)) *UserService_EnrollMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ValidateToken provides a mock function with given fields: token
-func (_m *UserService) ValidateToken(token string) (*domain.User, error) {
-	ret := _m.Called(token)
+// ForgotPassword provides a mock function with given fields: ctx, email
+func (_m *UserService) ForgotPassword(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ValidateToken")
+		panic("no return value specified for ForgotPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_ForgotPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ForgotPassword'
+type UserService_ForgotPassword_Call struct {
+	*mock.Call
+}
+
+// ForgotPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserService_Expecter) ForgotPassword(ctx interface{}, email interface{}) *UserService_ForgotPassword_Call {
+	return &UserService_ForgotPassword_Call{Call: _e.mock.On("ForgotPassword", ctx, email)}
+}
+
+func (_c *UserService_ForgotPassword_Call) Run(run func(ctx context.Context, email string)) *UserService_ForgotPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_ForgotPassword_Call) Return(_a0 error) *UserService_ForgotPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_ForgotPassword_Call) RunAndReturn(run func(context.Context, string) error) *UserService_ForgotPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllUsers provides a mock function with given fields: ctx, query
+func (_m *UserService) GetAllUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllUsers")
+	}
+
+	var r0 *domain.UserPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) (*domain.UserPage, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) *domain.UserPage); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserPage)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserQuery) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_GetAllUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllUsers'
+type UserService_GetAllUsers_Call struct {
+	*mock.Call
+}
+
+// GetAllUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query domain.UserQuery
+func (_e *UserService_Expecter) GetAllUsers(ctx interface{}, query interface{}) *UserService_GetAllUsers_Call {
+	return &UserService_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx, query)}
+}
+
+func (_c *UserService_GetAllUsers_Call) Run(run func(ctx context.Context, query domain.UserQuery)) *UserService_GetAllUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(domain.UserQuery))
+	})
+	return _c
+}
+
+func (_c *UserService_GetAllUsers_Call) Return(_a0 *domain.UserPage, _a1 error) *UserService_GetAllUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_GetAllUsers_Call) RunAndReturn(run func(context.Context, domain.UserQuery) (*domain.UserPage, error)) *UserService_GetAllUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *UserService) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByEmail")
 	}
 
 	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*domain.User, error)); ok {
-		return rf(token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(ctx, email)
 	}
-	if rf, ok := ret.Get(0).(func(string) *domain.User); ok {
-		r0 = rf(token)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(token)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -431,30 +710,872 @@ func (_m *UserService) ValidateToken(token string) (*domain.User, error) {
 	return r0, r1
 }
 
-// UserService_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
-type UserService_ValidateToken_Call struct {
+// UserService_GetUserByEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByEmail'
+type UserService_GetUserByEmail_Call struct {
 	*mock.Call
 }
 
-// ValidateToken is a helper method to define mock.On call
-//   - token string
-func (_e *UserService_Expecter) ValidateToken(token interface{}) *UserService_ValidateToken_Call {
-	return &UserService_ValidateToken_Call{Call: _e.mock.On("ValidateToken", token)}
+// GetUserByEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserService_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *UserService_GetUserByEmail_Call {
+	return &UserService_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
+}
+
+func (_c *UserService_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *UserService_GetUserByEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_GetUserByEmail_Call) Return(_a0 *domain.User, _a1 error) *UserService_GetUserByEmail_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (*domain.User, error)) *UserService_GetUserByEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserService) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domain.User, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_GetUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserByID'
+type UserService_GetUserByID_Call struct {
+	*mock.Call
+}
+
+// GetUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserService_Expecter) GetUserByID(ctx interface{}, id interface{}) *UserService_GetUserByID_Call {
+	return &UserService_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, id)}
+}
+
+func (_c *UserService_GetUserByID_Call) Run(run func(ctx context.Context, id uint)) *UserService_GetUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserService_GetUserByID_Call) Return(_a0 *domain.User, _a1 error) *UserService_GetUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_GetUserByID_Call) RunAndReturn(run func(context.Context, uint) (*domain.User, error)) *UserService_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserStatusHistory provides a mock function with given fields: ctx, id
+func (_m *UserService) GetUserStatusHistory(ctx context.Context, id uint) ([]domain.UserStatusChange, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStatusHistory")
+	}
+
+	var r0 []domain.UserStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domain.UserStatusChange, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domain.UserStatusChange); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UserStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_GetUserStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStatusHistory'
+type UserService_GetUserStatusHistory_Call struct {
+	*mock.Call
+}
+
+// GetUserStatusHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserService_Expecter) GetUserStatusHistory(ctx interface{}, id interface{}) *UserService_GetUserStatusHistory_Call {
+	return &UserService_GetUserStatusHistory_Call{Call: _e.mock.On("GetUserStatusHistory", ctx, id)}
+}
+
+func (_c *UserService_GetUserStatusHistory_Call) Run(run func(ctx context.Context, id uint)) *UserService_GetUserStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserService_GetUserStatusHistory_Call) Return(_a0 []domain.UserStatusChange, _a1 error) *UserService_GetUserStatusHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_GetUserStatusHistory_Call) RunAndReturn(run func(context.Context, uint) ([]domain.UserStatusChange, error)) *UserService_GetUserStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Invite provides a mock function with given fields: ctx, email
+func (_m *UserService) Invite(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Invite")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_Invite_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Invite'
+type UserService_Invite_Call struct {
+	*mock.Call
+}
+
+// Invite is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserService_Expecter) Invite(ctx interface{}, email interface{}) *UserService_Invite_Call {
+	return &UserService_Invite_Call{Call: _e.mock.On("Invite", ctx, email)}
+}
+
+func (_c *UserService_Invite_Call) Run(run func(ctx context.Context, email string)) *UserService_Invite_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_Invite_Call) Return(_a0 error) *UserService_Invite_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_Invite_Call) RunAndReturn(run func(context.Context, string) error) *UserService_Invite_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Logout provides a mock function with given fields: ctx, token, refreshToken
+func (_m *UserService) Logout(ctx context.Context, token string, refreshToken string) error {
+	ret := _m.Called(ctx, token, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Logout")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, refreshToken)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_Logout_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Logout'
+type UserService_Logout_Call struct {
+	*mock.Call
+}
+
+// Logout is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - refreshToken string
+func (_e *UserService_Expecter) Logout(ctx interface{}, token interface{}, refreshToken interface{}) *UserService_Logout_Call {
+	return &UserService_Logout_Call{Call: _e.mock.On("Logout", ctx, token, refreshToken)}
+}
+
+func (_c *UserService_Logout_Call) Run(run func(ctx context.Context, token string, refreshToken string)) *UserService_Logout_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_Logout_Call) Return(_a0 error) *UserService_Logout_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_Logout_Call) RunAndReturn(run func(context.Context, string, string) error) *UserService_Logout_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RefreshToken provides a mock function with given fields: ctx, refreshToken
+func (_m *UserService) RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, refreshToken)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RefreshToken")
+	}
+
+	var r0 *domain.AuthTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, refreshToken)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, refreshToken)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, refreshToken)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_RefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RefreshToken'
+type UserService_RefreshToken_Call struct {
+	*mock.Call
+}
+
+// RefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - refreshToken string
+func (_e *UserService_Expecter) RefreshToken(ctx interface{}, refreshToken interface{}) *UserService_RefreshToken_Call {
+	return &UserService_RefreshToken_Call{Call: _e.mock.On("RefreshToken", ctx, refreshToken)}
+}
+
+func (_c *UserService_RefreshToken_Call) Run(run func(ctx context.Context, refreshToken string)) *UserService_RefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_RefreshToken_Call) Return(_a0 *domain.AuthTokens, _a1 error) *UserService_RefreshToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_RefreshToken_Call) RunAndReturn(run func(context.Context, string) (*domain.AuthTokens, error)) *UserService_RefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Register provides a mock function with given fields: ctx, user, invitation
+func (_m *UserService) Register(ctx context.Context, user *domain.User, invitation string) error {
+	ret := _m.Called(ctx, user, invitation)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Register")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string) error); ok {
+		r0 = rf(ctx, user, invitation)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_Register_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Register'
+type UserService_Register_Call struct {
+	*mock.Call
+}
+
+// Register is a helper method to define mock.On call
+//   - ctx context.Context
+//   - user *domain.User
+//   - invitation string
+func (_e *UserService_Expecter) Register(ctx interface{}, user interface{}, invitation interface{}) *UserService_Register_Call {
+	return &UserService_Register_Call{Call: _e.mock.On("Register", ctx, user, invitation)}
+}
+
+func (_c *UserService_Register_Call) Run(run func(ctx context.Context, user *domain.User, invitation string)) *UserService_Register_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.User), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_Register_Call) Return(_a0 error) *UserService_Register_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_Register_Call) RunAndReturn(run func(context.Context, *domain.User, string) error) *UserService_Register_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResendVerification provides a mock function with given fields: ctx, email
+func (_m *UserService) ResendVerification(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResendVerification")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_ResendVerification_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResendVerification'
+type UserService_ResendVerification_Call struct {
+	*mock.Call
+}
+
+// ResendVerification is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+func (_e *UserService_Expecter) ResendVerification(ctx interface{}, email interface{}) *UserService_ResendVerification_Call {
+	return &UserService_ResendVerification_Call{Call: _e.mock.On("ResendVerification", ctx, email)}
+}
+
+func (_c *UserService_ResendVerification_Call) Run(run func(ctx context.Context, email string)) *UserService_ResendVerification_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_ResendVerification_Call) Return(_a0 error) *UserService_ResendVerification_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_ResendVerification_Call) RunAndReturn(run func(context.Context, string) error) *UserService_ResendVerification_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetPassword provides a mock function with given fields: ctx, token, password
+func (_m *UserService) ResetPassword(ctx context.Context, token string, password string) error {
+	ret := _m.Called(ctx, token, password)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetPassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, token, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_ResetPassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetPassword'
+type UserService_ResetPassword_Call struct {
+	*mock.Call
+}
+
+// ResetPassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+//   - password string
+func (_e *UserService_Expecter) ResetPassword(ctx interface{}, token interface{}, password interface{}) *UserService_ResetPassword_Call {
+	return &UserService_ResetPassword_Call{Call: _e.mock.On("ResetPassword", ctx, token, password)}
+}
+
+func (_c *UserService_ResetPassword_Call) Run(run func(ctx context.Context, token string, password string)) *UserService_ResetPassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_ResetPassword_Call) Return(_a0 error) *UserService_ResetPassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_ResetPassword_Call) RunAndReturn(run func(context.Context, string, string) error) *UserService_ResetPassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreUser provides a mock function with given fields: ctx, id
+func (_m *UserService) RestoreUser(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_RestoreUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreUser'
+type UserService_RestoreUser_Call struct {
+	*mock.Call
+}
+
+// RestoreUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserService_Expecter) RestoreUser(ctx interface{}, id interface{}) *UserService_RestoreUser_Call {
+	return &UserService_RestoreUser_Call{Call: _e.mock.On("RestoreUser", ctx, id)}
+}
+
+func (_c *UserService_RestoreUser_Call) Run(run func(ctx context.Context, id uint)) *UserService_RestoreUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserService_RestoreUser_Call) Return(_a0 error) *UserService_RestoreUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_RestoreUser_Call) RunAndReturn(run func(context.Context, uint) error) *UserService_RestoreUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function with given fields: ctx, userID
+func (_m *UserService) RevokeUserSessions(ctx context.Context, userID uint) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type UserService_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *UserService_Expecter) RevokeUserSessions(ctx interface{}, userID interface{}) *UserService_RevokeUserSessions_Call {
+	return &UserService_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, userID)}
+}
+
+func (_c *UserService_RevokeUserSessions_Call) Run(run func(ctx context.Context, userID uint)) *UserService_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserService_RevokeUserSessions_Call) Return(_a0 error) *UserService_RevokeUserSessions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_RevokeUserSessions_Call) RunAndReturn(run func(context.Context, uint) error) *UserService_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UnlockUser provides a mock function with given fields: ctx, id
+func (_m *UserService) UnlockUser(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UnlockUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_UnlockUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UnlockUser'
+type UserService_UnlockUser_Call struct {
+	*mock.Call
+}
+
+// UnlockUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserService_Expecter) UnlockUser(ctx interface{}, id interface{}) *UserService_UnlockUser_Call {
+	return &UserService_UnlockUser_Call{Call: _e.mock.On("UnlockUser", ctx, id)}
+}
+
+func (_c *UserService_UnlockUser_Call) Run(run func(ctx context.Context, id uint)) *UserService_UnlockUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserService_UnlockUser_Call) Return(_a0 error) *UserService_UnlockUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_UnlockUser_Call) RunAndReturn(run func(context.Context, uint) error) *UserService_UnlockUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateUserByID provides a mock function with given fields: ctx, id, updatedUser
+func (_m *UserService) UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (*domain.User, error) {
+	ret := _m.Called(ctx, id, updatedUser)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateUserByID")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) (*domain.User, error)); ok {
+		return rf(ctx, id, updatedUser)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) *domain.User); ok {
+		r0 = rf(ctx, id, updatedUser)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.User) error); ok {
+		r1 = rf(ctx, id, updatedUser)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_UpdateUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateUserByID'
+type UserService_UpdateUserByID_Call struct {
+	*mock.Call
+}
+
+// UpdateUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - updatedUser domain.User
+func (_e *UserService_Expecter) UpdateUserByID(ctx interface{}, id interface{}, updatedUser interface{}) *UserService_UpdateUserByID_Call {
+	return &UserService_UpdateUserByID_Call{Call: _e.mock.On("UpdateUserByID", ctx, id, updatedUser)}
+}
+
+func (_c *UserService_UpdateUserByID_Call) Run(run func(ctx context.Context, id string, updatedUser domain.User)) *UserService_UpdateUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.User))
+	})
+	return _c
+}
+
+func (_c *UserService_UpdateUserByID_Call) Return(_a0 *domain.User, _a1 error) *UserService_UpdateUserByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_UpdateUserByID_Call) RunAndReturn(run func(context.Context, string, domain.User) (*domain.User, error)) *UserService_UpdateUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateMFAEnrollmentToken provides a mock function with given fields: ctx, token
+func (_m *UserService) ValidateMFAEnrollmentToken(ctx context.Context, token string) (*domain.User, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateMFAEnrollmentToken")
+	}
+
+	var r0 *domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_ValidateMFAEnrollmentToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateMFAEnrollmentToken'
+type UserService_ValidateMFAEnrollmentToken_Call struct {
+	*mock.Call
+}
+
+// ValidateMFAEnrollmentToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *UserService_Expecter) ValidateMFAEnrollmentToken(ctx interface{}, token interface{}) *UserService_ValidateMFAEnrollmentToken_Call {
+	return &UserService_ValidateMFAEnrollmentToken_Call{Call: _e.mock.On("ValidateMFAEnrollmentToken", ctx, token)}
+}
+
+func (_c *UserService_ValidateMFAEnrollmentToken_Call) Run(run func(ctx context.Context, token string)) *UserService_ValidateMFAEnrollmentToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_ValidateMFAEnrollmentToken_Call) Return(_a0 *domain.User, _a1 error) *UserService_ValidateMFAEnrollmentToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_ValidateMFAEnrollmentToken_Call) RunAndReturn(run func(context.Context, string) (*domain.User, error)) *UserService_ValidateMFAEnrollmentToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ValidateToken provides a mock function with given fields: ctx, token
+func (_m *UserService) ValidateToken(ctx context.Context, token string) (*domain.Principal, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ValidateToken")
+	}
+
+	var r0 *domain.Principal
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.Principal, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.Principal); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Principal)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_ValidateToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ValidateToken'
+type UserService_ValidateToken_Call struct {
+	*mock.Call
+}
+
+// ValidateToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *UserService_Expecter) ValidateToken(ctx interface{}, token interface{}) *UserService_ValidateToken_Call {
+	return &UserService_ValidateToken_Call{Call: _e.mock.On("ValidateToken", ctx, token)}
+}
+
+func (_c *UserService_ValidateToken_Call) Run(run func(ctx context.Context, token string)) *UserService_ValidateToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_ValidateToken_Call) Return(_a0 *domain.Principal, _a1 error) *UserService_ValidateToken_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserService_ValidateToken_Call) RunAndReturn(run func(context.Context, string) (*domain.Principal, error)) *UserService_ValidateToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyEmail provides a mock function with given fields: ctx, token
+func (_m *UserService) VerifyEmail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserService_VerifyEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyEmail'
+type UserService_VerifyEmail_Call struct {
+	*mock.Call
+}
+
+// VerifyEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token string
+func (_e *UserService_Expecter) VerifyEmail(ctx interface{}, token interface{}) *UserService_VerifyEmail_Call {
+	return &UserService_VerifyEmail_Call{Call: _e.mock.On("VerifyEmail", ctx, token)}
+}
+
+func (_c *UserService_VerifyEmail_Call) Run(run func(ctx context.Context, token string)) *UserService_VerifyEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *UserService_VerifyEmail_Call) Return(_a0 error) *UserService_VerifyEmail_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserService_VerifyEmail_Call) RunAndReturn(run func(context.Context, string) error) *UserService_VerifyEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// VerifyMFA provides a mock function with given fields: ctx, mfaToken, code
+func (_m *UserService) VerifyMFA(ctx context.Context, mfaToken string, code string) (*domain.AuthTokens, error) {
+	ret := _m.Called(ctx, mfaToken, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyMFA")
+	}
+
+	var r0 *domain.AuthTokens
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.AuthTokens, error)); ok {
+		return rf(ctx, mfaToken, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.AuthTokens); ok {
+		r0 = rf(ctx, mfaToken, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuthTokens)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, mfaToken, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserService_VerifyMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'VerifyMFA'
+type UserService_VerifyMFA_Call struct {
+	*mock.Call
+}
+
+// VerifyMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - mfaToken string
+//   - code string
+func (_e *UserService_Expecter) VerifyMFA(ctx interface{}, mfaToken interface{}, code interface{}) *UserService_VerifyMFA_Call {
+	return &UserService_VerifyMFA_Call{Call: _e.mock.On("VerifyMFA", ctx, mfaToken, code)}
 }
 
-func (_c *UserService_ValidateToken_Call) Run(run func(token string)) *UserService_ValidateToken_Call {
+func (_c *UserService_VerifyMFA_Call) Run(run func(ctx context.Context, mfaToken string, code string)) *UserService_VerifyMFA_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
 	})
 	return _c
 }
 
-func (_c *UserService_ValidateToken_Call) Return(_a0 *domain.User, _a1 error) *UserService_ValidateToken_Call {
+func (_c *UserService_VerifyMFA_Call) Return(_a0 *domain.AuthTokens, _a1 error) *UserService_VerifyMFA_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserService_ValidateToken_Call) RunAndReturn(run func(string) (*domain.User, error)) *UserService_ValidateToken_Call {
+func (_c *UserService_VerifyMFA_Call) RunAndReturn(run func(context.Context, string, string) (*domain.AuthTokens, error)) *UserService_VerifyMFA_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+//go:generate mockery --name RoleService
+type RoleService interface {
+	GetAllRoles(ctx context.Context) ([]domain.Role, error)
+	GetAllPermissions(ctx context.Context) ([]domain.Permission, error)
+	CreateRole(ctx context.Context, role *domain.Role, permissions []string) error
+	AssignRoles(ctx context.Context, userID uint, roleNames []string) error
+}
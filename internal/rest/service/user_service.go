@@ -1,16 +1,45 @@
 package service
 
-import "github.com/tat-101/bb-assignment-back/domain"
+import (
+	"context"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
 
 //go:generate mockery --name UserService
 type UserService interface {
-	CreateUser(user *domain.User) error
-	GetAllUsers() ([]domain.User, error)
-	GetUserByID(id uint) (*domain.User, error)
-	GetUserByEmail(email string) (*domain.User, error)
-	UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error)
-	DeleteUserByID(id string) error
-
-	AuthenticateUser(email, password string) (string, error)
-	ValidateToken(token string) (*domain.User, error)
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetAllUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error)
+	GetUserByID(ctx context.Context, id uint) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (*domain.User, error)
+	DeleteUserByID(ctx context.Context, id string) error
+	RestoreUser(ctx context.Context, id uint) error
+
+	AuthenticateUser(ctx context.Context, email, password string) (*domain.AuthTokens, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*domain.AuthTokens, error)
+	ValidateToken(ctx context.Context, token string) (*domain.Principal, error)
+	Logout(ctx context.Context, token, refreshToken string) error
+	RevokeUserSessions(ctx context.Context, userID uint) error
+	UnlockUser(ctx context.Context, id uint) error
+	ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) error
+	ChangeUserStatus(ctx context.Context, id uint, status, reason string) (*domain.User, error)
+	GetUserStatusHistory(ctx context.Context, id uint) ([]domain.UserStatusChange, error)
+
+	Register(ctx context.Context, user *domain.User, invitation string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	Invite(ctx context.Context, email string) error
+
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password string) error
+
+	EnrollMFA(ctx context.Context, userID uint) (*domain.MFAEnrollment, error)
+	ConfirmMFA(ctx context.Context, userID uint, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID uint, code string) error
+	VerifyMFA(ctx context.Context, mfaToken, code string) (*domain.AuthTokens, error)
+	ValidateMFAEnrollmentToken(ctx context.Context, token string) (*domain.User, error)
+
+	BeginOIDCLogin(ctx context.Context, provider string) (*domain.OIDCLogin, error)
+	CompleteOIDCLogin(ctx context.Context, provider, code, state, loginToken string) (*domain.AuthTokens, error)
 }
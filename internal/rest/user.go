@@ -1,14 +1,19 @@
 package rest
 
 import (
+	"context"
+	"errors"
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/internal/rest/dto"
 	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
 	"github.com/tat-101/bb-assignment-back/internal/rest/service"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
 )
 
 type UserHandler struct {
@@ -20,34 +25,142 @@ type LoginData struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func NewUserHandler(r *gin.Engine, svc service.UserService) {
+type ListUsersQuery struct {
+	Limit       int       `form:"limit" binding:"omitempty,min=1"`
+	Offset      int       `form:"offset" binding:"omitempty,min=0"`
+	Cursor      string    `form:"cursor"`
+	Role        string    `form:"role"`
+	Email       string    `form:"email"`
+	Name        string    `form:"name"`
+	CreatedFrom time.Time `form:"createdFrom"`
+	CreatedTo   time.Time `form:"createdTo"`
+	Sort        string    `form:"sort"`
+	Order       string    `form:"order" binding:"omitempty,oneof=asc desc"`
+}
+
+type RefreshData struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type LogoutData struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+type RegisterData struct {
+	Name       string `json:"name" binding:"required"`
+	Email      string `json:"email" binding:"required,email"`
+	Password   string `json:"password" binding:"required,min=6"`
+	Invitation string `json:"invitation"`
+}
+
+type VerifyEmailData struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResetPasswordData struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type MFACodeData struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type MFAVerifyData struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type EmailData struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ProfileData struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type ChangePasswordData struct {
+	CurrentPassword string `json:"currentPassword" binding:"required"`
+	NewPassword     string `json:"newPassword" binding:"required,min=6"`
+}
+
+type UserStatusData struct {
+	Status string `json:"status" binding:"required"`
+	// Reason fits the user_status_changes.reason column.
+	Reason string `json:"reason" binding:"required,max=1000"`
+}
+
+// UserRateLimits are the rate limits of the /users and /auth route groups.
+// The /me routes share the /users limit. A zero limit leaves its group
+// unlimited.
+type UserRateLimits struct {
+	Limiter ratelimit.Limiter
+	Users   middleware.RateLimitRule
+	Auth    middleware.RateLimitRule
+}
+
+func NewUserHandler(r *gin.Engine, svc service.UserService, limits UserRateLimits) {
 	handler := &UserHandler{
 		Service: svc,
 	}
 
 	authMiddleware := middleware.AuthMiddleware(svc)
-	userRoutes := r.Group("/users")
+	enrollmentAuth := middleware.MFAEnrollmentAuth(svc)
+	// Every /users route needs authentication, so its limit can be per user.
+	userRoutes := r.Group("/users", authMiddleware, middleware.RateLimit(limits.Limiter, "users", limits.Users))
+	{
+		userRoutes.GET("", middleware.RequirePermission(domain.PermissionUsersRead), handler.GetUsers)
+		userRoutes.POST("", middleware.RequirePermission(domain.PermissionUsersWrite), handler.CreateUser)
+		userRoutes.GET("/:id", middleware.RequirePermission(domain.PermissionUsersRead), handler.GetUserByID)
+		userRoutes.PUT("/:id", middleware.Authorize(middleware.SelfOrAdmin("id")), handler.UpdateUserByID)
+		userRoutes.DELETE("/:id", middleware.RequirePermission(domain.PermissionUsersDelete), handler.DeleteUserByID)
+		userRoutes.POST("/:id/restore", middleware.RequirePermission(domain.PermissionUsersDelete), handler.RestoreUser)
+		userRoutes.DELETE("/:id/sessions", middleware.RequirePermission(domain.PermissionUsersWrite), handler.RevokeUserSessions)
+		userRoutes.POST("/:id/unlock", middleware.RequirePermission(domain.PermissionUsersWrite), handler.UnlockUser)
+		userRoutes.PUT("/:id/status", middleware.RequirePermission(domain.PermissionUsersWrite), handler.ChangeUserStatus)
+		userRoutes.GET("/:id/status-history", middleware.RequirePermission(domain.PermissionUsersWrite), handler.GetUserStatusHistory)
+	}
+
+	meRoutes := r.Group("/me", authMiddleware, middleware.RateLimit(limits.Limiter, "users", limits.Users))
 	{
-		userRoutes.GET("", authMiddleware, handler.GetUsers)
-		userRoutes.POST("", authMiddleware, handler.CreateUser)
-		userRoutes.GET("/:id", authMiddleware, handler.GetUserByID)
-		userRoutes.PUT("/:id", authMiddleware, handler.UpdateUserByID)
-		userRoutes.DELETE("/:id", authMiddleware, middleware.AdminMiddleware(), handler.DeleteUserByID)
+		meRoutes.GET("", handler.GetMe)
+		meRoutes.PATCH("", handler.UpdateMe)
+		meRoutes.POST("/password", handler.ChangeMyPassword)
 	}
 
-	authRoutes := r.Group("/auth")
+	authRoutes := r.Group("/auth", middleware.RateLimit(limits.Limiter, "auth", limits.Auth))
 	{
 		authRoutes.POST("/login", handler.LoginUser)
+		authRoutes.POST("/refresh", handler.RefreshToken)
+		authRoutes.POST("/logout", authMiddleware, handler.LogoutUser)
+		authRoutes.POST("/register", handler.Register)
+		authRoutes.POST("/verify", handler.VerifyEmail)
+		authRoutes.POST("/verify/resend", handler.ResendVerification)
+		authRoutes.POST("/forgot-password", handler.ForgotPassword)
+		authRoutes.POST("/reset-password", handler.ResetPassword)
+		authRoutes.POST("/invitations", authMiddleware, middleware.RequirePermission(domain.PermissionUsersWrite), handler.Invite)
+		authRoutes.POST("/mfa/enroll", enrollmentAuth, handler.EnrollMFA)
+		authRoutes.POST("/mfa/confirm", enrollmentAuth, handler.ConfirmMFA)
+		authRoutes.POST("/mfa/disable", authMiddleware, handler.DisableMFA)
+		authRoutes.POST("/mfa/verify", handler.VerifyMFA)
+		authRoutes.GET("/oidc/:provider/login", handler.BeginOIDCLogin)
+		authRoutes.GET("/oidc/:provider/callback", handler.CompleteOIDCLogin)
 	}
 }
 
 func (h *UserHandler) GetUsers(c *gin.Context) {
-	users, err := h.Service.GetAllUsers()
+	var query ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := h.Service.GetAllUsers(c.Request.Context(), domain.UserQuery(query))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, dto.FromUserEntities(users))
+	c.JSON(http.StatusOK, dto.FromUserPage(page))
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
@@ -56,8 +169,8 @@ func (h *UserHandler) CreateUser(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if err := h.Service.CreateUser(&user); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.Service.CreateUser(c.Request.Context(), &user); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, dto.FromUserEntity(&user))
@@ -71,7 +184,7 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := h.Service.GetUserByID(uint(idStr))
+	user, err := h.Service.GetUserByID(c.Request.Context(), uint(idStr))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
 		return
@@ -91,9 +204,21 @@ func (h *UserHandler) UpdateUserByID(c *gin.Context) {
 	tempId, _ := strconv.ParseUint(id, 10, 32)
 	user.ID = uint(tempId)
 
-	updatedUser, err := h.Service.UpdateUserByID(id, user)
+	if user.Password != "" {
+		actor, ok := middleware.CurrentPrincipal(c)
+		if !ok || !middleware.CanChangePassword(actor, user.ID) {
+			msg := "Access denied, users:write permission required to change another user's password"
+			if ok && actor.UserID == user.ID {
+				msg = "Use POST /me/password to change your own password"
+			}
+			c.JSON(http.StatusForbidden, gin.H{"error": msg})
+			return
+		}
+	}
+
+	updatedUser, err := h.Service.UpdateUserByID(c.Request.Context(), id, user)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, dto.FromUserEntity(updatedUser))
@@ -101,8 +226,22 @@ func (h *UserHandler) UpdateUserByID(c *gin.Context) {
 
 func (h *UserHandler) DeleteUserByID(c *gin.Context) {
 	id := c.Param("id")
-	if err := h.Service.DeleteUserByID(id); err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+	if err := h.Service.DeleteUserByID(c.Request.Context(), id); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) RestoreUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.RestoreUser(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.Status(http.StatusNoContent)
@@ -115,11 +254,401 @@ func (h *UserHandler) LoginUser(c *gin.Context) {
 		return
 	}
 
-	token, err := h.Service.AuthenticateUser(loginData.Email, loginData.Password)
+	tokens, err := h.Service.AuthenticateUser(c.Request.Context(), loginData.Email, loginData.Password)
+	if errors.Is(err, domain.ErrEmailNotVerified) || errors.Is(err, domain.ErrAccountInactive) ||
+		errors.Is(err, domain.ErrTooManyRequests) {
+		setRetryAfter(c, err)
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if tokens.MFAToken != "" {
+		c.JSON(http.StatusOK, dto.FromMFAChallenge(tokens))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromAuthTokens(tokens))
+}
+
+func (h *UserHandler) RefreshToken(c *gin.Context) {
+	var refreshData RefreshData
+	if err := c.ShouldBindJSON(&refreshData); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.Service.RefreshToken(c.Request.Context(), refreshData.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromAuthTokens(tokens))
+}
+
+func (h *UserHandler) LogoutUser(c *gin.Context) {
+	var logoutData LogoutData
+	// The body is optional; without it only the access token is revoked.
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&logoutData); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.Service.Logout(c.Request.Context(), c.GetHeader("Authorization"), logoutData.RefreshToken); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) RevokeUserSessions(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.RevokeUserSessions(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) UnlockUser(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.UnlockUser(c.Request.Context(), uint(id)); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// GetMe returns the authenticated user.
+func (h *UserHandler) GetMe(c *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromPrincipal(principal))
+}
+
+// UpdateMe changes the authenticated user's profile.
+func (h *UserHandler) UpdateMe(c *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	var data ProfileData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id := strconv.FormatUint(uint64(principal.UserID), 10)
+	user, err := h.Service.UpdateUserByID(c.Request.Context(), id, domain.User{Name: data.Name})
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromUserEntity(user))
+}
+
+// ChangeMyPassword changes the authenticated user's password.
+func (h *UserHandler) ChangeMyPassword(c *gin.Context) {
+	principal, ok := middleware.CurrentPrincipal(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+		return
+	}
+	var data ChangePasswordData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err := h.Service.ChangePassword(c.Request.Context(), principal.UserID, data.CurrentPassword, data.NewPassword)
+	if err != nil {
+		setRetryAfter(c, err)
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) ChangeUserStatus(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var data UserStatusData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := h.Service.ChangeUserStatus(c.Request.Context(), uint(id), data.Status, data.Reason)
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromUserEntity(user))
+}
+
+func (h *UserHandler) GetUserStatusHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changes, err := h.Service.GetUserStatusHistory(c.Request.Context(), uint(id))
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromUserStatusChanges(changes))
+}
+
+func (h *UserHandler) Register(c *gin.Context) {
+	var data RegisterData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	user := domain.User{Name: data.Name, Email: data.Email, Password: data.Password}
+	if err := h.Service.Register(c.Request.Context(), &user, data.Invitation); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, dto.FromUserEntity(&user))
+}
+
+func (h *UserHandler) VerifyEmail(c *gin.Context) {
+	var data VerifyEmailData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.VerifyEmail(c.Request.Context(), data.Token); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) ResendVerification(c *gin.Context) {
+	var data EmailData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.ResendVerification(c.Request.Context(), data.Email); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (h *UserHandler) Invite(c *gin.Context) {
+	var data EmailData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.Invite(c.Request.Context(), data.Email); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (h *UserHandler) ForgotPassword(c *gin.Context) {
+	var data EmailData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.ForgotPassword(c.Request.Context(), data.Email); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusAccepted)
+}
+
+func (h *UserHandler) ResetPassword(c *gin.Context) {
+	var data ResetPasswordData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.Service.ResetPassword(c.Request.Context(), data.Token, data.Password); err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) EnrollMFA(c *gin.Context) {
+	actor, _ := middleware.CurrentPrincipal(c)
+	enrollment, err := h.Service.EnrollMFA(c.Request.Context(), actor.UserID)
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromMFAEnrollment(enrollment))
+}
+
+func (h *UserHandler) ConfirmMFA(c *gin.Context) {
+	var data MFACodeData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := middleware.CurrentPrincipal(c)
+	codes, err := h.Service.ConfirmMFA(c.Request.Context(), actor.UserID, data.Code)
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, dto.RecoveryCodesDTO{RecoveryCodes: codes})
+}
+
+func (h *UserHandler) DisableMFA(c *gin.Context) {
+	var data MFACodeData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	actor, _ := middleware.CurrentPrincipal(c)
+	if err := h.Service.DisableMFA(c.Request.Context(), actor.UserID, data.Code); err != nil {
+		setRetryAfter(c, err)
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *UserHandler) VerifyMFA(c *gin.Context) {
+	var data MFAVerifyData
+	if err := c.ShouldBindJSON(&data); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tokens, err := h.Service.VerifyMFA(c.Request.Context(), data.MFAToken, data.Code)
+	if errors.Is(err, domain.ErrForbidden) || errors.Is(err, domain.ErrTooManyRequests) {
+		setRetryAfter(c, err)
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, dto.FromAuthTokens(tokens))
+}
+
+// oidcLoginCookie binds an OIDC login to the browser that started it.
+const oidcLoginCookie = "oidc_login"
+
+func (h *UserHandler) BeginOIDCLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	login, err := h.Service.BeginOIDCLogin(c.Request.Context(), provider)
+	if err != nil {
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	// Lax still sends the cookie on the top-level redirect back from the
+	// provider.
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcLoginCookie, login.LoginToken, int((10 * time.Minute).Seconds()),
+		"/auth/oidc/"+provider, "", c.Request.TLS != nil, true)
+	c.Redirect(http.StatusFound, login.AuthURL)
+}
 
-	c.JSON(http.StatusOK, gin.H{"token": token})
+func (h *UserHandler) CompleteOIDCLogin(c *gin.Context) {
+	provider := c.Param("provider")
+	if reason := c.Query("error"); reason != "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "single sign-on failed: " + reason})
+		return
+	}
+	loginToken, err := c.Cookie(oidcLoginCookie)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "single sign-on failed: login was not started in this browser"})
+		return
+	}
+
+	tokens, err := h.Service.CompleteOIDCLogin(c.Request.Context(), provider, c.Query("code"), c.Query("state"), loginToken)
+	if err != nil {
+		setRetryAfter(c, err)
+		c.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.SetCookie(oidcLoginCookie, "", -1, "/auth/oidc/"+provider, "", c.Request.TLS != nil, true)
+	if tokens.MFAToken != "" {
+		c.JSON(http.StatusOK, dto.FromMFAChallenge(tokens))
+		return
+	}
+	c.JSON(http.StatusOK, dto.FromAuthTokens(tokens))
+}
+
+// statusClientClosedRequest is the non-standard status, popularised by nginx,
+// logged when the client went away before the response was ready.
+const statusClientClosedRequest = 499
+
+// setRetryAfter tells the client how long to wait when err is a
+// *domain.TooManyRequestsError.
+func setRetryAfter(c *gin.Context, err error) {
+	var tooMany *domain.TooManyRequestsError
+	if errors.As(err, &tooMany) && tooMany.RetryAfter > 0 {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(tooMany.RetryAfter.Seconds()))))
+	}
+}
+
+func getStatusCode(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrBadParamInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden), errors.Is(err, domain.ErrEmailNotVerified),
+		errors.Is(err, domain.ErrAccountInactive):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	case errors.Is(err, context.Canceled):
+		return statusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
 }
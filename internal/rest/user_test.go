@@ -1,6 +1,9 @@
 package rest_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -10,9 +13,12 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
 	"github.com/tat-101/bb-assignment-back/internal/rest/service/mocks"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
 )
 
 func TestHandler_GetUsers(t *testing.T) {
@@ -42,7 +48,7 @@ func TestHandler_GetUsers(t *testing.T) {
 		},
 	}
 
-	mockUserService.On("GetAllUsers").Return(mockListUser, nil)
+	mockUserService.On("GetAllUsers", mock.Anything, domain.UserQuery{}).Return(&domain.UserPage{Items: mockListUser, Total: 2}, nil)
 
 	router := gin.Default()
 	router.GET("/users", userHandler.GetUsers)
@@ -55,15 +61,15 @@ func TestHandler_GetUsers(t *testing.T) {
 	// Check the response
 	assert.Equal(t, http.StatusOK, w.Code)
 	// fmt.Println(w.Body.String())
-	expectedResponse := "["
+	expectedResponse := `{"total":2,"items":[`
 	for i, user := range mockListUser {
 		if i > 0 {
 			expectedResponse += ","
 		}
 		createdAt := user.CreatedAt.Format(time.RFC3339)
-		expectedResponse += `{"id":` + strconv.Itoa(int(user.ID)) + `,"email":"` + user.Email + `","name":"` + user.Name + `","createdAt":"` + createdAt + `"}`
+		expectedResponse += `{"id":` + strconv.Itoa(int(user.ID)) + `,"email":"` + user.Email + `","name":"` + user.Name + `","status":"active","createdAt":"` + createdAt + `"}`
 	}
-	expectedResponse += "]"
+	expectedResponse += "]}"
 	// expectedResponse := `[{"id":1,"email":"john@example.com","name":"John Doe"},{"id":2,"email":"jane@example.com","name":"Jane Doe"}]`
 	assert.JSONEq(t, expectedResponse, w.Body.String())
 
@@ -71,6 +77,84 @@ func TestHandler_GetUsers(t *testing.T) {
 	mockUserService.AssertExpectations(t)
 }
 
+func TestHandler_GetUsers_Query(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	expectedQuery := domain.UserQuery{
+		Limit:       5,
+		Cursor:      "abc",
+		Role:        "admin",
+		CreatedFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Sort:        "createdAt",
+		Order:       "asc",
+	}
+	mockUserService.On("GetAllUsers", mock.Anything, expectedQuery).Return(&domain.UserPage{Items: []domain.User{}, NextCursor: "def", Total: 9}, nil)
+
+	router := gin.Default()
+	router.GET("/users", userHandler.GetUsers)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users?limit=5&cursor=abc&role=admin&createdFrom=2024-01-01T00:00:00Z&sort=createdAt&order=asc", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"items":[],"nextCursor":"def","total":9}`, w.Body.String())
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestHandler_GetUsers_InvalidSort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("GetAllUsers", mock.Anything, domain.UserQuery{Sort: "password"}).
+		Return(nil, fmt.Errorf("%w: invalid sort field", domain.ErrBadParamInput))
+
+	router := gin.Default()
+	router.GET("/users", userHandler.GetUsers)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users?sort=password", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestHandler_GetUsers_Timeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("GetAllUsers", mock.Anything, domain.UserQuery{}).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(nil, fmt.Errorf("list users: %w", context.DeadlineExceeded))
+
+	router := gin.Default()
+	router.Use(middleware.Timeout(10*time.Millisecond, nil))
+	router.GET("/users", userHandler.GetUsers)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
 func TestUserHandler_CreateUser(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
@@ -83,7 +167,7 @@ func TestUserHandler_CreateUser(t *testing.T) {
 		Password: "password123",
 	}
 
-	mockUserService.On("CreateUser", &mockUser).Return(nil)
+	mockUserService.On("CreateUser", mock.Anything, &mockUser).Return(nil)
 
 	router := gin.Default()
 	router.POST("/users", userHandler.CreateUser)
@@ -97,7 +181,7 @@ func TestUserHandler_CreateUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusCreated, w.Code)
 
-	expectedResponse := `{"id":0,"email":"john@example.com","name":"John Doe","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
+	expectedResponse := `{"id":0,"email":"john@example.com","name":"John Doe","status":"active","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
 	// fmt.Println(w.Body.String())
 	assert.JSONEq(t, expectedResponse, w.Body.String())
 
@@ -117,7 +201,7 @@ func TestUserHandler_GetUserByID(t *testing.T) {
 		CreatedAt: time.Date(2040, 7, 10, 0, 38, 44, 0, time.FixedZone("UTC+7", 7*3600)),
 	}
 
-	mockUserService.On("GetUserByID", uint(10)).Return(&mockUser, nil)
+	mockUserService.On("GetUserByID", mock.Anything, uint(10)).Return(&mockUser, nil)
 
 	router := gin.Default()
 	router.GET("/users/:id", userHandler.GetUserByID)
@@ -129,7 +213,7 @@ func TestUserHandler_GetUserByID(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	expectedResponse := `{"id":10,"email":"john@example.com","name":"John Doe","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
+	expectedResponse := `{"id":10,"email":"john@example.com","name":"John Doe","status":"active","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
 	assert.JSONEq(t, expectedResponse, w.Body.String())
 
 	mockUserService.AssertExpectations(t)
@@ -147,7 +231,7 @@ func TestUserHandler_UpdateUserByID(t *testing.T) {
 		Email: "john@example.com",
 	}
 
-	mockUserService.On("UpdateUserByID", "10", mockUser).Return(&mockUser, nil)
+	mockUserService.On("UpdateUserByID", mock.Anything, "10", mockUser).Return(&mockUser, nil)
 
 	router := gin.Default()
 	router.PUT("/users/:id", userHandler.UpdateUserByID)
@@ -162,19 +246,66 @@ func TestUserHandler_UpdateUserByID(t *testing.T) {
 	// fmt.Println("asdfasfasdf", w.Body.String())
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	expectedResponse := `{"id":10,"email":"john@example.com","name":"John Smith","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
+	expectedResponse := `{"id":10,"email":"john@example.com","name":"John Smith","status":"active","createdAt":"` + mockUser.CreatedAt.Format(time.RFC3339) + `"}`
 	assert.JSONEq(t, expectedResponse, w.Body.String())
 
 	mockUserService.AssertExpectations(t)
 }
 
+func TestUserHandler_UpdateUserByID_PasswordOfOtherUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	router := gin.Default()
+	router.PUT("/users/:id", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{UserID: 11, Roles: []string{"user"}})
+	}, userHandler.UpdateUserByID)
+
+	body := `{"password":"hijacked"}`
+	req, _ := http.NewRequest(http.MethodPut, "/users/10", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	mockUserService.AssertNotCalled(t, "UpdateUserByID", mock.Anything, mock.Anything)
+}
+
+func TestUserHandler_UpdateUserByID_OwnPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	router := gin.Default()
+	router.PUT("/users/:id", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{UserID: 10, Roles: []string{"admin"}, Permissions: []string{domain.PermissionUsersWrite}})
+	}, userHandler.UpdateUserByID)
+
+	body := `{"password":"without-the-current-one"}`
+	req, _ := http.NewRequest(http.MethodPut, "/users/10", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "/me/password")
+
+	mockUserService.AssertNotCalled(t, "UpdateUserByID", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestUserHandler_DeleteUserByID(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockUserService := new(mocks.UserService)
 	userHandler := rest.UserHandler{Service: mockUserService}
 
-	mockUserService.On("DeleteUserByID", "10").Return(nil)
+	mockUserService.On("DeleteUserByID", mock.Anything, "10").Return(nil)
 
 	router := gin.Default()
 	router.DELETE("/users/:id", userHandler.DeleteUserByID)
@@ -189,14 +320,65 @@ func TestUserHandler_DeleteUserByID(t *testing.T) {
 	mockUserService.AssertExpectations(t)
 }
 
+func TestUserHandler_DeleteUserByID_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("DeleteUserByID", mock.Anything, "11").Return(domain.ErrNotFound)
+	mockUserService.On("DeleteUserByID", mock.Anything, "x").Return(domain.ErrBadParamInput)
+	mockUserService.On("DeleteUserByID", mock.Anything, "12").Return(errors.New("db down"))
+
+	router := gin.Default()
+	router.DELETE("/users/:id", userHandler.DeleteUserByID)
+
+	for id, want := range map[string]int{"11": http.StatusNotFound, "x": http.StatusBadRequest, "12": http.StatusInternalServerError} {
+		req, _ := http.NewRequest(http.MethodDelete, "/users/"+id, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, id)
+	}
+}
+
+func TestUserHandler_RestoreUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("RestoreUser", mock.Anything, uint(10)).Return(nil)
+	mockUserService.On("RestoreUser", mock.Anything, uint(11)).Return(domain.ErrNotFound)
+
+	router := gin.Default()
+	router.POST("/users/:id/restore", userHandler.RestoreUser)
+
+	for id, want := range map[string]int{"10": http.StatusNoContent, "11": http.StatusNotFound, "x": http.StatusBadRequest} {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, id)
+	}
+
+	mockUserService.AssertExpectations(t)
+}
+
 func TestUserHandler_LoginUser(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	mockUserService := new(mocks.UserService)
 	userHandler := rest.UserHandler{Service: mockUserService}
 
-	mockToken := "mockToken123"
-	mockUserService.On("AuthenticateUser", "john@example.com", "password123").Return(mockToken, nil)
+	mockTokens := &domain.AuthTokens{
+		AccessToken:  "mockToken123",
+		RefreshToken: "mockRefresh123",
+		ExpiresAt:    time.Date(2040, 7, 10, 0, 38, 44, 0, time.UTC),
+	}
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").Return(mockTokens, nil)
 
 	router := gin.Default()
 	router.POST("/auth/login", userHandler.LoginUser)
@@ -210,8 +392,707 @@ func TestUserHandler_LoginUser(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 
-	expectedResponse := `{"token":"mockToken123"}`
+	expectedResponse := `{"token":"mockToken123","refreshToken":"mockRefresh123","expiresAt":"2040-07-10T00:38:44Z"}`
+	assert.JSONEq(t, expectedResponse, w.Body.String())
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_RefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockTokens := &domain.AuthTokens{
+		AccessToken:  "newToken",
+		RefreshToken: "newRefresh",
+		ExpiresAt:    time.Date(2040, 7, 10, 0, 38, 44, 0, time.UTC),
+	}
+	mockUserService.On("RefreshToken", mock.Anything, "oldRefresh").Return(mockTokens, nil)
+
+	router := gin.Default()
+	router.POST("/auth/refresh", userHandler.RefreshToken)
+
+	body := `{"refreshToken":"oldRefresh"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	expectedResponse := `{"token":"newToken","refreshToken":"newRefresh","expiresAt":"2040-07-10T00:38:44Z"}`
 	assert.JSONEq(t, expectedResponse, w.Body.String())
 
 	mockUserService.AssertExpectations(t)
 }
+
+func TestUserHandler_RefreshToken_Invalid(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("RefreshToken", mock.Anything, "spent").Return(nil, errors.New("refresh token reused"))
+
+	router := gin.Default()
+	router.POST("/auth/refresh", userHandler.RefreshToken)
+
+	body := `{"refreshToken":"spent"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.JSONEq(t, `{"error":"refresh token reused"}`, w.Body.String())
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_LogoutUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("Logout", mock.Anything, "accessToken", "refreshToken").Return(nil)
+
+	router := gin.Default()
+	router.POST("/auth/logout", userHandler.LogoutUser)
+
+	body := `{"refreshToken":"refreshToken"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "accessToken")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_RevokeUserSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("RevokeUserSessions", mock.Anything, uint(10)).Return(nil)
+
+	router := gin.Default()
+	router.DELETE("/users/:id/sessions", userHandler.RevokeUserSessions)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/users/10/sessions", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_UnlockUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("UnlockUser", mock.Anything, uint(10)).Return(nil)
+	mockUserService.On("UnlockUser", mock.Anything, uint(11)).Return(domain.ErrNotFound)
+
+	router := gin.Default()
+	router.POST("/users/:id/unlock", userHandler.UnlockUser)
+
+	for id, want := range map[string]int{"10": http.StatusNoContent, "11": http.StatusNotFound, "x": http.StatusBadRequest} {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id+"/unlock", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, want, w.Code, id)
+	}
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_Register(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("Register", mock.Anything, mock.MatchedBy(func(user *domain.User) bool {
+		return user.Email == "john@example.com" && user.Password == "password123" && len(user.Roles) == 0
+	}), "invite-token").Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.User).ID = 12
+	}).Return(nil)
+
+	router := gin.Default()
+	router.POST("/auth/register", userHandler.Register)
+
+	body := `{"name":"John","email":"john@example.com","password":"password123","role":"admin","invitation":"invite-token"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Contains(t, w.Body.String(), `"id":12`)
+	assert.NotContains(t, w.Body.String(), "password123")
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_Register_Errors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"disabled", fmt.Errorf("%w: registration is disabled", domain.ErrForbidden), http.StatusForbidden},
+		{"duplicate", fmt.Errorf("%w: email is already registered", domain.ErrConflict), http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserService := new(mocks.UserService)
+			userHandler := rest.UserHandler{Service: mockUserService}
+			mockUserService.On("Register", mock.Anything, mock.Anything, "").Return(tt.err)
+
+			router := gin.Default()
+			router.POST("/auth/register", userHandler.Register)
+
+			body := `{"name":"John","email":"john@example.com","password":"password123"}`
+			req, _ := http.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+}
+
+func TestUserHandler_VerifyEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("VerifyEmail", mock.Anything, "good").Return(nil)
+	mockUserService.On("VerifyEmail", mock.Anything, "bad").
+		Return(fmt.Errorf("%w: invalid or expired verification token", domain.ErrBadParamInput))
+
+	router := gin.Default()
+	router.POST("/auth/verify", userHandler.VerifyEmail)
+
+	for token, status := range map[string]int{"good": http.StatusNoContent, "bad": http.StatusBadRequest} {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/verify", strings.NewReader(`{"token":"`+token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, status, w.Code, token)
+	}
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_LoginUser_Unverified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").
+		Return(nil, domain.ErrEmailNotVerified)
+
+	router := gin.Default()
+	router.POST("/auth/login", userHandler.LoginUser)
+
+	body := `{"email":"john@example.com", "password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestUserHandler_LoginUser_Locked(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").
+		Return(nil, &domain.TooManyRequestsError{RetryAfter: 90500 * time.Millisecond})
+
+	router := gin.Default()
+	router.POST("/auth/login", userHandler.LoginUser)
+
+	body := `{"email":"john@example.com", "password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "91", w.Header().Get("Retry-After"))
+}
+
+func TestUserHandler_LoginUser_Inactive(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").
+		Return(nil, fmt.Errorf("%w (suspended)", domain.ErrAccountInactive))
+
+	router := gin.Default()
+	router.POST("/auth/login", userHandler.LoginUser)
+
+	body := `{"email":"john@example.com", "password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.JSONEq(t, `{"error":"account is not active (suspended)"}`, w.Body.String())
+}
+
+func TestUserHandler_ChangeUserStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("ChangeUserStatus", mock.Anything, uint(10), domain.UserStatusSuspended, "Spam").
+		Return(&domain.User{ID: 10, Email: "john@example.com", Name: "John Doe", Status: domain.UserStatusSuspended}, nil)
+	mockUserService.On("ChangeUserStatus", mock.Anything, uint(10), domain.UserStatusLocked, "Spam").
+		Return(nil, fmt.Errorf("%w: a suspended user can not become locked", domain.ErrConflict))
+
+	router := gin.Default()
+	router.PUT("/users/:id/status", userHandler.ChangeUserStatus)
+
+	changeStatus := func(body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPut, "/users/10/status", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := changeStatus(`{"status":"suspended","reason":"Spam"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":10,"email":"john@example.com","name":"John Doe","status":"suspended","createdAt":"0001-01-01T00:00:00Z"}`, w.Body.String())
+	assert.Equal(t, http.StatusConflict, changeStatus(`{"status":"locked","reason":"Spam"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, changeStatus(`{"status":"suspended"}`).Code, "a reason is required")
+	assert.Equal(t, http.StatusBadRequest, changeStatus(`{"status":"suspended","reason":"`+strings.Repeat("a", 1001)+`"}`).Code, "the reason is too long")
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_GetUserStatusHistory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	actorID := uint(1)
+	changedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	mockUserService.On("GetUserStatusHistory", mock.Anything, uint(10)).Return([]domain.UserStatusChange{
+		{ID: 2, UserID: 10, FromStatus: "active", ToStatus: "suspended", Reason: "Spam", ActorID: &actorID, CreatedAt: changedAt},
+		{ID: 1, UserID: 10, FromStatus: "pending", ToStatus: "active", Reason: "Email address verified", CreatedAt: changedAt},
+	}, nil)
+
+	router := gin.Default()
+	router.GET("/users/:id/status-history", userHandler.GetUserStatusHistory)
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/10/status-history", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[
+		{"id":2,"fromStatus":"active","toStatus":"suspended","reason":"Spam","actorId":1,"createdAt":"2024-05-01T12:00:00Z"},
+		{"id":1,"fromStatus":"pending","toStatus":"active","reason":"Email address verified","actorId":null,"createdAt":"2024-05-01T12:00:00Z"}
+	]`, w.Body.String())
+}
+
+func TestNewUserHandler_RateLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").
+		Return(&domain.AuthTokens{AccessToken: "mockToken123"}, nil)
+	readers := []string{domain.PermissionUsersRead}
+	mockUserService.On("ValidateToken", mock.Anything, "token-7").Return(&domain.Principal{UserID: 7, Permissions: readers}, nil)
+	mockUserService.On("ValidateToken", mock.Anything, "token-8").Return(&domain.Principal{UserID: 8, Permissions: readers}, nil)
+	mockUserService.On("GetUserByID", mock.Anything, mock.Anything).Return(&domain.User{ID: 7}, nil)
+
+	router := gin.New()
+	rest.NewUserHandler(router, mockUserService, rest.UserRateLimits{
+		Limiter: ratelimit.NewMemory(),
+		Auth:    middleware.RateLimitRule{Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}, Key: middleware.KeyByIP},
+		Users:   middleware.RateLimitRule{Limit: ratelimit.Limit{Requests: 1, Period: time.Minute}, Key: middleware.KeyByUser},
+	})
+
+	login := func() int {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(`{"email":"john@example.com", "password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	getUser := func(token string) int {
+		req, _ := http.NewRequest(http.MethodGet, "/users/7", nil)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, login())
+	assert.Equal(t, http.StatusTooManyRequests, login())
+	assert.Equal(t, http.StatusOK, getUser("token-7"), "groups have separate limits")
+	assert.Equal(t, http.StatusTooManyRequests, getUser("token-7"))
+	assert.Equal(t, http.StatusOK, getUser("token-8"), "/users is limited per user")
+}
+
+func TestNewUserHandler_ReadingUsersNeedsPermission(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("ValidateToken", mock.Anything, "reader").
+		Return(&domain.Principal{UserID: 7, Permissions: []string{domain.PermissionUsersRead}}, nil)
+	mockUserService.On("ValidateToken", mock.Anything, "nobody").Return(&domain.Principal{UserID: 8}, nil)
+	mockUserService.On("GetAllUsers", mock.Anything, mock.Anything).Return(&domain.UserPage{}, nil)
+	mockUserService.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7}, nil)
+
+	router := gin.New()
+	rest.NewUserHandler(router, mockUserService, rest.UserRateLimits{})
+
+	get := func(path, token string) int {
+		req, _ := http.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Authorization", token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, get("/users", "reader"))
+	assert.Equal(t, http.StatusOK, get("/users/7", "reader"))
+	assert.Equal(t, http.StatusForbidden, get("/users", "nobody"))
+	assert.Equal(t, http.StatusForbidden, get("/users/7", "nobody"))
+}
+
+func TestUserHandler_ForgotPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("ForgotPassword", mock.Anything, "john@example.com").Return(nil)
+
+	router := gin.Default()
+	router.POST("/auth/forgot-password", userHandler.ForgotPassword)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/forgot-password", strings.NewReader(`{"email":"john@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_ResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("ResetPassword", mock.Anything, "good", "new-password").Return(nil)
+	mockUserService.On("ResetPassword", mock.Anything, "bad", "new-password").
+		Return(fmt.Errorf("%w: invalid or expired reset token", domain.ErrBadParamInput))
+
+	router := gin.Default()
+	router.POST("/auth/reset-password", userHandler.ResetPassword)
+
+	tests := map[string]int{
+		`{"token":"good","password":"new-password"}`: http.StatusNoContent,
+		`{"token":"bad","password":"new-password"}`:  http.StatusBadRequest,
+		`{"token":"good","password":"short"}`:        http.StatusBadRequest,
+	}
+	for body, status := range tests {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/reset-password", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, status, w.Code, body)
+	}
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_LoginUser_MFAChallenge(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("AuthenticateUser", mock.Anything, "john@example.com", "password123").
+		Return(&domain.AuthTokens{MFAToken: "challenge", MFAEnrollmentRequired: true}, nil)
+
+	router := gin.Default()
+	router.POST("/auth/login", userHandler.LoginUser)
+
+	body := `{"email":"john@example.com", "password":"password123"}`
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"mfaRequired":true,"mfaToken":"challenge","enrollmentRequired":true}`, w.Body.String())
+}
+
+func TestUserHandler_EnrollAndConfirmMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("EnrollMFA", mock.Anything, uint(3)).
+		Return(&domain.MFAEnrollment{Secret: "SECRET", URI: "otpauth://totp/BB:john?secret=SECRET"}, nil)
+	mockUserService.On("ConfirmMFA", mock.Anything, uint(3), "123456").Return([]string{"abcde-12345"}, nil)
+
+	router := gin.Default()
+	setUser := func(c *gin.Context) { middleware.SetPrincipal(c, &domain.Principal{UserID: 3}) }
+	router.POST("/auth/mfa/enroll", setUser, userHandler.EnrollMFA)
+	router.POST("/auth/mfa/confirm", setUser, userHandler.ConfirmMFA)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/mfa/enroll", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"secret":"SECRET","uri":"otpauth://totp/BB:john?secret=SECRET"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodPost, "/auth/mfa/confirm", strings.NewReader(`{"code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"recoveryCodes":["abcde-12345"]}`, w.Body.String())
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_VerifyMFA(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("VerifyMFA", mock.Anything, "challenge", "123456").Return(&domain.AuthTokens{
+		AccessToken: "mockToken123",
+		ExpiresAt:   time.Date(2040, 7, 10, 0, 38, 44, 0, time.UTC),
+	}, nil)
+	mockUserService.On("VerifyMFA", mock.Anything, "challenge", "000000").Return(nil, errors.New("invalid MFA code"))
+	mockUserService.On("VerifyMFA", mock.Anything, "challenge", "111111").Return(nil, &domain.TooManyRequestsError{
+		Message:    "too many failed logins, try again later",
+		RetryAfter: time.Minute,
+	})
+
+	router := gin.Default()
+	router.POST("/auth/mfa/verify", userHandler.VerifyMFA)
+
+	serve := func(code string) *httptest.ResponseRecorder {
+		body := `{"mfaToken":"challenge","code":"` + code + `"}`
+		req, _ := http.NewRequest(http.MethodPost, "/auth/mfa/verify", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("123456")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"token":"mockToken123","expiresAt":"2040-07-10T00:38:44Z"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusUnauthorized, serve("000000").Code)
+
+	w = serve("111111")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "60", w.Header().Get("Retry-After"))
+}
+
+func TestUserHandler_OIDCLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	userHandler := rest.UserHandler{Service: mockUserService}
+
+	mockUserService.On("BeginOIDCLogin", mock.Anything, "corp").
+		Return(&domain.OIDCLogin{AuthURL: "https://idp.example.com/authorize?state=s1", LoginToken: "login-token"}, nil)
+	mockUserService.On("BeginOIDCLogin", mock.Anything, "other").
+		Return(nil, fmt.Errorf("%w: unknown provider", domain.ErrNotFound))
+	mockUserService.On("CompleteOIDCLogin", mock.Anything, "corp", "code-2", "s1", "login-token").Return(&domain.AuthTokens{MFAToken: "mfa-token"}, nil)
+	mockUserService.On("CompleteOIDCLogin", mock.Anything, "corp", "code-1", "s1", "login-token").Return(&domain.AuthTokens{
+		AccessToken: "mockToken123",
+		ExpiresAt:   time.Date(2040, 7, 10, 0, 38, 44, 0, time.UTC),
+	}, nil)
+
+	router := gin.Default()
+	router.GET("/auth/oidc/:provider/login", userHandler.BeginOIDCLogin)
+	router.GET("/auth/oidc/:provider/callback", userHandler.CompleteOIDCLogin)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/corp/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://idp.example.com/authorize?state=s1", w.Header().Get("Location"))
+	cookies := w.Result().Cookies()
+	if assert.Len(t, cookies, 1) {
+		assert.Equal(t, "login-token", cookies[0].Value)
+		assert.Equal(t, "/auth/oidc/corp", cookies[0].Path)
+		assert.True(t, cookies[0].HttpOnly)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/other/login", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback?code=code-1&state=s1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code, "the login cookie is required")
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback?code=code-1&state=s1", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"token":"mockToken123","expiresAt":"2040-07-10T00:38:44Z"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback?code=code-2&state=s1", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"mfaRequired":true,"mfaToken":"mfa-token"}`, w.Body.String())
+
+	req, _ = http.NewRequest(http.MethodGet, "/auth/oidc/corp/callback?error=access_denied", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestNewUserHandler_Me(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	mockUserService := new(mocks.UserService)
+	mockUserService.On("ValidateToken", mock.Anything, "token-7").Return(&domain.Principal{
+		UserID:      7,
+		Email:       "john@example.com",
+		Roles:       []string{"admin"},
+		Permissions: []string{domain.PermissionUsersRead},
+		SessionID:   "s1",
+		User:        &domain.User{ID: 7, Email: "john@example.com", Name: "John Doe"},
+	}, nil)
+	mockUserService.On("UpdateUserByID", mock.Anything, "7", domain.User{Name: "John Smith"}).
+		Return(&domain.User{ID: 7, Email: "john@example.com", Name: "John Smith"}, nil)
+	mockUserService.On("ChangePassword", mock.Anything, uint(7), "password123", "new-secret").Return(nil)
+	mockUserService.On("ChangePassword", mock.Anything, uint(7), "wrong", "new-secret").
+		Return(fmt.Errorf("%w: the current password is wrong", domain.ErrBadParamInput))
+	mockUserService.On("ChangePassword", mock.Anything, uint(7), "guess", "new-secret").
+		Return(&domain.TooManyRequestsError{RetryAfter: 30 * time.Second})
+
+	router := gin.New()
+	rest.NewUserHandler(router, mockUserService, rest.UserRateLimits{})
+
+	request := func(method, path, token, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if token != "" {
+			req.Header.Set("Authorization", token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, request(http.MethodGet, "/me", "", "").Code)
+
+	w := request(http.MethodGet, "/me", "token-7", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7,"email":"john@example.com","name":"John Doe","roles":["admin"],
+		"permissions":["users:read"],"status":"active","sessionId":"s1"}`, w.Body.String())
+
+	w = request(http.MethodPatch, "/me", "token-7", `{"name":"John Smith"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7,"email":"john@example.com","name":"John Smith","status":"active","createdAt":"0001-01-01T00:00:00Z"}`, w.Body.String())
+	assert.Equal(t, http.StatusBadRequest, request(http.MethodPatch, "/me", "token-7", `{}`).Code)
+
+	changePassword := func(current string) *httptest.ResponseRecorder {
+		return request(http.MethodPost, "/me/password", "token-7", `{"currentPassword":"`+current+`","newPassword":"new-secret"}`)
+	}
+	assert.Equal(t, http.StatusNoContent, changePassword("password123").Code)
+	assert.Equal(t, http.StatusBadRequest, changePassword("wrong").Code)
+	w = changePassword("guess")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "30", w.Header().Get("Retry-After"))
+	assert.Equal(t, http.StatusBadRequest,
+		request(http.MethodPost, "/me/password", "token-7", `{"currentPassword":"password123","newPassword":"short"}`).Code)
+
+	mockUserService.AssertExpectations(t)
+}
+
+func TestUserHandler_GetMe_Stateless(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	userHandler := rest.UserHandler{Service: new(mocks.UserService)}
+
+	router := gin.New()
+	router.GET("/me", func(c *gin.Context) {
+		middleware.SetPrincipal(c, &domain.Principal{UserID: 7, Email: "john@example.com", Roles: []string{"user"}})
+	}, userHandler.GetMe)
+
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":7,"email":"john@example.com","roles":["user"],"permissions":[]}`, w.Body.String())
+}
@@ -1,33 +1,105 @@
 package internal
 
 import (
+	"context"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/cache"
 	"github.com/tat-101/bb-assignment-back/config"
 	"github.com/tat-101/bb-assignment-back/database"
-	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/health"
 	"github.com/tat-101/bb-assignment-back/internal/repository"
 	"github.com/tat-101/bb-assignment-back/internal/rest"
+	"github.com/tat-101/bb-assignment-back/internal/rest/middleware"
+	"github.com/tat-101/bb-assignment-back/mailer"
+	"github.com/tat-101/bb-assignment-back/metrics"
+	"github.com/tat-101/bb-assignment-back/oidc"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
+	"github.com/tat-101/bb-assignment-back/role"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"github.com/tat-101/bb-assignment-back/tracing"
 	"github.com/tat-101/bb-assignment-back/user"
+	"gorm.io/gorm"
 )
 
-func SetupServer() *gin.Engine {
-	cfg := config.LoadConfig()
+const serviceName = "bb-assignment-back"
 
-	db := database.Initialize(cfg)
+func SetupServer(cfg config.Config) *App {
+	if err := cfg.ValidateServer(); err != nil {
+		panic(err.Error())
+	}
 
-	err := db.AutoMigrate(&domain.User{})
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:       cfg.TracingExporter,
+		Endpoint:       cfg.OTLPEndpoint,
+		File:           cfg.TracingFile,
+		ServiceName:    serviceName,
+		ServiceVersion: cfg.Version,
+	})
 	if err != nil {
-		panic("Failed to migrate database: " + err.Error())
+		panic("Failed to set up tracing: " + err.Error())
+	}
+
+	// Validated by config.Load.
+	routeTimeouts, _ := cfg.RouteTimeoutMap()
+	rateLimits, _ := cfg.RateLimitRules()
+	keys := jwtKeyRing(cfg)
+
+	db := database.Initialize(cfg)
+	if err := tracing.InstrumentDB(db); err != nil {
+		panic("Failed to instrument database: " + err.Error())
+	}
+
+	appMetrics := metrics.New()
+	if err := appMetrics.InstrumentDB(db); err != nil {
+		panic("Failed to instrument database: " + err.Error())
+	}
+
+	healthService := health.NewService(cfg.HealthCheckTimeout)
+	healthService.Register("database", health.Database(db))
+	healthService.Register("migrations", health.Migrations(db))
+
+	if cfg.MigrateOnBoot {
+		if err := database.Migrate(db); err != nil {
+			panic("Failed to migrate database: " + err.Error())
+		}
 	}
 
 	userRepo := repository.NewUserRepository(db)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	revocationRepo := repository.NewTokenRevocationRepository(db)
+	passwordResetRepo := repository.NewPasswordResetTokenRepository(db)
+	mfaRepo := repository.NewMFARepository(db)
+	identityRepo := repository.NewIdentityRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
 
-	r := gin.Default()
+	r := gin.New()
+	// Validated by config.Load. Without a list no proxy is trusted, as gin
+	// would otherwise trust every one.
+	proxies, _ := cfg.TrustedProxyList()
+	if err := r.SetTrustedProxies(proxies); err != nil {
+		panic("Failed to set trusted proxies: " + err.Error())
+	}
+	r.Use(
+		middleware.RequestID(),
+		middleware.RequestInfo(),
+		middleware.Tracing(),
+		middleware.RequestLogger(slog.Default()),
+		middleware.Recovery(slog.Default()),
+		middleware.Timeout(cfg.RequestTimeout, routeTimeouts),
+	)
+	app := NewApp(cfg, r, db)
+	app.Health = healthService
+	app.OnShutdown("tracing", shutdownTracing)
+	r.Use(middleware.Metrics(appMetrics))
 	r.Use(cors.New(cors.Config{
 		AllowOriginFunc: func(origin string) bool {
 			if strings.HasPrefix(origin, "http://localhost:") {
@@ -38,20 +110,219 @@ func SetupServer() *gin.Engine {
 			}
 			return false
 		},
-		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE"},
-		AllowHeaders:     []string{"Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
+		AllowHeaders:     []string{"Content-Type", "Authorization", middleware.RequestIDHeader, middleware.MFATokenHeader, middleware.APIKeyHeader, "traceparent", "tracestate"},
+		ExposeHeaders:    []string{"Content-Length", middleware.RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
+	redisClient := newRedisClient(cfg, app)
+	limiter := rateLimiter(cfg, redisClient)
+	// After CORS, so browsers can read the 429.
+	r.Use(middleware.RateLimit(limiter, "global", rateLimitRule(rateLimits.Global)))
 	r.GET("/version", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"version": cfg.Version,
 		})
 	})
+	rest.NewHealthHandler(r, healthService)
+	rest.NewJWKSHandler(r, keys)
+	r.GET("/metrics", gin.WrapH(appMetrics.Handler()))
+
+	auditService := audit.NewService(auditRepo)
+	mail := newMailer(cfg)
+	frontendURL := strings.TrimSuffix(cfg.FrontendURL, "/")
+	userService := user.NewService(userRepo,
+		user.WithAccessTokenTTL(cfg.AccessTokenTTL),
+		user.WithKeyRing(keys),
+		user.WithTokenValidation(cfg.TokenValidation),
+		user.WithPrincipalCache(principalCache(cfg, redisClient)),
+		user.WithLoginLockout(loginAttemptStore(cfg, db), loginLockout(cfg)),
+		user.WithRefreshTokens(refreshTokenRepo, cfg.RefreshTokenTTL),
+		user.WithRevocationStore(revocationRepo),
+		user.WithMetrics(appMetrics),
+		user.WithAuditor(auditService),
+		user.WithDeletedUserRetention(cfg.DeletedUserRetention),
+		user.WithRegistration(mail, user.RegistrationConfig{
+			Mode:            cfg.RegistrationMode,
+			Secret:          cfg.TokenKey("registration"),
+			VerifyURL:       frontendURL + "/verify-email",
+			InviteURL:       frontendURL + "/register",
+			VerificationTTL: cfg.VerificationTokenTTL,
+			InvitationTTL:   cfg.InvitationTTL,
+		}),
+		user.WithPasswordReset(passwordResetRepo, mail, user.PasswordResetConfig{
+			URL: frontendURL + "/reset-password",
+			TTL: cfg.PasswordResetTTL,
+		}),
+		user.WithMFA(mfaRepo, user.MFAConfig{
+			Issuer:        cfg.MFAIssuer,
+			Secret:        cfg.TokenKey("mfa"),
+			ChallengeTTL:  cfg.MFAChallengeTTL,
+			RequiredRoles: cfg.MFARequiredRoleList(),
+		}),
+		user.WithOIDC(oidcProviders(cfg), identityRepo, user.OIDCConfig{
+			Secret: cfg.TokenKey("oidc"),
+		}),
+	)
+	app.OnShutdown("password reset mail", userService.Wait)
+	app.AddWorker(periodically(time.Hour, "prune token revocations", func(ctx context.Context) error {
+		_, err := userService.PruneRevocations(ctx)
+		return err
+	}))
+	app.AddWorker(periodically(time.Hour, "prune login attempts", func(ctx context.Context) error {
+		_, err := userService.PruneLoginAttempts(ctx)
+		return err
+	}))
+	app.AddWorker(periodically(time.Hour, "purge deleted users", func(ctx context.Context) error {
+		purged, err := userService.PurgeDeletedUsers(ctx)
+		if purged > 0 {
+			slog.InfoContext(ctx, "Purged deleted users", "count", purged)
+		}
+		return err
+	}))
+	rest.NewUserHandler(r, userService, rest.UserRateLimits{
+		Limiter: limiter,
+		Auth:    rateLimitRule(rateLimits.Auth),
+		Users:   rateLimitRule(rateLimits.Users),
+	})
+
+	roleService := role.NewService(roleRepo,
+		role.WithPrincipalInvalidator(userService),
+		role.WithAuditor(auditService),
+	)
+	rest.NewRoleHandler(r, roleService, userService)
+	rest.NewAuditHandler(r, auditService, userService)
+
+	healthService.SetReady(true, "")
+	return app
+}
 
-	userService := user.NewService(userRepo)
-	rest.NewUserHandler(r, userService)
+// periodically runs task every interval until the app shuts down.
+func periodically(interval time.Duration, task string, run func(ctx context.Context) error) Worker {
+	return func(ctx context.Context) {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := run(ctx); err != nil {
+					slog.ErrorContext(ctx, "Failed to "+task, "error", err)
+				}
+			}
+		}
+	}
+}
 
-	return r
+// newRedisClient connects to REDIS_URL when a feature uses Redis.
+func newRedisClient(cfg config.Config, app *App) *redis.Client {
+	if !cfg.UsesRedis() {
+		return nil
+	}
+	options, err := redis.ParseURL(cfg.RedisURL)
+	if err != nil {
+		panic("Failed to parse REDIS_URL: " + err.Error())
+	}
+	client := redis.NewClient(options)
+	app.OnShutdown("redis", func(context.Context) error { return client.Close() })
+	return client
+}
+
+func principalCache(cfg config.Config, client *redis.Client) user.PrincipalCache {
+	switch cfg.PrincipalCache {
+	case "lru":
+		return cache.NewLRU(cfg.PrincipalCacheSize, cfg.PrincipalCacheTTL)
+	case "redis":
+		return cache.NewRedis(client, cfg.PrincipalCacheTTL, "bb:principal:")
+	default:
+		return nil
+	}
+}
+
+func rateLimiter(cfg config.Config, client *redis.Client) ratelimit.Limiter {
+	if cfg.RateLimitBackend == "redis" {
+		return ratelimit.NewRedis(client, "bb:ratelimit:")
+	}
+	return ratelimit.NewMemory()
+}
+
+var rateLimitKeys = map[string]middleware.RateLimitKey{
+	"ip":      middleware.KeyByIP,
+	"user":    middleware.KeyByUser,
+	"api-key": middleware.KeyByAPIKey,
+}
+
+func rateLimitRule(limit config.RateLimit) middleware.RateLimitRule {
+	return middleware.RateLimitRule{
+		Limit: ratelimit.Limit{Requests: limit.Requests, Period: limit.Period},
+		Key:   rateLimitKeys[limit.Key],
+	}
+}
+
+func loginAttemptStore(cfg config.Config, db *gorm.DB) user.LoginAttemptStore {
+	if cfg.LoginLockoutStore == "memory" {
+		return repository.NewMemoryLoginAttemptRepository()
+	}
+	return repository.NewLoginAttemptRepository(db)
+}
+
+func loginLockout(cfg config.Config) user.LockoutConfig {
+	account := user.DefaultAccountLockout
+	account.MaxFailures = cfg.LoginMaxFailures
+	account.LockoutDuration = cfg.LoginLockoutDuration
+	clientIP := user.DefaultClientIPLockout
+	clientIP.MaxFailures = cfg.LoginIPMaxFailures
+	clientIP.LockoutDuration = cfg.LoginLockoutDuration
+	return user.LockoutConfig{Account: account, ClientIP: clientIP, Window: cfg.LoginFailureWindow}
+}
+
+func jwtKeyRing(cfg config.Config) *tools.KeyRing {
+	// Validated by config.Load.
+	keys, _ := cfg.JWTKeyRing()
+	if keys != nil {
+		return keys
+	}
+	// Only allowed in development by ValidateServer.
+	slog.Warn("JWT_SIGNING_KEY is not set; signing access tokens with a temporary key that other instances do not know and that is lost on restart")
+	keys, err := tools.GenerateKeyRing(cfg.JWTIssuer, cfg.JWTAudience)
+	if err != nil {
+		panic("Failed to generate a JWT signing key: " + err.Error())
+	}
+	return keys
+}
+
+func newMailer(cfg config.Config) mailer.Mailer {
+	switch cfg.Mailer {
+	case "smtp":
+		return &mailer.SMTPMailer{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.MailFrom,
+		}
+	case "file":
+		return &mailer.FileMailer{Dir: cfg.MailDir, From: cfg.MailFrom}
+	default:
+		return &mailer.LogMailer{Logger: slog.Default()}
+	}
+}
+
+func oidcProviders(cfg config.Config) map[string]user.OIDCProvider {
+	// Validate has already parsed the list.
+	list, _ := cfg.OIDCProviderList()
+	providers := make(map[string]user.OIDCProvider, len(list))
+	for _, p := range list {
+		providers[p.Name] = oidc.NewProvider(oidc.Config{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+	return providers
 }
@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger sends gorm's logs to slog. Queries are logged at debug level,
+// queries slower than SlowThreshold at warn and failed queries at error.
+// Record-not-found errors are expected and not treated as failures.
+type GormLogger struct {
+	Logger        *slog.Logger
+	SlowThreshold time.Duration
+}
+
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration) *GormLogger {
+	return &GormLogger{Logger: logger, SlowThreshold: slowThreshold}
+}
+
+// ParamsFilter keeps bound parameters out of logged SQL, since they include
+// password and token hashes.
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	return sql, nil
+}
+
+// LogMode is a no-op; the level is controlled by the slog logger.
+func (l *GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	l.Logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	l.Logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	l.Logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		if !l.Logger.Enabled(ctx, slog.LevelError) {
+			return
+		}
+		sql, rows := fc()
+		l.Logger.ErrorContext(ctx, "query failed", "error", err, "sql", sql, "rows", rows, "elapsed", elapsed)
+	case l.SlowThreshold > 0 && elapsed > l.SlowThreshold:
+		if !l.Logger.Enabled(ctx, slog.LevelWarn) {
+			return
+		}
+		sql, rows := fc()
+		l.Logger.WarnContext(ctx, "slow query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	default:
+		if !l.Logger.Enabled(ctx, slog.LevelDebug) {
+			return
+		}
+		sql, rows := fc()
+		l.Logger.DebugContext(ctx, "query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
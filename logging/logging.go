@@ -0,0 +1,132 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const redacted = "[REDACTED]"
+
+// New returns a JSON logger that writes records at level and above to w.
+// Records logged with a request context carry its request ID, user ID and
+// trace ID, and sensitive attributes are redacted.
+func New(w io.Writer, level slog.Leveler) *slog.Logger {
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: redact,
+	})
+	return slog.New(&contextHandler{Handler: handler})
+}
+
+// ParseLevel parses debug, info, warn or error.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+	return level, err
+}
+
+// IsSensitive reports whether an attribute or header named key must not be
+// logged verbatim.
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	key = strings.NewReplacer("_", "", "-", "").Replace(key)
+	switch key {
+	case "authorization", "cookie", "setcookie":
+		return true
+	}
+	return strings.Contains(key, "password") ||
+		strings.Contains(key, "secret") ||
+		strings.Contains(key, "token")
+}
+
+func redact(groups []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, redacted)
+	}
+	if header, ok := a.Value.Any().(http.Header); ok {
+		clean := make(http.Header, len(header))
+		for name, values := range header {
+			if IsSensitive(name) {
+				clean[name] = []string{redacted}
+			} else {
+				clean[name] = values
+			}
+		}
+		return slog.Any(a.Key, clean)
+	}
+	return a
+}
+
+type fieldsKey struct{}
+
+// requestFields is shared by everything handling one request so that values
+// learned late, such as the authenticated user, show up in later log lines.
+type requestFields struct {
+	mu        sync.RWMutex
+	requestID string
+	userID    uint
+}
+
+// WithRequestID starts tracking log fields for a request.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, fieldsKey{}, &requestFields{requestID: requestID})
+}
+
+// RequestID returns the request ID stored in ctx, if any.
+func RequestID(ctx context.Context) string {
+	fields, ok := ctx.Value(fieldsKey{}).(*requestFields)
+	if !ok {
+		return ""
+	}
+	fields.mu.RLock()
+	defer fields.mu.RUnlock()
+	return fields.requestID
+}
+
+// SetUserID records the authenticated user for the request in ctx. It is a
+// no-op for contexts not created by WithRequestID.
+func SetUserID(ctx context.Context, userID uint) {
+	fields, ok := ctx.Value(fieldsKey{}).(*requestFields)
+	if !ok {
+		return
+	}
+	fields.mu.Lock()
+	defer fields.mu.Unlock()
+	fields.userID = userID
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if fields, ok := ctx.Value(fieldsKey{}).(*requestFields); ok {
+		fields.mu.RLock()
+		record.AddAttrs(slog.String("request_id", fields.requestID))
+		if fields.userID != 0 {
+			record.AddAttrs(slog.Uint64("user_id", uint64(fields.userID)))
+		}
+		fields.mu.RUnlock()
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", spanContext.TraceID().String()),
+			slog.String("span_id", spanContext.SpanID().String()),
+		)
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
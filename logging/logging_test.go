@@ -0,0 +1,101 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/logging"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func decode(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	return record
+}
+
+func TestNew_RequestFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+	logging.SetUserID(ctx, 42)
+	logger.InfoContext(ctx, "hello")
+
+	record := decode(t, &buf)
+	assert.Equal(t, "hello", record["msg"])
+	assert.Equal(t, "req-1", record["request_id"])
+	assert.Equal(t, float64(42), record["user_id"])
+	assert.Equal(t, "req-1", logging.RequestID(ctx))
+}
+
+func TestNew_TraceID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "request")
+	defer span.End()
+	logger.InfoContext(ctx, "hello")
+
+	record := decode(t, &buf)
+	assert.Equal(t, span.SpanContext().TraceID().String(), record["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record["span_id"])
+}
+
+func TestNew_Level(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelWarn)
+
+	logger.Info("hidden")
+	assert.Zero(t, buf.Len())
+
+	logger.Warn("shown")
+	assert.Equal(t, "WARN", decode(t, &buf)["level"])
+}
+
+func TestNew_Redacts(t *testing.T) {
+	var buf bytes.Buffer
+	logger := logging.New(&buf, slog.LevelInfo)
+
+	header := http.Header{}
+	header.Set("Authorization", "Bearer abc")
+	header.Set("Accept", "application/json")
+	logger.Info("login",
+		"email", "a@bb.com",
+		"password", "hunter2",
+		"refreshToken", "xyz",
+		slog.Group("db", "DB_PASSWORD", "secret"),
+		"headers", header,
+	)
+
+	out := buf.String()
+	assert.NotContains(t, out, "hunter2")
+	assert.NotContains(t, out, "xyz")
+	assert.NotContains(t, out, "Bearer abc")
+	assert.NotContains(t, out, `"secret"`)
+
+	record := decode(t, &buf)
+	assert.Equal(t, "a@bb.com", record["email"])
+	assert.Equal(t, "[REDACTED]", record["password"])
+	assert.Equal(t, "[REDACTED]", record["db"].(map[string]interface{})["DB_PASSWORD"])
+	headers := record["headers"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"[REDACTED]"}, headers["Authorization"])
+	assert.Equal(t, []interface{}{"application/json"}, headers["Accept"])
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("debug")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, level)
+
+	_, err = logging.ParseLevel("loud")
+	assert.Error(t, err)
+}
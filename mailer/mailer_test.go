@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileMailer_Send(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "mail")
+	m := &FileMailer{Dir: dir, From: "no-reply@example.com"}
+
+	err := m.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Verify your email address",
+		Body:    "line one\nline two\n",
+	})
+	require.NoError(t, err)
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), "-user_example.com.eml"), files[0].Name())
+
+	content, err := os.ReadFile(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "From: no-reply@example.com\r\n")
+	assert.Contains(t, string(content), "To: user@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Verify your email address\r\n")
+	assert.True(t, strings.HasSuffix(string(content), "\r\n\r\nline one\r\nline two\r\n"))
+}
+
+func TestFormat_HeaderInjection(t *testing.T) {
+	msg := format("no-reply@example.com", Message{
+		To:      "user@example.com\r\nBcc: victim@example.com",
+		Subject: "Hello\nBcc: victim@example.com",
+	})
+
+	for _, line := range strings.Split(string(msg), "\r\n") {
+		assert.False(t, strings.HasPrefix(line, "Bcc:"), "injected header %q", line)
+	}
+}
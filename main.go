@@ -1,13 +1,37 @@
 package main
 
 import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
 	"github.com/tat-101/bb-assignment-back/config"
 	"github.com/tat-101/bb-assignment-back/internal"
+	"github.com/tat-101/bb-assignment-back/logging"
 )
 
-// TODO: improve log
-
 func main() {
-	r := internal.SetupServer()
-	r.Run(":" + config.LoadConfig().ServerAddress)
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		slog.Error("Failed to load config", "error", err)
+		os.Exit(1)
+	}
+
+	level, _ := logging.ParseLevel(cfg.LogLevel)
+	slog.SetDefault(logging.New(os.Stdout, level))
+	if level > slog.LevelDebug {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	app := internal.SetupServer(cfg)
+	if err := app.Run(ctx); err != nil {
+		slog.Error("Server stopped with error", "error", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"gorm.io/gorm"
+)
+
+const queryStartKey = "metrics:query_start"
+
+// InstrumentDB times every gorm operation on db and exports the connection
+// pool statistics of its sql.DB.
+func (m *Metrics) InstrumentDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	if err := m.registry.Register(collectors.NewDBStatsCollector(sqlDB, "postgres")); err != nil {
+		return err
+	}
+
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("metrics:before_create", startQuery),
+		cb.Create().After("*").Register("metrics:after_create", m.observeQuery("create")),
+		cb.Query().Before("*").Register("metrics:before_query", startQuery),
+		cb.Query().After("*").Register("metrics:after_query", m.observeQuery("query")),
+		cb.Update().Before("*").Register("metrics:before_update", startQuery),
+		cb.Update().After("*").Register("metrics:after_update", m.observeQuery("update")),
+		cb.Delete().Before("*").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("*").Register("metrics:after_delete", m.observeQuery("delete")),
+		cb.Row().Before("*").Register("metrics:before_row", startQuery),
+		cb.Row().After("*").Register("metrics:after_row", m.observeQuery("row")),
+		cb.Raw().Before("*").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("*").Register("metrics:after_raw", m.observeQuery("raw")),
+	)
+}
+
+func startQuery(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+func (m *Metrics) observeQuery(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		m.dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+	}
+}
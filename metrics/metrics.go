@@ -0,0 +1,119 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "bb"
+
+// Metrics owns a Prometheus registry with the HTTP, authentication and
+// database collectors of this service.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	logins           *prometheus.CounterVec
+	tokenValidations *prometheus.CounterVec
+	principalCache   *prometheus.CounterVec
+	dbQueryDuration  *prometheus.HistogramVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_logins_total",
+			Help:      "Login attempts by outcome.",
+		}, []string{"outcome"}),
+		tokenValidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_token_validations_total",
+			Help:      "Access token validations by outcome.",
+		}, []string{"outcome"}),
+		principalCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_principal_cache_lookups_total",
+			Help:      "Principal cache lookups by result, hit or miss.",
+		}, []string{"result"}),
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database query latency by operation and table.",
+			Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.logins,
+		m.tokenValidations,
+		m.principalCache,
+		m.dbQueryDuration,
+	)
+	return m
+}
+
+// Registry exposes the underlying registry so other packages can add their
+// own collectors.
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// Handler serves the registry in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// ObserveHTTPRequest records a served request. route must be a route
+// template such as /users/:id, never a raw path, to keep cardinality bounded.
+// Methods other than the standard ones are counted as OTHER for the same
+// reason.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	method = methodLabel(method)
+	code := strconv.Itoa(status)
+	m.httpRequests.WithLabelValues(method, route, code).Inc()
+	m.httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+func methodLabel(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		return method
+	}
+	return "OTHER"
+}
+
+func (m *Metrics) ObserveLogin(outcome string) {
+	m.logins.WithLabelValues(outcome).Inc()
+}
+
+func (m *Metrics) ObserveTokenValidation(outcome string) {
+	m.tokenValidations.WithLabelValues(outcome).Inc()
+}
+
+func (m *Metrics) ObservePrincipalCache(result string) {
+	m.principalCache.WithLabelValues(result).Inc()
+}
@@ -0,0 +1,70 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := New()
+
+	m.ObserveHTTPRequest(http.MethodGet, "/users/:id", http.StatusOK, 20*time.Millisecond)
+	m.ObserveHTTPRequest(http.MethodGet, "/users/:id", http.StatusOK, 30*time.Millisecond)
+	m.ObserveHTTPRequest("FOO", "/users/:id", http.StatusNotFound, time.Millisecond)
+	m.ObserveHTTPRequest("BAR", "/users/:id", http.StatusNotFound, time.Millisecond)
+	m.ObserveLogin("success")
+	m.ObserveLogin("invalid_credentials")
+	m.ObserveLogin("invalid_credentials")
+	m.ObserveTokenValidation("revoked")
+	m.ObservePrincipalCache("hit")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("GET", "/users/:id", "200")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.httpRequests.WithLabelValues("OTHER", "/users/:id", "404")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.httpDuration))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.logins.WithLabelValues("invalid_credentials")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.tokenValidations.WithLabelValues("revoked")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.principalCache.WithLabelValues("hit")))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveLogin("success")
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `bb_auth_logins_total{outcome="success"} 1`)
+	assert.Contains(t, w.Body.String(), "go_goroutines")
+}
+
+func TestMetrics_InstrumentDB(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+
+	m := New()
+	require.NoError(t, m.InstrumentDB(db))
+
+	var users []domain.User
+	db.Where("email = ?", "a@bb.com").Find(&users)
+	db.Create(&domain.User{Email: "b@bb.com"})
+
+	assert.Equal(t, 2, testutil.CollectAndCount(m.dbQueryDuration))
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, w.Body.String(), `bb_db_query_duration_seconds_count{operation="query",table="users"} 1`)
+	assert.Contains(t, w.Body.String(), `go_sql_max_open_connections{db_name="postgres"}`)
+}
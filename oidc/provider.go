@@ -0,0 +1,122 @@
+// Package oidc signs users in at OpenID Connect providers with the
+// authorization code flow and PKCE.
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	gooidc "github.com/coreos/go-oidc/v3/oidc"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"golang.org/x/oauth2"
+)
+
+// Config describes one provider, as registered with it.
+type Config struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	// Scopes are requested in addition to "openid". They default to
+	// "email" and "profile".
+	Scopes []string
+}
+
+// Provider is a relying party of one OpenID Connect provider. The provider's
+// discovery document and keys are fetched on first use, so a provider that
+// is down does not keep the service from starting.
+type Provider struct {
+	cfg Config
+
+	mu       sync.Mutex
+	oauth2   *oauth2.Config
+	verifier *gooidc.IDTokenVerifier
+}
+
+func NewProvider(cfg Config) *Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"email", "profile"}
+	}
+	return &Provider{cfg: cfg}
+}
+
+// AuthCodeURL returns the provider's page to send the user to. The user comes
+// back to the redirect URL with state and a code for Exchange.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.AuthCodeURL(state, gooidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange redeems code and returns the identity from the verified ID token,
+// which must carry nonce.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error) {
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("oidc %s: exchange code: %w", p.cfg.Name, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("oidc %s: no id_token in token response", p.cfg.Name)
+	}
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("oidc %s: %w", p.cfg.Name, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("oidc %s: nonce mismatch", p.cfg.Name)
+	}
+
+	var claims struct {
+		Email string `json:"email"`
+		// Some providers send email_verified as a string.
+		EmailVerified interface{} `json:"email_verified"`
+		Name          string      `json:"name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("oidc %s: %w", p.cfg.Name, err)
+	}
+	verified, _ := strconv.ParseBool(fmt.Sprint(claims.EmailVerified))
+
+	return &domain.OIDCIdentity{
+		Provider:      p.cfg.Name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: verified,
+		Name:          claims.Name,
+	}, nil
+}
+
+func (p *Provider) discover(ctx context.Context) (*oauth2.Config, *gooidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.oauth2 != nil {
+		return p.oauth2, p.verifier, nil
+	}
+
+	// The provider keeps using the context to refresh its keys, so it must
+	// outlive the request that triggered discovery.
+	provider, err := gooidc.NewProvider(context.WithoutCancel(ctx), p.cfg.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("oidc %s: discovery: %w", p.cfg.Name, err)
+	}
+	p.oauth2 = &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       append([]string{gooidc.ScopeOpenID}, p.cfg.Scopes...),
+	}
+	p.verifier = provider.Verifier(&gooidc.Config{ClientID: p.cfg.ClientID})
+	return p.oauth2, p.verifier, nil
+}
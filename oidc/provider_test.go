@@ -0,0 +1,112 @@
+package oidc_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/oidc"
+	"github.com/tat-101/bb-assignment-back/oidc/oidctest"
+	"golang.org/x/oauth2"
+)
+
+func newProvider(issuer *oidctest.Issuer) *oidc.Provider {
+	return oidc.NewProvider(oidc.Config{
+		Name:         "corp",
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  "http://localhost:3000/auth/oidc/corp/callback",
+	})
+}
+
+func TestProvider_Login(t *testing.T) {
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+	provider := newProvider(issuer)
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+	assert.NotContains(t, authURL, verifier, "only the challenge is sent to the provider")
+
+	code, state, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, "state-1", state)
+
+	identity, err := provider.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "corp", identity.Provider)
+	assert.Equal(t, "subject-1", identity.Subject)
+	assert.Equal(t, "user@example.com", identity.Email)
+	assert.True(t, identity.EmailVerified)
+	assert.Equal(t, "Test User", identity.Name)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+	assert.Error(t, err, "a code can only be redeemed once")
+}
+
+func TestProvider_Exchange_WrongVerifier(t *testing.T) {
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+	provider := newProvider(issuer)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", oauth2.GenerateVerifier())
+	require.NoError(t, err)
+	code, _, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, oauth2.GenerateVerifier(), "nonce-1")
+
+	assert.Error(t, err)
+}
+
+func TestProvider_Exchange_WrongNonce(t *testing.T) {
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+	provider := newProvider(issuer)
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+	code, _, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-2")
+
+	assert.ErrorContains(t, err, "nonce mismatch")
+}
+
+func TestProvider_Exchange_WrongClient(t *testing.T) {
+	issuer := oidctest.NewIssuer("client", "secret")
+	defer issuer.Close()
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "corp",
+		Issuer:       issuer.URL,
+		ClientID:     "client",
+		ClientSecret: "wrong",
+		RedirectURL:  "http://localhost:3000/auth/oidc/corp/callback",
+	})
+
+	verifier := oauth2.GenerateVerifier()
+	authURL, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", verifier)
+	require.NoError(t, err)
+	code, _, err := issuer.Authorize(authURL)
+	require.NoError(t, err)
+
+	_, err = provider.Exchange(context.Background(), code, verifier, "nonce-1")
+
+	assert.Error(t, err)
+}
+
+func TestProvider_DiscoveryFailure(t *testing.T) {
+	issuer := oidctest.NewIssuer("client", "secret")
+	provider := newProvider(issuer)
+	issuer.Close()
+
+	_, err := provider.AuthCodeURL(context.Background(), "state-1", "nonce-1", oauth2.GenerateVerifier())
+
+	assert.ErrorContains(t, err, "discovery")
+}
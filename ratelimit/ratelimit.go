@@ -0,0 +1,71 @@
+// Package ratelimit limits how often something may happen with token
+// buckets. A bucket holds up to Limit.Requests tokens and refills at
+// Requests per Period; every request takes a token and is refused when the
+// bucket is empty, so a client can burst up to Requests at once but not
+// exceed the rate over time.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Limit allows Requests per Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// IsZero reports whether the limit is unset, meaning unlimited.
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// Result is the state of a bucket after a request.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// ResetAfter is how long until the bucket is full again.
+	ResetAfter time.Duration
+	// RetryAfter is how long until the next request is allowed. It is zero
+	// when the request was allowed.
+	RetryAfter time.Duration
+}
+
+// Limiter takes a token from the bucket named key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// refill returns the tokens in a bucket that held tokens elapsed ago.
+func refill(tokens float64, elapsed time.Duration, limit Limit) float64 {
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	return math.Min(float64(limit.Requests), tokens+elapsed.Seconds()*rate(limit))
+}
+
+// rate is the number of tokens added per second.
+func rate(limit Limit) float64 {
+	return float64(limit.Requests) / limit.Period.Seconds()
+}
+
+// result describes a bucket left with tokens after a request.
+func result(allowed bool, tokens float64, limit Limit) Result {
+	r := Result{
+		Allowed:    allowed,
+		Limit:      limit.Requests,
+		Remaining:  int(math.Floor(tokens)),
+		ResetAfter: seconds((float64(limit.Requests) - tokens) / rate(limit)),
+	}
+	if !allowed {
+		r.RetryAfter = seconds((1 - tokens) / rate(limit))
+	}
+	return r
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(math.Ceil(s * float64(time.Second)))
+}
@@ -0,0 +1,86 @@
+package ratelimit_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/ratelimit"
+)
+
+func testLimiter(t *testing.T, limiter ratelimit.Limiter) {
+	ctx := context.Background()
+	limit := ratelimit.Limit{Requests: 2, Period: 200 * time.Millisecond}
+
+	for remaining := 1; remaining >= 0; remaining-- {
+		r, err := limiter.Allow(ctx, "ip:192.0.2.1", limit)
+		require.NoError(t, err)
+		assert.True(t, r.Allowed)
+		assert.Equal(t, 2, r.Limit)
+		assert.Equal(t, remaining, r.Remaining)
+		assert.Zero(t, r.RetryAfter)
+	}
+
+	r, err := limiter.Allow(ctx, "ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	assert.False(t, r.Allowed)
+	assert.Equal(t, 0, r.Remaining)
+	assert.InDelta(t, 100*time.Millisecond, r.RetryAfter, float64(10*time.Millisecond))
+	assert.InDelta(t, 200*time.Millisecond, r.ResetAfter, float64(10*time.Millisecond))
+
+	r, err = limiter.Allow(ctx, "ip:192.0.2.2", limit)
+	require.NoError(t, err)
+	assert.True(t, r.Allowed, "buckets are per key")
+
+	time.Sleep(110 * time.Millisecond)
+	r, err = limiter.Allow(ctx, "ip:192.0.2.1", limit)
+	require.NoError(t, err)
+	assert.True(t, r.Allowed, "a token was added")
+}
+
+func testConcurrent(t *testing.T, limiter ratelimit.Limiter) {
+	limit := ratelimit.Limit{Requests: 10, Period: time.Hour}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for range 30 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := limiter.Allow(context.Background(), "user:7", limit)
+			assert.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			if r.Allowed {
+				allowed++
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 10, allowed)
+}
+
+func TestMemory(t *testing.T) {
+	testLimiter(t, ratelimit.NewMemory())
+	testConcurrent(t, ratelimit.NewMemory())
+}
+
+func TestRedis(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+
+	testLimiter(t, ratelimit.NewRedis(client, "bb:ratelimit:"))
+	testConcurrent(t, ratelimit.NewRedis(client, "bb:ratelimit:"))
+	assert.True(t, server.Exists("bb:ratelimit:user:7"))
+}
+
+func TestLimit_IsZero(t *testing.T) {
+	assert.True(t, ratelimit.Limit{}.IsZero())
+	assert.True(t, ratelimit.Limit{Requests: 10}.IsZero())
+	assert.False(t, ratelimit.Limit{Requests: 10, Period: time.Minute}.IsZero())
+}
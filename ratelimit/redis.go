@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis keeps buckets in Redis, so all instances of the service share one
+// limit.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+// NewRedis keeps buckets in client under keys starting with prefix.
+func NewRedis(client redis.UniversalClient, prefix string) *Redis {
+	return &Redis{client: client, prefix: prefix}
+}
+
+// takeToken refills and takes from a bucket in one step, so concurrent
+// requests can not take the same token. Times are in milliseconds; the
+// tokens left are returned as a string as Redis truncates Lua numbers.
+var takeToken = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or capacity
+local updated = tonumber(bucket[2]) or now
+if now > updated then
+	tokens = math.min(capacity, tokens + (now - updated) * capacity / period)
+	updated = now
+end
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(updated))
+redis.call('PEXPIRE', KEYS[1], period)
+return {allowed, tostring(tokens)}
+`)
+
+func (r *Redis) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	reply, err := takeToken.Run(ctx, r.client, []string{r.prefix + key},
+		limit.Requests, limit.Period.Milliseconds(), time.Now().UnixMilli()).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	allowed, _ := reply[0].(int64)
+	left, _ := reply[1].(string)
+	tokens, err := strconv.ParseFloat(left, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(allowed == 1, tokens, limit), nil
+}
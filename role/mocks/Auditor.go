@@ -0,0 +1,85 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	audit "github.com/tat-101/bb-assignment-back/audit"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// Auditor is an autogenerated mock type for the Auditor type
+type Auditor struct {
+	mock.Mock
+}
+
+type Auditor_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Auditor) EXPECT() *Auditor_Expecter {
+	return &Auditor_Expecter{mock: &_m.Mock}
+}
+
+// Record provides a mock function with given fields: ctx, entry
+func (_m *Auditor) Record(ctx context.Context, entry audit.Entry) error {
+	ret := _m.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, audit.Entry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Auditor_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type Auditor_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry audit.Entry
+func (_e *Auditor_Expecter) Record(ctx interface{}, entry interface{}) *Auditor_Record_Call {
+	return &Auditor_Record_Call{Call: _e.mock.On("Record", ctx, entry)}
+}
+
+func (_c *Auditor_Record_Call) Run(run func(ctx context.Context, entry audit.Entry)) *Auditor_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(audit.Entry))
+	})
+	return _c
+}
+
+func (_c *Auditor_Record_Call) Return(_a0 error) *Auditor_Record_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *Auditor_Record_Call) RunAndReturn(run func(context.Context, audit.Entry) error) *Auditor_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewAuditor creates a new instance of Auditor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditor(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Auditor {
+	mock := &Auditor{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,425 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// RoleRepository is an autogenerated mock type for the RoleRepository type
+type RoleRepository struct {
+	mock.Mock
+}
+
+type RoleRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RoleRepository) EXPECT() *RoleRepository_Expecter {
+	return &RoleRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateRole provides a mock function with given fields: ctx, _a1
+func (_m *RoleRepository) CreateRole(ctx context.Context, _a1 *domain.Role) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.Role) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoleRepository_CreateRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRole'
+type RoleRepository_CreateRole_Call struct {
+	*mock.Call
+}
+
+// CreateRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 *domain.Role
+func (_e *RoleRepository_Expecter) CreateRole(ctx interface{}, _a1 interface{}) *RoleRepository_CreateRole_Call {
+	return &RoleRepository_CreateRole_Call{Call: _e.mock.On("CreateRole", ctx, _a1)}
+}
+
+func (_c *RoleRepository_CreateRole_Call) Run(run func(ctx context.Context, _a1 *domain.Role)) *RoleRepository_CreateRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.Role))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_CreateRole_Call) Return(_a0 error) *RoleRepository_CreateRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoleRepository_CreateRole_Call) RunAndReturn(run func(context.Context, *domain.Role) error) *RoleRepository_CreateRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllPermissions provides a mock function with given fields: ctx
+func (_m *RoleRepository) GetAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllPermissions")
+	}
+
+	var r0 []domain.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Permission, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Permission); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleRepository_GetAllPermissions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllPermissions'
+type RoleRepository_GetAllPermissions_Call struct {
+	*mock.Call
+}
+
+// GetAllPermissions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RoleRepository_Expecter) GetAllPermissions(ctx interface{}) *RoleRepository_GetAllPermissions_Call {
+	return &RoleRepository_GetAllPermissions_Call{Call: _e.mock.On("GetAllPermissions", ctx)}
+}
+
+func (_c *RoleRepository_GetAllPermissions_Call) Run(run func(ctx context.Context)) *RoleRepository_GetAllPermissions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_GetAllPermissions_Call) Return(_a0 []domain.Permission, _a1 error) *RoleRepository_GetAllPermissions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleRepository_GetAllPermissions_Call) RunAndReturn(run func(context.Context) ([]domain.Permission, error)) *RoleRepository_GetAllPermissions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetAllRoles provides a mock function with given fields: ctx
+func (_m *RoleRepository) GetAllRoles(ctx context.Context) ([]domain.Role, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllRoles")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]domain.Role, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []domain.Role); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleRepository_GetAllRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetAllRoles'
+type RoleRepository_GetAllRoles_Call struct {
+	*mock.Call
+}
+
+// GetAllRoles is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *RoleRepository_Expecter) GetAllRoles(ctx interface{}) *RoleRepository_GetAllRoles_Call {
+	return &RoleRepository_GetAllRoles_Call{Call: _e.mock.On("GetAllRoles", ctx)}
+}
+
+func (_c *RoleRepository_GetAllRoles_Call) Run(run func(ctx context.Context)) *RoleRepository_GetAllRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_GetAllRoles_Call) Return(_a0 []domain.Role, _a1 error) *RoleRepository_GetAllRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleRepository_GetAllRoles_Call) RunAndReturn(run func(context.Context) ([]domain.Role, error)) *RoleRepository_GetAllRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPermissionsByNames provides a mock function with given fields: ctx, names
+func (_m *RoleRepository) GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPermissionsByNames")
+	}
+
+	var r0 []domain.Permission
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Permission, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Permission); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Permission)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleRepository_GetPermissionsByNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPermissionsByNames'
+type RoleRepository_GetPermissionsByNames_Call struct {
+	*mock.Call
+}
+
+// GetPermissionsByNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+func (_e *RoleRepository_Expecter) GetPermissionsByNames(ctx interface{}, names interface{}) *RoleRepository_GetPermissionsByNames_Call {
+	return &RoleRepository_GetPermissionsByNames_Call{Call: _e.mock.On("GetPermissionsByNames", ctx, names)}
+}
+
+func (_c *RoleRepository_GetPermissionsByNames_Call) Run(run func(ctx context.Context, names []string)) *RoleRepository_GetPermissionsByNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_GetPermissionsByNames_Call) Return(_a0 []domain.Permission, _a1 error) *RoleRepository_GetPermissionsByNames_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleRepository_GetPermissionsByNames_Call) RunAndReturn(run func(context.Context, []string) ([]domain.Permission, error)) *RoleRepository_GetPermissionsByNames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRolesByNames provides a mock function with given fields: ctx, names
+func (_m *RoleRepository) GetRolesByNames(ctx context.Context, names []string) ([]domain.Role, error) {
+	ret := _m.Called(ctx, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRolesByNames")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.Role, error)); ok {
+		return rf(ctx, names)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.Role); ok {
+		r0 = rf(ctx, names)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, names)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleRepository_GetRolesByNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRolesByNames'
+type RoleRepository_GetRolesByNames_Call struct {
+	*mock.Call
+}
+
+// GetRolesByNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - names []string
+func (_e *RoleRepository_Expecter) GetRolesByNames(ctx interface{}, names interface{}) *RoleRepository_GetRolesByNames_Call {
+	return &RoleRepository_GetRolesByNames_Call{Call: _e.mock.On("GetRolesByNames", ctx, names)}
+}
+
+func (_c *RoleRepository_GetRolesByNames_Call) Run(run func(ctx context.Context, names []string)) *RoleRepository_GetRolesByNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_GetRolesByNames_Call) Return(_a0 []domain.Role, _a1 error) *RoleRepository_GetRolesByNames_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleRepository_GetRolesByNames_Call) RunAndReturn(run func(context.Context, []string) ([]domain.Role, error)) *RoleRepository_GetRolesByNames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserRoles provides a mock function with given fields: ctx, userID
+func (_m *RoleRepository) GetUserRoles(ctx context.Context, userID uint) ([]domain.Role, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserRoles")
+	}
+
+	var r0 []domain.Role
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domain.Role, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domain.Role); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Role)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RoleRepository_GetUserRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserRoles'
+type RoleRepository_GetUserRoles_Call struct {
+	*mock.Call
+}
+
+// GetUserRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *RoleRepository_Expecter) GetUserRoles(ctx interface{}, userID interface{}) *RoleRepository_GetUserRoles_Call {
+	return &RoleRepository_GetUserRoles_Call{Call: _e.mock.On("GetUserRoles", ctx, userID)}
+}
+
+func (_c *RoleRepository_GetUserRoles_Call) Run(run func(ctx context.Context, userID uint)) *RoleRepository_GetUserRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_GetUserRoles_Call) Return(_a0 []domain.Role, _a1 error) *RoleRepository_GetUserRoles_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RoleRepository_GetUserRoles_Call) RunAndReturn(run func(context.Context, uint) ([]domain.Role, error)) *RoleRepository_GetUserRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserRoles provides a mock function with given fields: ctx, userID, roles
+func (_m *RoleRepository) SetUserRoles(ctx context.Context, userID uint, roles []domain.Role) error {
+	ret := _m.Called(ctx, userID, roles)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserRoles")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []domain.Role) error); ok {
+		r0 = rf(ctx, userID, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RoleRepository_SetUserRoles_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserRoles'
+type RoleRepository_SetUserRoles_Call struct {
+	*mock.Call
+}
+
+// SetUserRoles is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - roles []domain.Role
+func (_e *RoleRepository_Expecter) SetUserRoles(ctx interface{}, userID interface{}, roles interface{}) *RoleRepository_SetUserRoles_Call {
+	return &RoleRepository_SetUserRoles_Call{Call: _e.mock.On("SetUserRoles", ctx, userID, roles)}
+}
+
+func (_c *RoleRepository_SetUserRoles_Call) Run(run func(ctx context.Context, userID uint, roles []domain.Role)) *RoleRepository_SetUserRoles_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].([]domain.Role))
+	})
+	return _c
+}
+
+func (_c *RoleRepository_SetUserRoles_Call) Return(_a0 error) *RoleRepository_SetUserRoles_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RoleRepository_SetUserRoles_Call) RunAndReturn(run func(context.Context, uint, []domain.Role) error) *RoleRepository_SetUserRoles_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRoleRepository creates a new instance of RoleRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRoleRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RoleRepository {
+	mock := &RoleRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,170 @@
+package role
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+//go:generate mockery --name RoleRepository
+type RoleRepository interface {
+	GetAllRoles(ctx context.Context) ([]domain.Role, error)
+	GetRolesByNames(ctx context.Context, names []string) ([]domain.Role, error)
+	CreateRole(ctx context.Context, role *domain.Role) error
+	GetAllPermissions(ctx context.Context) ([]domain.Permission, error)
+	GetPermissionsByNames(ctx context.Context, names []string) ([]domain.Permission, error)
+	GetUserRoles(ctx context.Context, userID uint) ([]domain.Role, error)
+	SetUserRoles(ctx context.Context, userID uint, roles []domain.Role) error
+}
+
+// Auditor records role assignments in the audit log.
+//
+//go:generate mockery --name Auditor
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// PrincipalInvalidator forgets what it cached about a user whose roles
+// changed.
+type PrincipalInvalidator interface {
+	InvalidatePrincipal(ctx context.Context, userID uint)
+}
+
+type Service struct {
+	roleRepo   RoleRepository
+	principals PrincipalInvalidator
+	auditor    Auditor
+}
+
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithPrincipalInvalidator tells p about every user whose roles change.
+func WithPrincipalInvalidator(p PrincipalInvalidator) Option {
+	return func(s *Service) {
+		s.principals = p
+	}
+}
+
+// WithAuditor records every change of a user's roles with auditor.
+func WithAuditor(auditor Auditor) Option {
+	return func(s *Service) {
+		s.auditor = auditor
+	}
+}
+
+func NewService(r RoleRepository, opts ...Option) *Service {
+	s := &Service{
+		roleRepo: r,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// GetAllRoles returns every role with its permissions
+func (s *Service) GetAllRoles(ctx context.Context) ([]domain.Role, error) {
+	return s.roleRepo.GetAllRoles(ctx)
+}
+
+// GetAllPermissions returns every permission a role can be granted
+func (s *Service) GetAllPermissions(ctx context.Context) ([]domain.Permission, error) {
+	return s.roleRepo.GetAllPermissions(ctx)
+}
+
+// CreateRole creates a role granting the named permissions, which must exist
+func (s *Service) CreateRole(ctx context.Context, role *domain.Role, permissions []string) error {
+	role.Name = strings.TrimSpace(role.Name)
+	if role.Name == "" {
+		return fmt.Errorf("%w: role name is required", domain.ErrBadParamInput)
+	}
+
+	found, err := s.roleRepo.GetPermissionsByNames(ctx, permissions)
+	if err != nil {
+		return err
+	}
+	if missing := missingNames(permissions, found, func(p domain.Permission) string { return p.Name }); len(missing) > 0 {
+		return fmt.Errorf("%w: unknown permissions %v", domain.ErrBadParamInput, missing)
+	}
+
+	role.Permissions = found
+	return s.roleRepo.CreateRole(ctx, role)
+}
+
+// AssignRoles replaces the roles of a user with the named roles
+func (s *Service) AssignRoles(ctx context.Context, userID uint, roleNames []string) error {
+	found, err := s.roleRepo.GetRolesByNames(ctx, roleNames)
+	if err != nil {
+		return err
+	}
+	if missing := missingNames(roleNames, found, func(r domain.Role) string { return r.Name }); len(missing) > 0 {
+		return fmt.Errorf("%w: unknown roles %v", domain.ErrBadParamInput, missing)
+	}
+
+	var before []domain.Role
+	if s.auditor != nil {
+		if before, err = s.roleRepo.GetUserRoles(ctx, userID); err != nil {
+			return err
+		}
+	}
+	if err := s.roleRepo.SetUserRoles(ctx, userID, found); err != nil {
+		return err
+	}
+	if s.principals != nil {
+		s.principals.InvalidatePrincipal(ctx, userID)
+	}
+	s.record(ctx, userID, before, found)
+	return nil
+}
+
+// userRoles is what the audit log records of a role assignment.
+type userRoles struct {
+	Roles []string
+}
+
+// record adds the change of a user's roles to the audit log. A failure is
+// logged rather than returned, as the roles have already changed.
+func (s *Service) record(ctx context.Context, userID uint, before, after []domain.Role) {
+	if s.auditor == nil {
+		return
+	}
+	names := func(roles []domain.Role) userRoles {
+		list := make([]string, len(roles))
+		for i, role := range roles {
+			list[i] = role.Name
+		}
+		sort.Strings(list)
+		return userRoles{Roles: list}
+	}
+	err := s.auditor.Record(ctx, audit.Entry{
+		Action:     domain.AuditUserRoles,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(userID), 10),
+		Before:     names(before),
+		After:      names(after),
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to record audit event", "action", domain.AuditUserRoles, "error", err)
+	}
+}
+
+func missingNames[T any](names []string, found []T, name func(T) string) []string {
+	known := make(map[string]bool, len(found))
+	for _, item := range found {
+		known[name(item)] = true
+	}
+	var missing []string
+	for _, n := range names {
+		if !known[n] {
+			missing = append(missing, n)
+		}
+	}
+	return missing
+}
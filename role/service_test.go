@@ -0,0 +1,117 @@
+package role_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/role"
+	"github.com/tat-101/bb-assignment-back/role/mocks"
+)
+
+func TestService_GetAllRoles(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	service := role.NewService(mockRoleRepo)
+
+	expectedRoles := []domain.Role{{ID: 1, Name: "admin"}, {ID: 2, Name: "user"}}
+
+	mockRoleRepo.On("GetAllRoles", mock.Anything, mock.Anything).Return(expectedRoles, nil)
+
+	roles, err := service.GetAllRoles(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expectedRoles, roles)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestService_CreateRole(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	service := role.NewService(mockRoleRepo)
+
+	permissions := []domain.Permission{{ID: 1, Name: domain.PermissionUsersRead}}
+	mockRoleRepo.On("GetPermissionsByNames", mock.Anything, []string{domain.PermissionUsersRead}).Return(permissions, nil)
+	mockRoleRepo.On("CreateRole", mock.Anything, mock.MatchedBy(func(r *domain.Role) bool {
+		return r.Name == "auditor" && len(r.Permissions) == 1
+	})).Return(nil)
+
+	newRole := &domain.Role{Name: " auditor "}
+	err := service.CreateRole(context.Background(), newRole, []string{domain.PermissionUsersRead})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "auditor", newRole.Name)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestService_CreateRole_UnknownPermission(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	service := role.NewService(mockRoleRepo)
+
+	mockRoleRepo.On("GetPermissionsByNames", mock.Anything, []string{"users:fly"}).Return([]domain.Permission{}, nil)
+
+	err := service.CreateRole(context.Background(), &domain.Role{Name: "pilot"}, []string{"users:fly"})
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	mockRoleRepo.AssertNotCalled(t, "CreateRole", mock.Anything)
+}
+
+// invalidations records the users InvalidatePrincipal is called for.
+type invalidations []uint
+
+func (i *invalidations) InvalidatePrincipal(ctx context.Context, userID uint) {
+	*i = append(*i, userID)
+}
+
+func TestService_AssignRoles(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	var invalidated invalidations
+	service := role.NewService(mockRoleRepo, role.WithPrincipalInvalidator(&invalidated))
+
+	roles := []domain.Role{{ID: 1, Name: "admin"}}
+	mockRoleRepo.On("GetRolesByNames", mock.Anything, []string{"admin"}).Return(roles, nil)
+	mockRoleRepo.On("SetUserRoles", mock.Anything, uint(10), roles).Return(nil)
+
+	err := service.AssignRoles(context.Background(), 10, []string{"admin"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, invalidations{10}, invalidated)
+	mockRoleRepo.AssertExpectations(t)
+}
+
+func TestService_AssignRoles_Audit(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	mockAuditor := new(mocks.Auditor)
+	service := role.NewService(mockRoleRepo, role.WithAuditor(mockAuditor))
+
+	roles := []domain.Role{{ID: 2, Name: "user"}, {ID: 1, Name: "admin"}}
+	mockRoleRepo.On("GetRolesByNames", mock.Anything, []string{"user", "admin"}).Return(roles, nil)
+	mockRoleRepo.On("GetUserRoles", mock.Anything, uint(10)).Return([]domain.Role{{ID: 2, Name: "user"}}, nil)
+	mockRoleRepo.On("SetUserRoles", mock.Anything, uint(10), roles).Return(nil)
+	var entry audit.Entry
+	mockAuditor.On("Record", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		entry = args.Get(1).(audit.Entry)
+	}).Return(nil)
+
+	err := service.AssignRoles(context.Background(), 10, []string{"user", "admin"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AuditUserRoles, entry.Action)
+	assert.Equal(t, "10", entry.TargetID)
+	diff, err := audit.Diff(entry.Before, entry.After)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Roles":{"from":["user"],"to":["admin","user"]}}`, diff)
+}
+
+func TestService_AssignRoles_UnknownRole(t *testing.T) {
+	mockRoleRepo := new(mocks.RoleRepository)
+	service := role.NewService(mockRoleRepo)
+
+	mockRoleRepo.On("GetRolesByNames", mock.Anything, []string{"admin", "ghost"}).Return([]domain.Role{{ID: 1, Name: "admin"}}, nil)
+
+	err := service.AssignRoles(context.Background(), 10, []string{"admin", "ghost"})
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	mockRoleRepo.AssertNotCalled(t, "SetUserRoles", mock.Anything, mock.Anything)
+}
@@ -1,46 +1,217 @@
 package tools
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"errors"
-	"os"
+	"fmt"
+	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Claims are the JWT claims issued by this service. RegisteredClaims.Subject
+// is the user ID and RegisteredClaims.ID is the "jti" claim, a unique token
+// ID used for revocation.
 type Claims struct {
-	Email string `json:"email"`
+	Email       string   `json:"email,omitempty"`
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	SessionID   string   `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateJWT generates a JWT token for a given email.
-func GenerateJWT(email string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
-	claims := &Claims{
-		Email: email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// UserID parses the subject as a user ID.
+func (c *Claims) UserID() (uint, error) {
+	id, err := strconv.ParseUint(c.Subject, 10, 32)
+	if err != nil || id == 0 {
+		return 0, errors.New("token subject is not a user ID")
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(os.Getenv("JWT_SECRET")))
+	return uint(id), nil
+}
+
+// MinRSAKeyBits is the smallest RSA key accepted for signing or verifying.
+const MinRSAKeyBits = 2048
+
+// KeyRingConfig lists the keys of a KeyRing.
+type KeyRingConfig struct {
+	// SigningKey signs new tokens. It is an RSA (RS256) or Ed25519 (EdDSA)
+	// private key.
+	SigningKey crypto.Signer
+	// VerificationKeys are public keys that are still accepted but no
+	// longer sign, such as the previous signing key during a rotation.
+	VerificationKeys []crypto.PublicKey
+	// Issuer and Audience, when set, are put in every token and required
+	// in every token validated.
+	Issuer   string
+	Audience string
+}
+
+// KeyRing signs access tokens with one key and verifies them with any of its
+// keys, picked by the "kid" header. A key's ID is its RFC 7638 thumbprint, so
+// it is the same wherever the key is loaded.
+type KeyRing struct {
+	signing  crypto.Signer
+	signKey  jwk
+	keys     map[string]jwk
+	order    []string
+	issuer   string
+	audience string
+}
+
+type jwk struct {
+	id     string
+	method jwt.SigningMethod
+	public crypto.PublicKey
+}
+
+func NewKeyRing(cfg KeyRingConfig) (*KeyRing, error) {
+	if cfg.SigningKey == nil {
+		return nil, errors.New("a signing key is required")
+	}
+	k := &KeyRing{
+		signing:  cfg.SigningKey,
+		keys:     map[string]jwk{},
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+	for _, public := range append([]crypto.PublicKey{cfg.SigningKey.Public()}, cfg.VerificationKeys...) {
+		key, err := newJWK(public)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := k.keys[key.id]; ok {
+			continue
+		}
+		k.keys[key.id] = key
+		k.order = append(k.order, key.id)
+	}
+	k.signKey = k.keys[k.order[0]]
+	return k, nil
+}
+
+// GenerateKeyRing returns a key ring with a new Ed25519 signing key. Tokens it
+// signs can not be verified once the process exits.
+func GenerateKeyRing(issuer, audience string) (*KeyRing, error) {
+	_, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyRing(KeyRingConfig{SigningKey: key, Issuer: issuer, Audience: audience})
+}
+
+func newJWK(public crypto.PublicKey) (jwk, error) {
+	var key jwk
+	switch public := public.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < MinRSAKeyBits {
+			return key, fmt.Errorf("RSA keys must have at least %d bits, got %d", MinRSAKeyBits, public.N.BitLen())
+		}
+		key.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.method = jwt.SigningMethodEdDSA
+	default:
+		return key, fmt.Errorf("unsupported key type %T, only RSA and Ed25519 keys are supported", public)
+	}
+	key.public = public
+
+	// The thumbprint is the hash of the required members in lexical order.
+	thumbprint, err := json.Marshal(key.members())
+	if err != nil {
+		return key, err
+	}
+	sum := sha256.Sum256(thumbprint)
+	key.id = base64.RawURLEncoding.EncodeToString(sum[:])
+	return key, nil
+}
+
+func (k jwk) members() map[string]string {
+	switch public := k.public.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}
+	default:
+		return map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   base64.RawURLEncoding.EncodeToString(public.(ed25519.PublicKey)),
+		}
+	}
+}
+
+// KeyID returns the ID of the signing key.
+func (k *KeyRing) KeyID() string {
+	return k.signKey.id
+}
+
+// GenerateJWT signs claims into a token that expires after ttl. It sets the
+// token ID, issuer, audience and times.
+func (k *KeyRing) GenerateJWT(claims Claims, ttl time.Duration) (string, error) {
+	now := time.Now()
+	jti, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	claims.ID = jti
+	claims.Issuer = k.issuer
+	claims.IssuedAt = jwt.NewNumericDate(now)
+	claims.ExpiresAt = jwt.NewNumericDate(now.Add(ttl))
+	claims.Audience = nil
+	if k.audience != "" {
+		claims.Audience = jwt.ClaimStrings{k.audience}
+	}
+	token := jwt.NewWithClaims(k.signKey.method, &claims)
+	token.Header["kid"] = k.signKey.id
+	return token.SignedString(k.signing)
 }
 
 // ValidateJWT validates a given JWT token and returns the claims if valid.
-func ValidateJWT(tokenString string) (*Claims, error) {
+func (k *KeyRing) ValidateJWT(tokenString string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuedAt(),
+		jwt.WithExpirationRequired(),
+	}
+	if k.issuer != "" {
+		options = append(options, jwt.WithIssuer(k.issuer))
+	}
+	if k.audience != "" {
+		options = append(options, jwt.WithAudience(k.audience))
+	}
+
 	claims := &Claims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("JWT_SECRET")), nil
-	})
+		kid, _ := token.Header["kid"].(string)
+		key, ok := k.keys[kid]
+		// A key is only good for its own algorithm.
+		if !ok || token.Method != key.method {
+			return nil, errUnknownKey
+		}
+		return key.public, nil
+	}, options...)
 
 	if err != nil {
 		if errors.Is(err, jwt.ErrTokenMalformed) {
 			return nil, errors.New("token is malformed")
+		} else if errors.Is(err, errUnknownKey) {
+			return nil, errUnknownKey
 		} else if errors.Is(err, jwt.ErrTokenSignatureInvalid) {
 			return nil, errors.New("invalid token signature")
 		} else if errors.Is(err, jwt.ErrTokenExpired) {
 			return nil, errors.New("token is expired")
+		} else if errors.Is(err, jwt.ErrTokenInvalidIssuer) || errors.Is(err, jwt.ErrTokenInvalidAudience) {
+			return nil, errors.New("token was issued for someone else")
 		} else {
 			return nil, errors.New("token is invalid")
 		}
@@ -52,3 +223,71 @@ func ValidateJWT(tokenString string) (*Claims, error) {
 
 	return claims, nil
 }
+
+var errUnknownKey = errors.New("token is signed by an unknown key")
+
+// JWKS is a JSON Web Key Set.
+type JWKS struct {
+	Keys []map[string]string `json:"keys"`
+}
+
+// JWKS returns the public keys of the ring, signing key first, for other
+// services to verify tokens with.
+func (k *KeyRing) JWKS() JWKS {
+	set := JWKS{Keys: make([]map[string]string, 0, len(k.order))}
+	for _, id := range k.order {
+		key := k.keys[id]
+		members := key.members()
+		members["kid"] = key.id
+		members["alg"] = key.method.Alg()
+		members["use"] = "sig"
+		set.Keys = append(set.Keys, members)
+	}
+	return set
+}
+
+// ParsePrivateKeyPEM parses a PEM encoded PKCS #8 or PKCS #1 private key.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if key, rsaErr := x509.ParsePKCS1PrivateKey(block.Bytes); rsaErr == nil {
+			return key, nil
+		}
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+	return signer, nil
+}
+
+// ParsePublicKeysPEM parses every PEM block in data, public or private keys,
+// and returns their public keys.
+func ParsePublicKeysPEM(data []byte) ([]crypto.PublicKey, error) {
+	var keys []crypto.PublicKey
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			return keys, nil
+		}
+		if block.Type != "PUBLIC KEY" {
+			signer, err := ParsePrivateKeyPEM(pem.EncodeToMemory(block))
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, signer.Public())
+			continue
+		}
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+}
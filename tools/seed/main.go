@@ -1,10 +1,19 @@
 package main
 
 import (
+	"log"
+	"os"
+
+	"github.com/tat-101/bb-assignment-back/config"
 	"github.com/tat-101/bb-assignment-back/tools/seed/seed"
 )
 
 func main() {
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Seed the database with an admin user
-	seed.SeedAdminUser()
+	seed.SeedAdminUser(cfg)
 }
@@ -2,45 +2,107 @@ package seed
 
 import (
 	"log"
+	"time"
 
 	"github.com/tat-101/bb-assignment-back/config"
 	"github.com/tat-101/bb-assignment-back/database"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
-// SeedAdminUser seeds the database with an admin user if it doesn't exist.
-func SeedAdminUser() {
-	cfg := config.LoadConfig()
+var defaultPermissions = []domain.Permission{
+	{Name: domain.PermissionUsersRead, Description: "List and view users"},
+	{Name: domain.PermissionUsersWrite, Description: "Create users and edit any user"},
+	{Name: domain.PermissionUsersDelete, Description: "Delete users"},
+	{Name: domain.PermissionRolesManage, Description: "Create roles and assign them to users"},
+	{Name: domain.PermissionAuditRead, Description: "Read the audit log"},
+}
+
+// defaultRoles maps the default role names to the permissions they grant.
+var defaultRoles = map[string][]string{
+	"admin": {
+		domain.PermissionUsersRead,
+		domain.PermissionUsersWrite,
+		domain.PermissionUsersDelete,
+		domain.PermissionRolesManage,
+		domain.PermissionAuditRead,
+	},
+	// Every new account gets "user", including self-registered ones, so it
+	// grants nothing beyond the account's own data under /me.
+	"user": {},
+}
 
+// SeedAdminUser seeds the database with an admin user if it doesn't exist.
+func SeedAdminUser(cfg config.Config) {
 	db := database.Initialize(cfg)
 
-	err := db.AutoMigrate(&domain.User{})
+	err := database.Migrate(db)
 	if err != nil {
 		panic("Failed to migrate database: " + err.Error())
 	}
 
+	if err := SeedRoles(db); err != nil {
+		log.Fatalf("Failed to seed roles: %v", err)
+	}
+
 	var user domain.User
 	email := "admin@bb.com"
 
 	// Check if the admin user already exists
 	if err := db.Where("email = ?", email).First(&user).Error; err == nil {
 		log.Println("Admin user already exists, skipping seeding.")
-		return
-	}
+	} else {
+		// Create a new admin user
+		hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
+		var adminRole domain.Role
+		if err := db.Where("name = ?", "admin").First(&adminRole).Error; err != nil {
+			log.Fatalf("Failed to find the admin role: %v", err)
+		}
+		now := time.Now()
+		admin := domain.User{
+			Name:            "Admin",
+			Email:           email,
+			Password:        string(hashedPassword),
+			Roles:           []domain.Role{adminRole},
+			EmailVerifiedAt: &now,
+		}
 
-	// Create a new admin user
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("123456"), bcrypt.DefaultCost)
-	admin := domain.User{
-		Name:     "Admin",
-		Email:    email,
-		Password: string(hashedPassword),
-		Role:     "admin",
-	}
+		if err := db.Create(&admin).Error; err != nil {
+			log.Fatalf("Failed to seed admin user: %v", err)
+		}
 
-	if err := db.Create(&admin).Error; err != nil {
-		log.Fatalf("Failed to seed admin user: %v", err)
+		log.Println("Admin user created successfully.")
 	}
+}
+
+// SeedRoles creates the default permissions and roles.
+func SeedRoles(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		permissions := make(map[string]domain.Permission, len(defaultPermissions))
+		for _, permission := range defaultPermissions {
+			if err := tx.Where(domain.Permission{Name: permission.Name}).FirstOrCreate(&permission).Error; err != nil {
+				return err
+			}
+			permissions[permission.Name] = permission
+		}
 
-	log.Println("Admin user created successfully.")
+		for name, granted := range defaultRoles {
+			role := domain.Role{Name: name}
+			if err := tx.Where(domain.Role{Name: name}).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+			if len(granted) == 0 {
+				continue
+			}
+			rolePermissions := make([]domain.Permission, len(granted))
+			for i, permission := range granted {
+				rolePermissions[i] = permissions[permission]
+			}
+			if err := tx.Model(&role).Association("Permissions").Append(rolePermissions); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrInvalidSignedToken is returned for signed tokens that are malformed,
+// expired, tampered with or issued for another purpose.
+var ErrInvalidSignedToken = errors.New("invalid or expired token")
+
+// SignToken returns a token that proves subject was vouched for by this
+// service for purpose, such as "verify-email", until ttl has passed. The key
+// is derived from secret and purpose, so a token for one purpose is never
+// accepted for another, nor as an access token. Every token is unique, so
+// one can be spent without spending others for the same subject.
+func SignToken(secret []byte, purpose, subject string, ttl time.Duration) (string, error) {
+	id, err := GenerateRandomToken(16)
+	if err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		ID:        id,
+		Subject:   subject,
+		Audience:  jwt.ClaimStrings{purpose},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(purposeKey(secret, purpose))
+}
+
+// VerifySignedToken checks a token made by SignToken for purpose and returns
+// its subject.
+func VerifySignedToken(secret []byte, purpose, token string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return purposeKey(secret, purpose), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithAudience(purpose),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil || claims.Subject == "" {
+		return "", ErrInvalidSignedToken
+	}
+	return claims.Subject, nil
+}
+
+func purposeKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const spanKey = "tracing:span"
+
+var tracer = otel.Tracer("github.com/tat-101/bb-assignment-back/tracing")
+
+// InstrumentDB records a client span for every gorm operation on db. The
+// span is a child of the span in the statement's context, so queries must be
+// run with db.WithContext to be attached to the request. The SQL is
+// recorded without its parameters.
+func InstrumentDB(db *gorm.DB) error {
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("*").Register("tracing:before_create", startSpan("create")),
+		cb.Create().After("*").Register("tracing:after_create", endSpan),
+		cb.Query().Before("*").Register("tracing:before_query", startSpan("query")),
+		cb.Query().After("*").Register("tracing:after_query", endSpan),
+		cb.Update().Before("*").Register("tracing:before_update", startSpan("update")),
+		cb.Update().After("*").Register("tracing:after_update", endSpan),
+		cb.Delete().Before("*").Register("tracing:before_delete", startSpan("delete")),
+		cb.Delete().After("*").Register("tracing:after_delete", endSpan),
+		cb.Row().Before("*").Register("tracing:before_row", startSpan("row")),
+		cb.Row().After("*").Register("tracing:after_row", endSpan),
+		cb.Raw().Before("*").Register("tracing:before_raw", startSpan("raw")),
+		cb.Raw().After("*").Register("tracing:after_raw", endSpan),
+	)
+}
+
+func startSpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		_, span := tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemPostgreSQL,
+				semconv.DBOperationName(operation),
+			),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBCollectionName(db.Statement.Table),
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,82 @@
+package tracing_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tracing"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var recorder = tracetest.NewSpanRecorder()
+
+func TestMain(m *testing.M) {
+	// Package level tracers bind to the first provider installed globally.
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	os.Exit(m.Run())
+}
+
+func TestInstrumentDB(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+	})
+	require.NoError(t, err)
+	require.NoError(t, tracing.InstrumentDB(db))
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "request")
+	var users []domain.User
+	db.WithContext(ctx).Where("email = ?", "secret@bb.com").Find(&users)
+	parent.End()
+
+	var query sdktrace.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		if span.Name() == "gorm.query" {
+			query = span
+		}
+	}
+	require.NotNil(t, query)
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent().SpanID())
+
+	attrs := map[string]string{}
+	for _, kv := range query.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "users", attrs["db.collection.name"])
+	assert.Contains(t, attrs["db.query.text"], "email = $1")
+	assert.NotContains(t, attrs["db.query.text"], "secret@bb.com")
+}
+
+func TestSetup_File(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "traces.json")
+	shutdown, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:    tracing.ExporterFile,
+		File:        file,
+		ServiceName: "test",
+	})
+	require.NoError(t, err)
+
+	_, span := otel.Tracer("test").Start(context.Background(), "hello")
+	span.End()
+	require.NoError(t, shutdown(context.Background()))
+
+	out, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"Name":"hello"`)
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(context.Background(), tracing.Config{Exporter: "jaeger"})
+
+	assert.ErrorContains(t, err, "unknown trace exporter")
+}
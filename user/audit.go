@@ -0,0 +1,78 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// Auditor records what happens to users in the audit log.
+//
+//go:generate mockery --name Auditor
+type Auditor interface {
+	Record(ctx context.Context, entry audit.Entry) error
+}
+
+// WithAuditor records user changes and logins with auditor.
+func WithAuditor(auditor Auditor) Option {
+	return func(s *Service) {
+		s.auditor = auditor
+	}
+}
+
+// record adds entry to the audit log. A failure is logged rather than
+// returned, as what is recorded has already happened.
+func (s *Service) record(ctx context.Context, entry audit.Entry) {
+	if s.auditor == nil {
+		return
+	}
+	if err := s.auditor.Record(ctx, entry); err != nil {
+		slog.ErrorContext(ctx, "Failed to record audit event", "action", entry.Action, "error", err)
+	}
+}
+
+// recordUser records action on the user with the given ID.
+func (s *Service) recordUser(ctx context.Context, action string, id uint, before, after *domain.User) {
+	s.record(ctx, audit.Entry{
+		Action:     action,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(id), 10),
+		Before:     before,
+		After:      after,
+	})
+}
+
+// auditedUser loads the user an audited change is about to modify, or
+// returns nil when there is no auditor or the user can not be loaded.
+func (s *Service) auditedUser(ctx context.Context, id string) *domain.User {
+	if s.auditor == nil {
+		return nil
+	}
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return nil
+	}
+	user, err := s.userRepo.GetUserByID(ctx, uint(userID))
+	if err != nil {
+		return nil
+	}
+	return user
+}
+
+// recordLogin records a login attempt for email. user is the account it was
+// for, nil when there is none.
+func (s *Service) recordLogin(ctx context.Context, action, email string, user *domain.User) {
+	entry := audit.Entry{Action: action, ActorEmail: email}
+	if user != nil {
+		entry.TargetType = domain.AuditTargetUser
+		entry.TargetID = strconv.FormatUint(uint64(user.ID), 10)
+		if action == domain.AuditLogin {
+			entry.ActorID = &user.ID
+			entry.ActorEmail = user.Email
+		}
+	}
+	s.record(ctx, entry)
+}
@@ -0,0 +1,123 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func action(action string) interface{} {
+	return mock.MatchedBy(func(e audit.Entry) bool { return e.Action == action })
+}
+
+func TestService_UpdateUserByID_Audit(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAuditor := new(mocks.Auditor)
+	service := user.NewService(mockUserRepo, user.WithAuditor(mockAuditor))
+
+	before := &domain.User{ID: 1, Email: "old@example.com", Name: "Old"}
+	after := domain.User{ID: 1, Email: "new@example.com", Name: "Old"}
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(before, nil)
+	mockUserRepo.On("UpdateUserByID", mock.Anything, "1", after).Return(&after, nil)
+	mockAuditor.On("Record", mock.Anything, audit.Entry{
+		Action:     domain.AuditUserUpdate,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   "1",
+		Before:     before,
+		After:      &after,
+	}).Return(nil)
+
+	_, err := service.UpdateUserByID(context.Background(), "1", after)
+
+	require.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockAuditor.AssertExpectations(t)
+}
+
+func TestService_DeleteUserByID_AuditFailure(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAuditor := new(mocks.Auditor)
+	service := user.NewService(mockUserRepo, user.WithAuditor(mockAuditor))
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(&domain.User{ID: 1}, nil)
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(1)).Return(nil)
+	mockAuditor.On("Record", mock.Anything, action(domain.AuditUserDelete)).Return(errors.New("audit log is down"))
+
+	// The user is already gone, so a failure to record it is not returned.
+	err := service.DeleteUserByID(context.Background(), "1")
+
+	assert.NoError(t, err)
+	mockAuditor.AssertExpectations(t)
+}
+
+func TestService_AuthenticateUser_Audit(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAuditor := new(mocks.Auditor)
+	service := user.NewService(mockUserRepo, user.WithAuditor(mockAuditor))
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "nobody@example.com").Return(nil, domain.ErrNotFound)
+	mockAuditor.On("Record", mock.Anything, audit.Entry{
+		Action:     domain.AuditLoginFailed,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   "7",
+		ActorEmail: "user@example.com",
+	}).Return(nil).Once()
+	mockAuditor.On("Record", mock.Anything, audit.Entry{
+		Action:     domain.AuditLoginFailed,
+		ActorEmail: "nobody@example.com",
+	}).Return(nil).Once()
+	mockAuditor.On("Record", mock.Anything, mock.MatchedBy(func(e audit.Entry) bool {
+		return e.Action == domain.AuditLogin && e.ActorID != nil && *e.ActorID == 7 && e.TargetID == "7"
+	})).Return(nil).Once()
+
+	_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	require.Error(t, err)
+	_, err = service.AuthenticateUser(context.Background(), "nobody@example.com", "password123")
+	require.Error(t, err)
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+
+	mockAuditor.AssertExpectations(t)
+}
+
+func TestService_AuthenticateUser_AuditLockout(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAuditor := new(mocks.Auditor)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo,
+		user.WithLoginLockout(attempts, testLockout),
+		user.WithAuditor(mockAuditor))
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, domain.ErrNotFound)
+	mockAuditor.On("Record", mock.Anything, action(domain.AuditLoginFailed)).Return(nil)
+	mockAuditor.On("Record", mock.Anything, action(domain.AuditLoginLocked)).Return(nil)
+	mockAuditor.On("Record", mock.Anything, audit.Entry{
+		Action:     domain.AuditLockout,
+		TargetType: domain.AuditTargetLoginKey,
+		TargetID:   "account:user@example.com",
+	}).Return(nil).Once()
+
+	for range 3 {
+		_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+		require.Error(t, err)
+		require.NoError(t, attempts.LockLogin(context.Background(), "account:user@example.com", time.Now()))
+	}
+	_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	require.Error(t, err)
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	require.ErrorIs(t, err, domain.ErrTooManyRequests)
+
+	mockAuditor.AssertExpectations(t)
+	mockAuditor.AssertNumberOfCalls(t, "Record", 6)
+}
@@ -0,0 +1,48 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// DefaultDeletedUserRetention is how long deleted users can be restored
+// before they are purged.
+const DefaultDeletedUserRetention = 30 * 24 * time.Hour
+
+// WithDeletedUserRetention sets how long deleted users are kept before
+// PurgeDeletedUsers removes them.
+func WithDeletedUserRetention(retention time.Duration) Option {
+	return func(s *Service) {
+		s.deletedRetention = tools.Coalesce(retention, DefaultDeletedUserRetention)
+	}
+}
+
+// RestoreUser brings back a deleted user that has not been purged yet.
+func (s *Service) RestoreUser(ctx context.Context, id uint) (err error) {
+	ctx, span := startSpan(ctx, "RestoreUser", attribute.Int64("user.id", int64(id)))
+	defer func() { endSpan(span, err) }()
+
+	if err := s.userRepo.RestoreUserByID(ctx, id); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditUserRestore, id, nil, nil)
+	return nil
+}
+
+// PurgeDeletedUsers removes users deleted longer than the retention period
+// ago for good. Their email addresses can then be registered again.
+func (s *Service) PurgeDeletedUsers(ctx context.Context) (purged int64, err error) {
+	ctx, span := startSpan(ctx, "PurgeDeletedUsers")
+	defer func() { endSpan(span, err) }()
+
+	purged, err = s.userRepo.PurgeDeletedUsers(ctx, time.Now().Add(-s.deletedRetention))
+	if err != nil {
+		return 0, err
+	}
+	span.SetAttributes(attribute.Int64("users.purged", purged))
+	return purged, nil
+}
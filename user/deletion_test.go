@@ -0,0 +1,79 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func TestService_DeleteUserByID_InvalidID(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	err := service.DeleteUserByID(context.Background(), "abc")
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	mockUserRepo.AssertNotCalled(t, "DeleteUserByID", mock.Anything, mock.Anything)
+}
+
+func TestService_DeleteUserByID_NotFound(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(1)).Return(domain.ErrNotFound)
+
+	assert.ErrorIs(t, service.DeleteUserByID(context.Background(), "1"), domain.ErrNotFound)
+}
+
+func TestService_DeleteUserByID_RevokesSessions(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshRepo := new(mocks.RefreshTokenRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo,
+		user.WithRefreshTokens(mockRefreshRepo, time.Hour),
+		user.WithRevocationStore(mockRevocations))
+
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(7)).Return(nil)
+	mockRevocations.On("RevokeUserTokens", mock.Anything, uint(7), mock.Anything, mock.Anything).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokensByUser", mock.Anything, uint(7)).Return(nil)
+
+	require.NoError(t, service.DeleteUserByID(context.Background(), "7"))
+	mockRevocations.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestService_RestoreUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAuditor := new(mocks.Auditor)
+	service := user.NewService(mockUserRepo, user.WithAuditor(mockAuditor))
+
+	mockUserRepo.On("RestoreUserByID", mock.Anything, uint(1)).Return(nil)
+	mockUserRepo.On("RestoreUserByID", mock.Anything, uint(2)).Return(domain.ErrNotFound)
+	mockAuditor.On("Record", mock.Anything, action(domain.AuditUserRestore)).Return(nil).Once()
+
+	require.NoError(t, service.RestoreUser(context.Background(), 1))
+	assert.ErrorIs(t, service.RestoreUser(context.Background(), 2), domain.ErrNotFound)
+	mockAuditor.AssertExpectations(t)
+}
+
+func TestService_PurgeDeletedUsers(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo, user.WithDeletedUserRetention(7*24*time.Hour))
+
+	mockUserRepo.On("PurgeDeletedUsers", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Since(before) > 7*24*time.Hour-time.Minute && time.Since(before) < 7*24*time.Hour+time.Minute
+	})).Return(int64(3), nil)
+
+	purged, err := service.PurgeDeletedUsers(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+	mockUserRepo.AssertExpectations(t)
+}
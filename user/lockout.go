@@ -0,0 +1,227 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// LoginAttemptStore counts failed logins per key. Keys are "account:<email>",
+// "ip:<client IP>" and "mfa-challenge:<token hash>".
+//
+//go:generate mockery --name LoginAttemptStore
+type LoginAttemptStore interface {
+	GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	// RecordLoginFailure counts a failure at now and returns the failures
+	// counted for key. Failures from before now-window are forgotten.
+	RecordLoginFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	// LockLogin locks key until then, creating it if it has not failed yet.
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginAttempts(ctx context.Context, key string) error
+	// DeleteStaleLoginAttempts deletes keys that have neither failed nor
+	// been locked since before.
+	DeleteStaleLoginAttempts(ctx context.Context, before time.Time) (int64, error)
+}
+
+// LockoutPolicy decides how long logins for a key are refused after it
+// failed. The first FreeAttempts failures cost nothing. Every failure after
+// that refuses logins for BaseDelay, doubled with each further failure up to
+// MaxDelay. MaxFailures failures lock the key for LockoutDuration.
+type LockoutPolicy struct {
+	FreeAttempts    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	MaxFailures     int
+	LockoutDuration time.Duration
+}
+
+var (
+	DefaultAccountLockout = LockoutPolicy{
+		FreeAttempts:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		MaxFailures:     10,
+		LockoutDuration: 15 * time.Minute,
+	}
+	// DefaultClientIPLockout is more lenient, as many users can share an
+	// address.
+	DefaultClientIPLockout = LockoutPolicy{
+		FreeAttempts:    20,
+		BaseDelay:       time.Second,
+		MaxDelay:        time.Minute,
+		MaxFailures:     100,
+		LockoutDuration: 15 * time.Minute,
+	}
+)
+
+const DefaultLoginFailureWindow = time.Hour
+
+// LockoutConfig configures WithLoginLockout.
+type LockoutConfig struct {
+	Account  LockoutPolicy
+	ClientIP LockoutPolicy
+	// Window is how long a failure is remembered.
+	Window time.Duration
+}
+
+// WithLoginLockout throttles and locks out logins for accounts and client
+// IPs that keep failing. Without it failed logins are only counted in
+// metrics.
+func WithLoginLockout(store LoginAttemptStore, cfg LockoutConfig) Option {
+	return func(s *Service) {
+		if cfg.Account == (LockoutPolicy{}) {
+			cfg.Account = DefaultAccountLockout
+		}
+		if cfg.ClientIP == (LockoutPolicy{}) {
+			cfg.ClientIP = DefaultClientIPLockout
+		}
+		if cfg.Window <= 0 {
+			cfg.Window = DefaultLoginFailureWindow
+		}
+		s.loginAttempts = store
+		s.lockout = cfg
+	}
+}
+
+func (p LockoutPolicy) delay(failures int) time.Duration {
+	switch {
+	case failures >= p.MaxFailures:
+		return p.LockoutDuration
+	case failures <= p.FreeAttempts:
+		return 0
+	}
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < failures && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, p.MaxDelay)
+}
+
+type loginKey struct {
+	key    string
+	policy LockoutPolicy
+}
+
+func accountLoginKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// loginKeys returns the keys a login for email from the client in ctx is
+// counted under.
+func (s *Service) loginKeys(ctx context.Context, email string) []loginKey {
+	keys := []loginKey{{key: accountLoginKey(email), policy: s.lockout.Account}}
+	if ip := domain.RequestInfoFrom(ctx).ClientIP; ip != "" {
+		keys = append(keys, loginKey{key: "ip:" + ip, policy: s.lockout.ClientIP})
+	}
+	return keys
+}
+
+// checkLoginLock returns a *domain.TooManyRequestsError when any of keys is
+// locked.
+func (s *Service) checkLoginLock(ctx context.Context, keys []loginKey) error {
+	if s.loginAttempts == nil {
+		return nil
+	}
+	now := time.Now()
+	var retryAfter time.Duration
+	for _, k := range keys {
+		attempts, err := s.loginAttempts.GetLoginAttempts(ctx, k.key)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if attempts.IsLocked(now) {
+			retryAfter = max(retryAfter, attempts.LockedUntil.Sub(now))
+		}
+	}
+	if retryAfter > 0 {
+		return &domain.TooManyRequestsError{
+			Message:    "too many failed logins, try again later",
+			RetryAfter: retryAfter,
+		}
+	}
+	return nil
+}
+
+// recordLoginFailure counts a failed login under keys and locks the keys
+// that failed too often. Errors are logged, as the login fails anyway.
+func (s *Service) recordLoginFailure(ctx context.Context, keys []loginKey) {
+	if s.loginAttempts == nil {
+		return
+	}
+	now := time.Now()
+	for _, k := range keys {
+		failures, err := s.loginAttempts.RecordLoginFailure(ctx, k.key, now, s.lockout.Window)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to record login failure", "error", err)
+			continue
+		}
+		delay := k.policy.delay(failures)
+		if delay == 0 {
+			continue
+		}
+		if err := s.loginAttempts.LockLogin(ctx, k.key, now.Add(delay)); err != nil {
+			slog.ErrorContext(ctx, "Failed to lock login", "error", err)
+			continue
+		}
+		if failures == k.policy.MaxFailures {
+			slog.WarnContext(ctx, "Login locked out after repeated failures",
+				"key", k.key, "failures", failures, "until", now.Add(delay))
+			s.record(ctx, audit.Entry{
+				Action:     domain.AuditLockout,
+				TargetType: domain.AuditTargetLoginKey,
+				TargetID:   k.key,
+			})
+		}
+	}
+}
+
+// resetLoginAttempts forgets the failures of keys after a successful login.
+func (s *Service) resetLoginAttempts(ctx context.Context, keys []loginKey) {
+	if s.loginAttempts == nil {
+		return
+	}
+	for _, k := range keys {
+		if err := s.loginAttempts.ResetLoginAttempts(ctx, k.key); err != nil {
+			slog.ErrorContext(ctx, "Failed to reset login failures", "error", err)
+		}
+	}
+}
+
+// UnlockUser lifts the lockout of a user's account and forgets its failed
+// logins. Locks on client IPs stay in place.
+func (s *Service) UnlockUser(ctx context.Context, id uint) (err error) {
+	ctx, span := startSpan(ctx, "UnlockUser")
+	defer func() { endSpan(span, err) }()
+
+	user, err := s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if s.loginAttempts == nil {
+		return nil
+	}
+	if err := s.loginAttempts.ResetLoginAttempts(ctx, accountLoginKey(user.Email)); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditUserUnlock, id, nil, nil)
+	return nil
+}
+
+// PruneLoginAttempts deletes failed login counts that have expired.
+func (s *Service) PruneLoginAttempts(ctx context.Context) (pruned int64, err error) {
+	ctx, span := startSpan(ctx, "PruneLoginAttempts")
+	defer func() { endSpan(span, err) }()
+
+	if s.loginAttempts == nil {
+		return 0, nil
+	}
+	return s.loginAttempts.DeleteStaleLoginAttempts(ctx, time.Now().Add(-s.lockout.Window))
+}
@@ -0,0 +1,139 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+var testLockout = user.LockoutConfig{
+	Account: user.LockoutPolicy{
+		FreeAttempts:    2,
+		BaseDelay:       time.Hour,
+		MaxDelay:        time.Hour,
+		MaxFailures:     4,
+		LockoutDuration: 2 * time.Hour,
+	},
+	ClientIP: user.LockoutPolicy{
+		FreeAttempts:    2,
+		MaxFailures:     2,
+		LockoutDuration: time.Hour,
+	},
+}
+
+func TestService_AuthenticateUser_LockoutBackoff(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(attempts, testLockout))
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+
+	for range 3 {
+		_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, domain.ErrTooManyRequests)
+	}
+
+	// The third failure is past the free attempts, so even the right
+	// password is refused without being checked.
+	_, err := service.AuthenticateUser(context.Background(), "User@Example.com", "password123")
+	var tooMany *domain.TooManyRequestsError
+	require.True(t, errors.As(err, &tooMany))
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+	assert.InDelta(t, time.Hour, tooMany.RetryAfter, float64(time.Minute))
+	mockUserRepo.AssertNumberOfCalls(t, "GetUserByEmail", 3)
+
+	require.NoError(t, service.UnlockUser(context.Background(), 7))
+
+	tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+}
+
+func TestService_AuthenticateUser_LockoutMaxFailures(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(attempts, testLockout))
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, domain.ErrNotFound)
+
+	for range 3 {
+		_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+		require.Error(t, err)
+		// Skip the backoff to reach the lockout.
+		require.NoError(t, attempts.LockLogin(context.Background(), "account:user@example.com", time.Now()))
+	}
+	_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	require.Error(t, err)
+
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+
+	var tooMany *domain.TooManyRequestsError
+	require.True(t, errors.As(err, &tooMany))
+	assert.Greater(t, tooMany.RetryAfter, time.Hour, "unknown accounts are locked like existing ones")
+}
+
+func TestService_AuthenticateUser_LockoutClientIP(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(attempts, testLockout))
+	ctx := domain.WithRequestInfo(context.Background(), domain.RequestInfo{ClientIP: "192.0.2.1"})
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, domain.ErrNotFound)
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		_, err := service.AuthenticateUser(ctx, email, "wrong")
+		assert.NotErrorIs(t, err, domain.ErrTooManyRequests)
+	}
+
+	_, err := service.AuthenticateUser(ctx, "c@example.com", "wrong")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests, "the address tried too many accounts")
+
+	_, err = service.AuthenticateUser(context.Background(), "c@example.com", "wrong")
+	assert.NotErrorIs(t, err, domain.ErrTooManyRequests, "other clients can still log in")
+}
+
+func TestService_AuthenticateUser_SuccessResetsFailures(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(attempts, testLockout))
+	ctx := domain.WithRequestInfo(context.Background(), domain.RequestInfo{ClientIP: "192.0.2.1"})
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+
+	_, err := service.AuthenticateUser(ctx, "user@example.com", "wrong")
+	require.Error(t, err)
+	_, err = service.AuthenticateUser(ctx, "user@example.com", "password123")
+	require.NoError(t, err)
+
+	for _, key := range []string{"account:user@example.com", "ip:192.0.2.1"} {
+		_, err := attempts.GetLoginAttempts(context.Background(), key)
+		assert.ErrorIs(t, err, domain.ErrNotFound, key)
+	}
+}
+
+func TestService_PruneLoginAttempts(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockAttempts := new(mocks.LoginAttemptStore)
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(mockAttempts, user.LockoutConfig{}))
+
+	mockAttempts.On("DeleteStaleLoginAttempts", mock.Anything, mock.MatchedBy(func(before time.Time) bool {
+		return time.Until(before) < -user.DefaultLoginFailureWindow+time.Minute
+	})).Return(int64(2), nil)
+
+	pruned, err := service.PruneLoginAttempts(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pruned)
+	mockAttempts.AssertExpectations(t)
+}
@@ -0,0 +1,51 @@
+package user
+
+// Outcomes reported to Metrics.
+const (
+	OutcomeSuccess            = "success"
+	OutcomeInvalidCredentials = "invalid_credentials"
+	OutcomeUnverified         = "unverified"
+	OutcomeInactive           = "inactive"
+	OutcomeLocked             = "locked"
+	OutcomeMFARequired        = "mfa_required"
+	OutcomeInvalidMFACode     = "invalid_mfa_code"
+	OutcomeInvalidToken       = "invalid_token"
+	OutcomeUserNotFound       = "user_not_found"
+	OutcomeRevoked            = "revoked"
+	OutcomeError              = "error"
+)
+
+// Metrics records authentication outcomes.
+//
+//go:generate mockery --name Metrics
+type Metrics interface {
+	ObserveLogin(outcome string)
+	ObserveTokenValidation(outcome string)
+	ObservePrincipalCache(result string)
+}
+
+// WithMetrics reports login and token validation outcomes and principal
+// cache lookups to m.
+func WithMetrics(m Metrics) Option {
+	return func(s *Service) {
+		s.metrics = m
+	}
+}
+
+func (s *Service) observeLogin(outcome string) {
+	if s.metrics != nil {
+		s.metrics.ObserveLogin(outcome)
+	}
+}
+
+func (s *Service) observeTokenValidation(outcome string) {
+	if s.metrics != nil {
+		s.metrics.ObserveTokenValidation(outcome)
+	}
+}
+
+func (s *Service) observePrincipalCache(result string) {
+	if s.metrics != nil {
+		s.metrics.ObservePrincipalCache(result)
+	}
+}
@@ -0,0 +1,395 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+//go:generate mockery --name MFARepository
+type MFARepository interface {
+	// GetMFA returns domain.ErrNotFound when the user has not enrolled.
+	GetMFA(ctx context.Context, userID uint) (*domain.MFA, error)
+	SaveMFA(ctx context.Context, mfa *domain.MFA) error
+	DeleteMFA(ctx context.Context, userID uint) error
+	// UseTOTPStep reports false when a code of step or a later one was
+	// already accepted.
+	UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error)
+	ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error
+	// UseRecoveryCode reports false when there is no unused code with hash.
+	UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error)
+}
+
+const (
+	DefaultMFAChallengeTTL = 5 * time.Minute
+	RecoveryCodeCount      = 10
+	// MaxMFAChallengeFailures wrong codes void a challenge; the user has to
+	// log in again.
+	MaxMFAChallengeFailures = 5
+
+	mfaChallengePurpose  = "mfa-challenge"
+	mfaEnrollmentPurpose = "mfa-enrollment"
+)
+
+var ErrInvalidMFACode = errors.New("invalid MFA code")
+
+// MFAConfig controls TOTP two-factor authentication.
+type MFAConfig struct {
+	// Issuer names this service in authenticator apps.
+	Issuer string
+	// Secret signs the challenge tokens handed out between the password and
+	// the code step of a login.
+	Secret       []byte
+	ChallengeTTL time.Duration
+	// RequiredRoles lists the roles that must use MFA. Users with one of
+	// them have to enroll before they can finish logging in.
+	RequiredRoles []string
+}
+
+// WithMFA enables TOTP two-factor authentication.
+func WithMFA(repo MFARepository, cfg MFAConfig) Option {
+	return func(s *Service) {
+		cfg.ChallengeTTL = tools.Coalesce(cfg.ChallengeTTL, DefaultMFAChallengeTTL)
+		s.mfaRepo = repo
+		s.mfa = cfg
+	}
+}
+
+// EnrollMFA starts enrolling the user in MFA with a new secret. Enrolling
+// again before confirming replaces the pending secret.
+func (s *Service) EnrollMFA(ctx context.Context, userID uint) (enrollment *domain.MFAEnrollment, err error) {
+	ctx, span := startSpan(ctx, "EnrollMFA", attribute.Int64("user.id", int64(userID)))
+	defer func() { endSpan(span, err) }()
+
+	if s.mfaRepo == nil {
+		return nil, fmt.Errorf("%w: MFA is disabled", domain.ErrForbidden)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if existing != nil && existing.IsEnabled() {
+		return nil, fmt.Errorf("%w: MFA is already enabled", domain.ErrConflict)
+	}
+
+	secret, err := tools.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.SaveMFA(ctx, &domain.MFA{UserID: userID, Secret: secret}); err != nil {
+		return nil, err
+	}
+	return &domain.MFAEnrollment{
+		Secret: secret,
+		URI:    tools.TOTPURI(s.mfa.Issuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA enables a pending enrollment once the user proves their app
+// produces valid codes, and returns their recovery codes. The codes are not
+// stored and can not be shown again.
+func (s *Service) ConfirmMFA(ctx context.Context, userID uint, code string) (recoveryCodes []string, err error) {
+	ctx, span := startSpan(ctx, "ConfirmMFA", attribute.Int64("user.id", int64(userID)))
+	defer func() { endSpan(span, err) }()
+
+	if s.mfaRepo == nil {
+		return nil, fmt.Errorf("%w: MFA is disabled", domain.ErrForbidden)
+	}
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && mfa.IsEnabled()) {
+		return nil, fmt.Errorf("%w: no pending MFA enrollment", domain.ErrBadParamInput)
+	}
+	if err != nil {
+		return nil, err
+	}
+	step, ok := tools.ValidateTOTP(mfa.Secret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("%w: %v", domain.ErrBadParamInput, ErrInvalidMFACode)
+	}
+
+	now := time.Now()
+	mfa.EnabledAt = &now
+	mfa.LastUsedStep = step
+	if err := s.mfaRepo.SaveMFA(ctx, mfa); err != nil {
+		return nil, err
+	}
+	s.recordUser(ctx, domain.AuditMFAEnable, userID, nil, nil)
+	return s.newRecoveryCodes(ctx, userID)
+}
+
+// DisableMFA turns MFA off for the user after checking a current code or a
+// recovery code. Users whose role requires MFA can not turn it off. A wrong
+// code counts as a failed login.
+func (s *Service) DisableMFA(ctx context.Context, userID uint, code string) (err error) {
+	ctx, span := startSpan(ctx, "DisableMFA", attribute.Int64("user.id", int64(userID)))
+	defer func() { endSpan(span, err) }()
+
+	if s.mfaRepo == nil {
+		return fmt.Errorf("%w: MFA is disabled", domain.ErrForbidden)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if s.mfaRequired(user) {
+		return fmt.Errorf("%w: MFA is required for your role", domain.ErrForbidden)
+	}
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return fmt.Errorf("%w: MFA is not enabled", domain.ErrBadParamInput)
+	}
+	if err != nil {
+		return err
+	}
+	if mfa.IsEnabled() {
+		keys := s.loginKeys(ctx, user.Email)
+		if err := s.checkLoginLock(ctx, keys); err != nil {
+			return err
+		}
+		ok, err := s.checkMFACode(ctx, mfa, code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			s.recordLoginFailure(ctx, keys)
+			return fmt.Errorf("%w: %v", domain.ErrBadParamInput, ErrInvalidMFACode)
+		}
+		s.resetLoginAttempts(ctx, keys)
+	}
+	if err := s.mfaRepo.DeleteMFA(ctx, userID); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditMFADisable, userID, nil, nil)
+	return nil
+}
+
+// VerifyMFA finishes a login that AuthenticateUser answered with a
+// challenge, accepting either a TOTP code or an unused recovery code. A wrong
+// code counts as a failed login. With WithLoginLockout a challenge is spent
+// by the first right code or MaxMFAChallengeFailures wrong ones.
+func (s *Service) VerifyMFA(ctx context.Context, mfaToken, code string) (tokens *domain.AuthTokens, err error) {
+	ctx, span := startSpan(ctx, "VerifyMFA")
+	defer func() { endSpan(span, err) }()
+
+	if s.mfaRepo == nil {
+		return nil, fmt.Errorf("%w: MFA is disabled", domain.ErrForbidden)
+	}
+	// A user who had to enroll during login finishes with their enrollment
+	// token once the enrollment is confirmed.
+	userID, err := s.mfaTokenUser(mfaToken, mfaChallengePurpose)
+	if err != nil {
+		userID, err = s.mfaTokenUser(mfaToken, mfaEnrollmentPurpose)
+	}
+	if err != nil {
+		s.observeLogin(OutcomeInvalidMFACode)
+		return nil, ErrInvalidMFACode
+	}
+	span.SetAttributes(attribute.Int64("user.id", int64(userID)))
+
+	challenge := loginKey{key: mfaChallengeKey(mfaToken), policy: s.mfaChallengeLockout()}
+	spent, err := s.isMFAChallengeSpent(ctx, challenge)
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	if spent {
+		s.observeLogin(OutcomeInvalidMFACode)
+		return nil, ErrInvalidMFACode
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	keys := s.loginKeys(ctx, user.Email)
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			s.observeLogin(OutcomeLocked)
+		} else {
+			s.observeLogin(OutcomeError)
+		}
+		return nil, err
+	}
+
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !mfa.IsEnabled()) {
+		s.observeLogin(OutcomeInvalidMFACode)
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	ok, err := s.checkMFACode(ctx, mfa, code)
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	if !ok {
+		s.observeLogin(OutcomeInvalidMFACode)
+		s.recordLogin(ctx, domain.AuditLoginFailed, user.Email, user)
+		s.recordLoginFailure(ctx, append(keys, challenge))
+		return nil, ErrInvalidMFACode
+	}
+	s.resetLoginAttempts(ctx, keys)
+	if err := s.spendMFAChallenge(ctx, challenge); err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+
+	tokens, err = s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	s.observeLogin(OutcomeSuccess)
+	s.recordLogin(ctx, domain.AuditLogin, user.Email, user)
+	return tokens, nil
+}
+
+// ValidateMFAEnrollmentToken returns the user an enrollment token handed out
+// by AuthenticateUser belongs to. It lets users who must use MFA enroll
+// before they have an access token.
+func (s *Service) ValidateMFAEnrollmentToken(ctx context.Context, token string) (user *domain.User, err error) {
+	ctx, span := startSpan(ctx, "ValidateMFAEnrollmentToken")
+	defer func() { endSpan(span, err) }()
+
+	userID, err := s.mfaTokenUser(token, mfaEnrollmentPurpose)
+	if err != nil {
+		return nil, errors.New("invalid token")
+	}
+	return s.userRepo.GetUserByID(ctx, userID)
+}
+
+// mfaChallenge returns the challenge AuthenticateUser answers with instead
+// of tokens, or nil when the user does not need a second factor.
+func (s *Service) mfaChallenge(ctx context.Context, user *domain.User) (*domain.AuthTokens, error) {
+	if s.mfaRepo == nil {
+		return nil, nil
+	}
+	mfa, err := s.mfaRepo.GetMFA(ctx, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	purpose := mfaChallengePurpose
+	if mfa == nil || !mfa.IsEnabled() {
+		if !s.mfaRequired(user) {
+			return nil, nil
+		}
+		purpose = mfaEnrollmentPurpose
+	}
+	token, err := tools.SignToken(s.mfa.Secret, purpose, strconv.FormatUint(uint64(user.ID), 10), s.mfa.ChallengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.AuthTokens{
+		MFAToken:              token,
+		MFAEnrollmentRequired: purpose == mfaEnrollmentPurpose,
+	}, nil
+}
+
+func (s *Service) mfaRequired(user *domain.User) bool {
+	for _, role := range user.Roles {
+		if slices.Contains(s.mfa.RequiredRoles, role.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) mfaTokenUser(token, purpose string) (uint, error) {
+	subject, err := tools.VerifySignedToken(s.mfa.Secret, purpose, token)
+	if err != nil {
+		return 0, err
+	}
+	id, err := strconv.ParseUint(subject, 10, 32)
+	if err != nil {
+		return 0, tools.ErrInvalidSignedToken
+	}
+	return uint(id), nil
+}
+
+// mfaChallengeKey is the key the failures of one challenge token are counted
+// under. Only a hash of the token is stored.
+func mfaChallengeKey(token string) string {
+	return "mfa-challenge:" + tools.HashToken(token)
+}
+
+// mfaChallengeLockout locks a challenge for as long as it is valid once it
+// has failed MaxMFAChallengeFailures times.
+func (s *Service) mfaChallengeLockout() LockoutPolicy {
+	return LockoutPolicy{
+		FreeAttempts:    MaxMFAChallengeFailures,
+		MaxFailures:     MaxMFAChallengeFailures,
+		LockoutDuration: s.mfa.ChallengeTTL,
+	}
+}
+
+// isMFAChallengeSpent reports whether challenge was used or failed too often.
+func (s *Service) isMFAChallengeSpent(ctx context.Context, challenge loginKey) (bool, error) {
+	if s.loginAttempts == nil {
+		return false, nil
+	}
+	attempts, err := s.loginAttempts.GetLoginAttempts(ctx, challenge.key)
+	if errors.Is(err, domain.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return attempts.IsLocked(time.Now()), nil
+}
+
+// spendMFAChallenge locks challenge until the token expires, so it can not
+// finish a second login.
+func (s *Service) spendMFAChallenge(ctx context.Context, challenge loginKey) error {
+	if s.loginAttempts == nil {
+		return nil
+	}
+	return s.loginAttempts.LockLogin(ctx, challenge.key, time.Now().Add(s.mfa.ChallengeTTL))
+}
+
+// checkMFACode accepts a TOTP code that has not been used before or an
+// unused recovery code, which is then spent.
+func (s *Service) checkMFACode(ctx context.Context, mfa *domain.MFA, code string) (bool, error) {
+	if step, ok := tools.ValidateTOTP(mfa.Secret, code, time.Now()); ok {
+		return s.mfaRepo.UseTOTPStep(ctx, mfa.UserID, step)
+	}
+	return s.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, hashRecoveryCode(code))
+}
+
+func (s *Service) newRecoveryCodes(ctx context.Context, userID uint) ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code, err := tools.GenerateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+	if err := s.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode ignores case and separators, which are easily mistyped.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	return tools.HashToken(code)
+}
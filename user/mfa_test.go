@@ -0,0 +1,338 @@
+package user_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+var mfaSecret = []byte("mfa-secret")
+
+func newMFAService(repo *mocks.UserRepository, mfaRepo *mocks.MFARepository, requiredRoles ...string) *user.Service {
+	return user.NewService(repo, user.WithMFA(mfaRepo, user.MFAConfig{
+		Issuer:        "BB",
+		Secret:        mfaSecret,
+		RequiredRoles: requiredRoles,
+	}))
+}
+
+func currentCode(t *testing.T, secret string) string {
+	t.Helper()
+	code, err := tools.TOTPCode(secret, tools.TOTPStep(time.Now()))
+	require.NoError(t, err)
+	return code
+}
+
+func verifiedUser(t *testing.T, id uint, role string) *domain.User {
+	t.Helper()
+	u := &domain.User{ID: id, Email: "user@example.com", Password: "password123", Roles: []domain.Role{{Name: role}}, EmailVerifiedAt: verifiedAt()}
+	require.NoError(t, u.HashPassword())
+	return u
+}
+
+func TestService_AuthenticateUser_MFAChallenge(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo)
+
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: "S", EnabledAt: &enabledAt}, nil)
+
+	tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.MFAToken)
+	assert.False(t, tokens.MFAEnrollmentRequired)
+}
+
+func TestService_AuthenticateUser_WithoutMFA(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo, "admin")
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(nil, domain.ErrNotFound)
+
+	tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.Empty(t, tokens.MFAToken)
+}
+
+func TestService_AuthenticateUser_MFARequiredForRole(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo, "admin")
+
+	admin := verifiedUser(t, 1, "admin")
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(admin, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(admin, nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(1)).Return(nil, domain.ErrNotFound)
+
+	tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	assert.True(t, tokens.MFAEnrollmentRequired)
+
+	enrollingUser, err := service.ValidateMFAEnrollmentToken(context.Background(), tokens.MFAToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), enrollingUser.ID)
+
+	_, err = service.VerifyMFA(context.Background(), tokens.MFAToken, "123456")
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode, "an enrollment must be confirmed first")
+}
+
+func TestService_EnrollAndConfirmMFA(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo)
+
+	var pending *domain.MFA
+	var hashes []string
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(nil, domain.ErrNotFound).Once()
+	mockMFARepo.On("SaveMFA", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pending = args.Get(1).(*domain.MFA)
+	}).Return(nil)
+	mockMFARepo.On("ReplaceRecoveryCodes", mock.Anything, uint(7), mock.Anything).Run(func(args mock.Arguments) {
+		hashes = args.Get(2).([]string)
+	}).Return(nil)
+
+	enrollment, err := service.EnrollMFA(context.Background(), 7)
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, pending.Secret)
+	assert.False(t, pending.IsEnabled())
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/BB:user@example.com?"), enrollment.URI)
+
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(pending, nil)
+
+	_, err = service.ConfirmMFA(context.Background(), 7, "000000")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+
+	codes, err := service.ConfirmMFA(context.Background(), 7, currentCode(t, enrollment.Secret))
+	require.NoError(t, err)
+	assert.True(t, pending.IsEnabled())
+	assert.Len(t, codes, user.RecoveryCodeCount)
+	require.Len(t, hashes, user.RecoveryCodeCount)
+	assert.NotContains(t, hashes, codes[0], "recovery codes are stored hashed")
+}
+
+func TestService_EnrollMFA_AlreadyEnabled(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo)
+
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: "S", EnabledAt: &enabledAt}, nil)
+
+	_, err := service.EnrollMFA(context.Background(), 7)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockMFARepo.AssertNotCalled(t, "SaveMFA", mock.Anything, mock.Anything)
+}
+
+func TestService_VerifyMFA(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo)
+
+	secret, err := tools.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	mfa := &domain.MFA{UserID: 7, Secret: secret, EnabledAt: &enabledAt}
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(mfa, nil)
+	mockMFARepo.On("UseTOTPStep", mock.Anything, uint(7), mock.Anything).Return(true, nil).Once()
+	mockMFARepo.On("UseTOTPStep", mock.Anything, uint(7), mock.Anything).Return(false, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), mock.Anything).Return(false, nil)
+
+	challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "000000")
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode)
+
+	tokens, err := service.VerifyMFA(context.Background(), challenge.MFAToken, currentCode(t, secret))
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, currentCode(t, secret))
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode, "a code can not be replayed")
+
+	_, err = service.VerifyMFA(context.Background(), "forged", currentCode(t, secret))
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode)
+}
+
+func TestService_VerifyMFA_ChallengeIsSingleUse(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := user.NewService(mockUserRepo,
+		user.WithMFA(mockMFARepo, user.MFAConfig{Secret: mfaSecret}),
+		user.WithLoginLockout(repository.NewMemoryLoginAttemptRepository(), user.LockoutConfig{}))
+
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: "GEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), mock.Anything).Return(true, nil)
+
+	challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "ABCDE-12345")
+	require.NoError(t, err)
+
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "FGHIJ-67890")
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode, "the challenge was spent")
+	mockMFARepo.AssertNumberOfCalls(t, "UseRecoveryCode", 1)
+}
+
+func TestService_VerifyMFA_ChallengeFailureLimit(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	lenient := user.LockoutPolicy{FreeAttempts: 100, MaxFailures: 100, LockoutDuration: time.Hour}
+	service := user.NewService(mockUserRepo,
+		user.WithMFA(mockMFARepo, user.MFAConfig{Secret: mfaSecret}),
+		user.WithLoginLockout(repository.NewMemoryLoginAttemptRepository(), user.LockoutConfig{Account: lenient, ClientIP: lenient}))
+
+	secret, err := tools.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseTOTPStep", mock.Anything, uint(7), mock.Anything).Return(true, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), mock.Anything).Return(false, nil)
+
+	challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	for range user.MaxMFAChallengeFailures {
+		_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "wrong")
+		require.ErrorIs(t, err, user.ErrInvalidMFACode)
+	}
+
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, currentCode(t, secret))
+	assert.ErrorIs(t, err, user.ErrInvalidMFACode, "the challenge is void")
+	mockMFARepo.AssertNotCalled(t, "UseTOTPStep", mock.Anything, mock.Anything, mock.Anything)
+
+	challenge, err = service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, currentCode(t, secret))
+	assert.NoError(t, err, "a new login gets a new challenge")
+}
+
+func TestService_VerifyMFA_Lockout(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := user.NewService(mockUserRepo,
+		user.WithMFA(mockMFARepo, user.MFAConfig{Secret: mfaSecret}),
+		user.WithLoginLockout(repository.NewMemoryLoginAttemptRepository(), testLockout))
+
+	secret, err := tools.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), mock.Anything).Return(false, nil)
+
+	challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+	for range 3 {
+		_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "wrong")
+		require.ErrorIs(t, err, user.ErrInvalidMFACode)
+	}
+
+	// Wrong codes count like wrong passwords for the account.
+	_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, currentCode(t, secret))
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+	assert.ErrorIs(t, service.DisableMFA(context.Background(), 7, currentCode(t, secret)), domain.ErrTooManyRequests)
+	mockMFARepo.AssertNotCalled(t, "DeleteMFA", mock.Anything, mock.Anything)
+}
+
+func TestService_VerifyMFA_NewChallengesKeepFailures(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	policy := user.LockoutPolicy{FreeAttempts: 6, MaxFailures: 6, LockoutDuration: time.Hour}
+	service := user.NewService(mockUserRepo,
+		user.WithMFA(mockMFARepo, user.MFAConfig{Secret: mfaSecret}),
+		user.WithLoginLockout(repository.NewMemoryLoginAttemptRepository(), user.LockoutConfig{Account: policy, ClientIP: policy}))
+
+	secret, err := tools.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), mock.Anything).Return(false, nil)
+
+	// The password is known, so every login gets a fresh challenge.
+	for range 3 {
+		challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+		require.NoError(t, err)
+		require.NotEmpty(t, challenge.MFAToken)
+		for range 2 {
+			_, err = service.VerifyMFA(context.Background(), challenge.MFAToken, "wrong")
+			require.ErrorIs(t, err, user.ErrInvalidMFACode)
+		}
+	}
+
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+}
+
+func TestService_VerifyMFA_RecoveryCode(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo)
+
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: "GEZDGNBVGY3TQOJQ", EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseRecoveryCode", mock.Anything, uint(7), tools.HashToken("abcde12345")).Return(true, nil).Once()
+
+	challenge, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	require.NoError(t, err)
+
+	tokens, err := service.VerifyMFA(context.Background(), challenge.MFAToken, "ABCDE-12345")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	mockMFARepo.AssertExpectations(t)
+}
+
+func TestService_DisableMFA(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMFARepo := new(mocks.MFARepository)
+	service := newMFAService(mockUserRepo, mockMFARepo, "admin")
+
+	secret, err := tools.GenerateTOTPSecret()
+	require.NoError(t, err)
+	enabledAt := time.Now()
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(verifiedUser(t, 1, "admin"), nil)
+	mockMFARepo.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, Secret: secret, EnabledAt: &enabledAt}, nil)
+	mockMFARepo.On("UseTOTPStep", mock.Anything, uint(7), mock.Anything).Return(true, nil)
+	mockMFARepo.On("DeleteMFA", mock.Anything, uint(7)).Return(nil)
+
+	assert.ErrorIs(t, service.DisableMFA(context.Background(), 1, "123456"), domain.ErrForbidden)
+	assert.NoError(t, service.DisableMFA(context.Background(), 7, currentCode(t, secret)))
+	mockMFARepo.AssertCalled(t, "DeleteMFA", mock.Anything, uint(7))
+	mockMFARepo.AssertNotCalled(t, "DeleteMFA", mock.Anything, uint(1))
+}
@@ -0,0 +1,144 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// IdentityRepository is an autogenerated mock type for the IdentityRepository type
+type IdentityRepository struct {
+	mock.Mock
+}
+
+type IdentityRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *IdentityRepository) EXPECT() *IdentityRepository_Expecter {
+	return &IdentityRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateIdentity provides a mock function with given fields: ctx, identity
+func (_m *IdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	ret := _m.Called(ctx, identity)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIdentity")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserIdentity) error); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IdentityRepository_CreateIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIdentity'
+type IdentityRepository_CreateIdentity_Call struct {
+	*mock.Call
+}
+
+// CreateIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - identity *domain.UserIdentity
+func (_e *IdentityRepository_Expecter) CreateIdentity(ctx interface{}, identity interface{}) *IdentityRepository_CreateIdentity_Call {
+	return &IdentityRepository_CreateIdentity_Call{Call: _e.mock.On("CreateIdentity", ctx, identity)}
+}
+
+func (_c *IdentityRepository_CreateIdentity_Call) Run(run func(ctx context.Context, identity *domain.UserIdentity)) *IdentityRepository_CreateIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.UserIdentity))
+	})
+	return _c
+}
+
+func (_c *IdentityRepository_CreateIdentity_Call) Return(_a0 error) *IdentityRepository_CreateIdentity_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *IdentityRepository_CreateIdentity_Call) RunAndReturn(run func(context.Context, *domain.UserIdentity) error) *IdentityRepository_CreateIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetIdentity provides a mock function with given fields: ctx, provider, subject
+func (_m *IdentityRepository) GetIdentity(ctx context.Context, provider string, subject string) (*domain.UserIdentity, error) {
+	ret := _m.Called(ctx, provider, subject)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIdentity")
+	}
+
+	var r0 *domain.UserIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*domain.UserIdentity, error)); ok {
+		return rf(ctx, provider, subject)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *domain.UserIdentity); ok {
+		r0 = rf(ctx, provider, subject)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.UserIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, provider, subject)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// IdentityRepository_GetIdentity_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetIdentity'
+type IdentityRepository_GetIdentity_Call struct {
+	*mock.Call
+}
+
+// GetIdentity is a helper method to define mock.On call
+//   - ctx context.Context
+//   - provider string
+//   - subject string
+func (_e *IdentityRepository_Expecter) GetIdentity(ctx interface{}, provider interface{}, subject interface{}) *IdentityRepository_GetIdentity_Call {
+	return &IdentityRepository_GetIdentity_Call{Call: _e.mock.On("GetIdentity", ctx, provider, subject)}
+}
+
+func (_c *IdentityRepository_GetIdentity_Call) Run(run func(ctx context.Context, provider string, subject string)) *IdentityRepository_GetIdentity_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *IdentityRepository_GetIdentity_Call) Return(_a0 *domain.UserIdentity, _a1 error) *IdentityRepository_GetIdentity_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *IdentityRepository_GetIdentity_Call) RunAndReturn(run func(context.Context, string, string) (*domain.UserIdentity, error)) *IdentityRepository_GetIdentity_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewIdentityRepository creates a new instance of IdentityRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewIdentityRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *IdentityRepository {
+	mock := &IdentityRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,309 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+
+	time "time"
+)
+
+// LoginAttemptStore is an autogenerated mock type for the LoginAttemptStore type
+type LoginAttemptStore struct {
+	mock.Mock
+}
+
+type LoginAttemptStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *LoginAttemptStore) EXPECT() *LoginAttemptStore_Expecter {
+	return &LoginAttemptStore_Expecter{mock: &_m.Mock}
+}
+
+// DeleteStaleLoginAttempts provides a mock function with given fields: ctx, before
+func (_m *LoginAttemptStore) DeleteStaleLoginAttempts(ctx context.Context, before time.Time) (int64, error) {
+	ret := _m.Called(ctx, before)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteStaleLoginAttempts")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, before)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginAttemptStore_DeleteStaleLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteStaleLoginAttempts'
+type LoginAttemptStore_DeleteStaleLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// DeleteStaleLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+func (_e *LoginAttemptStore_Expecter) DeleteStaleLoginAttempts(ctx interface{}, before interface{}) *LoginAttemptStore_DeleteStaleLoginAttempts_Call {
+	return &LoginAttemptStore_DeleteStaleLoginAttempts_Call{Call: _e.mock.On("DeleteStaleLoginAttempts", ctx, before)}
+}
+
+func (_c *LoginAttemptStore_DeleteStaleLoginAttempts_Call) Run(run func(ctx context.Context, before time.Time)) *LoginAttemptStore_DeleteStaleLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptStore_DeleteStaleLoginAttempts_Call) Return(_a0 int64, _a1 error) *LoginAttemptStore_DeleteStaleLoginAttempts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LoginAttemptStore_DeleteStaleLoginAttempts_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *LoginAttemptStore_DeleteStaleLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetLoginAttempts provides a mock function with given fields: ctx, key
+func (_m *LoginAttemptStore) GetLoginAttempts(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLoginAttempts")
+	}
+
+	var r0 *domain.LoginAttempts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.LoginAttempts, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.LoginAttempts); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.LoginAttempts)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginAttemptStore_GetLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetLoginAttempts'
+type LoginAttemptStore_GetLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// GetLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *LoginAttemptStore_Expecter) GetLoginAttempts(ctx interface{}, key interface{}) *LoginAttemptStore_GetLoginAttempts_Call {
+	return &LoginAttemptStore_GetLoginAttempts_Call{Call: _e.mock.On("GetLoginAttempts", ctx, key)}
+}
+
+func (_c *LoginAttemptStore_GetLoginAttempts_Call) Run(run func(ctx context.Context, key string)) *LoginAttemptStore_GetLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptStore_GetLoginAttempts_Call) Return(_a0 *domain.LoginAttempts, _a1 error) *LoginAttemptStore_GetLoginAttempts_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LoginAttemptStore_GetLoginAttempts_Call) RunAndReturn(run func(context.Context, string) (*domain.LoginAttempts, error)) *LoginAttemptStore_GetLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockLogin provides a mock function with given fields: ctx, key, until
+func (_m *LoginAttemptStore) LockLogin(ctx context.Context, key string, until time.Time) error {
+	ret := _m.Called(ctx, key, until)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, key, until)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoginAttemptStore_LockLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockLogin'
+type LoginAttemptStore_LockLogin_Call struct {
+	*mock.Call
+}
+
+// LockLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - until time.Time
+func (_e *LoginAttemptStore_Expecter) LockLogin(ctx interface{}, key interface{}, until interface{}) *LoginAttemptStore_LockLogin_Call {
+	return &LoginAttemptStore_LockLogin_Call{Call: _e.mock.On("LockLogin", ctx, key, until)}
+}
+
+func (_c *LoginAttemptStore_LockLogin_Call) Run(run func(ctx context.Context, key string, until time.Time)) *LoginAttemptStore_LockLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptStore_LockLogin_Call) Return(_a0 error) *LoginAttemptStore_LockLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LoginAttemptStore_LockLogin_Call) RunAndReturn(run func(context.Context, string, time.Time) error) *LoginAttemptStore_LockLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RecordLoginFailure provides a mock function with given fields: ctx, key, now, window
+func (_m *LoginAttemptStore) RecordLoginFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, key, now, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecordLoginFailure")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) (int, error)); ok {
+		return rf(ctx, key, now, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, time.Duration) int); ok {
+		r0 = rf(ctx, key, now, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, time.Duration) error); ok {
+		r1 = rf(ctx, key, now, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LoginAttemptStore_RecordLoginFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RecordLoginFailure'
+type LoginAttemptStore_RecordLoginFailure_Call struct {
+	*mock.Call
+}
+
+// RecordLoginFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - now time.Time
+//   - window time.Duration
+func (_e *LoginAttemptStore_Expecter) RecordLoginFailure(ctx interface{}, key interface{}, now interface{}, window interface{}) *LoginAttemptStore_RecordLoginFailure_Call {
+	return &LoginAttemptStore_RecordLoginFailure_Call{Call: _e.mock.On("RecordLoginFailure", ctx, key, now, window)}
+}
+
+func (_c *LoginAttemptStore_RecordLoginFailure_Call) Run(run func(ctx context.Context, key string, now time.Time, window time.Duration)) *LoginAttemptStore_RecordLoginFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptStore_RecordLoginFailure_Call) Return(_a0 int, _a1 error) *LoginAttemptStore_RecordLoginFailure_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *LoginAttemptStore_RecordLoginFailure_Call) RunAndReturn(run func(context.Context, string, time.Time, time.Duration) (int, error)) *LoginAttemptStore_RecordLoginFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResetLoginAttempts provides a mock function with given fields: ctx, key
+func (_m *LoginAttemptStore) ResetLoginAttempts(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResetLoginAttempts")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// LoginAttemptStore_ResetLoginAttempts_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResetLoginAttempts'
+type LoginAttemptStore_ResetLoginAttempts_Call struct {
+	*mock.Call
+}
+
+// ResetLoginAttempts is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *LoginAttemptStore_Expecter) ResetLoginAttempts(ctx interface{}, key interface{}) *LoginAttemptStore_ResetLoginAttempts_Call {
+	return &LoginAttemptStore_ResetLoginAttempts_Call{Call: _e.mock.On("ResetLoginAttempts", ctx, key)}
+}
+
+func (_c *LoginAttemptStore_ResetLoginAttempts_Call) Run(run func(ctx context.Context, key string)) *LoginAttemptStore_ResetLoginAttempts_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *LoginAttemptStore_ResetLoginAttempts_Call) Return(_a0 error) *LoginAttemptStore_ResetLoginAttempts_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *LoginAttemptStore_ResetLoginAttempts_Call) RunAndReturn(run func(context.Context, string) error) *LoginAttemptStore_ResetLoginAttempts_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewLoginAttemptStore creates a new instance of LoginAttemptStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLoginAttemptStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LoginAttemptStore {
+	mock := &LoginAttemptStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,354 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// MFARepository is an autogenerated mock type for the MFARepository type
+type MFARepository struct {
+	mock.Mock
+}
+
+type MFARepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MFARepository) EXPECT() *MFARepository_Expecter {
+	return &MFARepository_Expecter{mock: &_m.Mock}
+}
+
+// DeleteMFA provides a mock function with given fields: ctx, userID
+func (_m *MFARepository) DeleteMFA(ctx context.Context, userID uint) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MFARepository_DeleteMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMFA'
+type MFARepository_DeleteMFA_Call struct {
+	*mock.Call
+}
+
+// DeleteMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MFARepository_Expecter) DeleteMFA(ctx interface{}, userID interface{}) *MFARepository_DeleteMFA_Call {
+	return &MFARepository_DeleteMFA_Call{Call: _e.mock.On("DeleteMFA", ctx, userID)}
+}
+
+func (_c *MFARepository_DeleteMFA_Call) Run(run func(ctx context.Context, userID uint)) *MFARepository_DeleteMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MFARepository_DeleteMFA_Call) Return(_a0 error) *MFARepository_DeleteMFA_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MFARepository_DeleteMFA_Call) RunAndReturn(run func(context.Context, uint) error) *MFARepository_DeleteMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMFA provides a mock function with given fields: ctx, userID
+func (_m *MFARepository) GetMFA(ctx context.Context, userID uint) (*domain.MFA, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMFA")
+	}
+
+	var r0 *domain.MFA
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domain.MFA, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.MFA); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.MFA)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MFARepository_GetMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMFA'
+type MFARepository_GetMFA_Call struct {
+	*mock.Call
+}
+
+// GetMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *MFARepository_Expecter) GetMFA(ctx interface{}, userID interface{}) *MFARepository_GetMFA_Call {
+	return &MFARepository_GetMFA_Call{Call: _e.mock.On("GetMFA", ctx, userID)}
+}
+
+func (_c *MFARepository_GetMFA_Call) Run(run func(ctx context.Context, userID uint)) *MFARepository_GetMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *MFARepository_GetMFA_Call) Return(_a0 *domain.MFA, _a1 error) *MFARepository_GetMFA_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MFARepository_GetMFA_Call) RunAndReturn(run func(context.Context, uint) (*domain.MFA, error)) *MFARepository_GetMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: ctx, userID, hashes
+func (_m *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userID uint, hashes []string) error {
+	ret := _m.Called(ctx, userID, hashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, []string) error); ok {
+		r0 = rf(ctx, userID, hashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MFARepository_ReplaceRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceRecoveryCodes'
+type MFARepository_ReplaceRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// ReplaceRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - hashes []string
+func (_e *MFARepository_Expecter) ReplaceRecoveryCodes(ctx interface{}, userID interface{}, hashes interface{}) *MFARepository_ReplaceRecoveryCodes_Call {
+	return &MFARepository_ReplaceRecoveryCodes_Call{Call: _e.mock.On("ReplaceRecoveryCodes", ctx, userID, hashes)}
+}
+
+func (_c *MFARepository_ReplaceRecoveryCodes_Call) Run(run func(ctx context.Context, userID uint, hashes []string)) *MFARepository_ReplaceRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MFARepository_ReplaceRecoveryCodes_Call) Return(_a0 error) *MFARepository_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MFARepository_ReplaceRecoveryCodes_Call) RunAndReturn(run func(context.Context, uint, []string) error) *MFARepository_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveMFA provides a mock function with given fields: ctx, mfa
+func (_m *MFARepository) SaveMFA(ctx context.Context, mfa *domain.MFA) error {
+	ret := _m.Called(ctx, mfa)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveMFA")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.MFA) error); ok {
+		r0 = rf(ctx, mfa)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MFARepository_SaveMFA_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveMFA'
+type MFARepository_SaveMFA_Call struct {
+	*mock.Call
+}
+
+// SaveMFA is a helper method to define mock.On call
+//   - ctx context.Context
+//   - mfa *domain.MFA
+func (_e *MFARepository_Expecter) SaveMFA(ctx interface{}, mfa interface{}) *MFARepository_SaveMFA_Call {
+	return &MFARepository_SaveMFA_Call{Call: _e.mock.On("SaveMFA", ctx, mfa)}
+}
+
+func (_c *MFARepository_SaveMFA_Call) Run(run func(ctx context.Context, mfa *domain.MFA)) *MFARepository_SaveMFA_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.MFA))
+	})
+	return _c
+}
+
+func (_c *MFARepository_SaveMFA_Call) Return(_a0 error) *MFARepository_SaveMFA_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MFARepository_SaveMFA_Call) RunAndReturn(run func(context.Context, *domain.MFA) error) *MFARepository_SaveMFA_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, hash
+func (_m *MFARepository) UseRecoveryCode(ctx context.Context, userID uint, hash string) (bool, error) {
+	ret := _m.Called(ctx, userID, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) (bool, error)); ok {
+		return rf(ctx, userID, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, string) bool); ok {
+		r0 = rf(ctx, userID, hash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, string) error); ok {
+		r1 = rf(ctx, userID, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MFARepository_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MFARepository_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - hash string
+func (_e *MFARepository_Expecter) UseRecoveryCode(ctx interface{}, userID interface{}, hash interface{}) *MFARepository_UseRecoveryCode_Call {
+	return &MFARepository_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, userID, hash)}
+}
+
+func (_c *MFARepository_UseRecoveryCode_Call) Run(run func(ctx context.Context, userID uint, hash string)) *MFARepository_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MFARepository_UseRecoveryCode_Call) Return(_a0 bool, _a1 error) *MFARepository_UseRecoveryCode_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MFARepository_UseRecoveryCode_Call) RunAndReturn(run func(context.Context, uint, string) (bool, error)) *MFARepository_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *MFARepository) UseTOTPStep(ctx context.Context, userID uint, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MFARepository_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MFARepository_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - step int64
+func (_e *MFARepository_Expecter) UseTOTPStep(ctx interface{}, userID interface{}, step interface{}) *MFARepository_UseTOTPStep_Call {
+	return &MFARepository_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", ctx, userID, step)}
+}
+
+func (_c *MFARepository_UseTOTPStep_Call) Run(run func(ctx context.Context, userID uint, step int64)) *MFARepository_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MFARepository_UseTOTPStep_Call) Return(_a0 bool, _a1 error) *MFARepository_UseTOTPStep_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MFARepository_UseTOTPStep_Call) RunAndReturn(run func(context.Context, uint, int64) (bool, error)) *MFARepository_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMFARepository creates a new instance of MFARepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFARepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFARepository {
+	mock := &MFARepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
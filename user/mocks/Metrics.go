@@ -0,0 +1,131 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Metrics is an autogenerated mock type for the Metrics type
+type Metrics struct {
+	mock.Mock
+}
+
+type Metrics_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *Metrics) EXPECT() *Metrics_Expecter {
+	return &Metrics_Expecter{mock: &_m.Mock}
+}
+
+// ObserveLogin provides a mock function with given fields: outcome
+func (_m *Metrics) ObserveLogin(outcome string) {
+	_m.Called(outcome)
+}
+
+// Metrics_ObserveLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveLogin'
+type Metrics_ObserveLogin_Call struct {
+	*mock.Call
+}
+
+// ObserveLogin is a helper method to define mock.On call
+//   - outcome string
+func (_e *Metrics_Expecter) ObserveLogin(outcome interface{}) *Metrics_ObserveLogin_Call {
+	return &Metrics_ObserveLogin_Call{Call: _e.mock.On("ObserveLogin", outcome)}
+}
+
+func (_c *Metrics_ObserveLogin_Call) Run(run func(outcome string)) *Metrics_ObserveLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Metrics_ObserveLogin_Call) Return() *Metrics_ObserveLogin_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_ObserveLogin_Call) RunAndReturn(run func(string)) *Metrics_ObserveLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ObservePrincipalCache provides a mock function with given fields: result
+func (_m *Metrics) ObservePrincipalCache(result string) {
+	_m.Called(result)
+}
+
+// Metrics_ObservePrincipalCache_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObservePrincipalCache'
+type Metrics_ObservePrincipalCache_Call struct {
+	*mock.Call
+}
+
+// ObservePrincipalCache is a helper method to define mock.On call
+//   - result string
+func (_e *Metrics_Expecter) ObservePrincipalCache(result interface{}) *Metrics_ObservePrincipalCache_Call {
+	return &Metrics_ObservePrincipalCache_Call{Call: _e.mock.On("ObservePrincipalCache", result)}
+}
+
+func (_c *Metrics_ObservePrincipalCache_Call) Run(run func(result string)) *Metrics_ObservePrincipalCache_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Metrics_ObservePrincipalCache_Call) Return() *Metrics_ObservePrincipalCache_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_ObservePrincipalCache_Call) RunAndReturn(run func(string)) *Metrics_ObservePrincipalCache_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ObserveTokenValidation provides a mock function with given fields: outcome
+func (_m *Metrics) ObserveTokenValidation(outcome string) {
+	_m.Called(outcome)
+}
+
+// Metrics_ObserveTokenValidation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ObserveTokenValidation'
+type Metrics_ObserveTokenValidation_Call struct {
+	*mock.Call
+}
+
+// ObserveTokenValidation is a helper method to define mock.On call
+//   - outcome string
+func (_e *Metrics_Expecter) ObserveTokenValidation(outcome interface{}) *Metrics_ObserveTokenValidation_Call {
+	return &Metrics_ObserveTokenValidation_Call{Call: _e.mock.On("ObserveTokenValidation", outcome)}
+}
+
+func (_c *Metrics_ObserveTokenValidation_Call) Run(run func(outcome string)) *Metrics_ObserveTokenValidation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(string))
+	})
+	return _c
+}
+
+func (_c *Metrics_ObserveTokenValidation_Call) Return() *Metrics_ObserveTokenValidation_Call {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *Metrics_ObserveTokenValidation_Call) RunAndReturn(run func(string)) *Metrics_ObserveTokenValidation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMetrics creates a new instance of Metrics. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMetrics(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Metrics {
+	mock := &Metrics{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
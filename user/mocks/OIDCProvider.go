@@ -0,0 +1,157 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// OIDCProvider is an autogenerated mock type for the OIDCProvider type
+type OIDCProvider struct {
+	mock.Mock
+}
+
+type OIDCProvider_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *OIDCProvider) EXPECT() *OIDCProvider_Expecter {
+	return &OIDCProvider_Expecter{mock: &_m.Mock}
+}
+
+// AuthCodeURL provides a mock function with given fields: ctx, state, nonce, codeVerifier
+func (_m *OIDCProvider) AuthCodeURL(ctx context.Context, state string, nonce string, codeVerifier string) (string, error) {
+	ret := _m.Called(ctx, state, nonce, codeVerifier)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthCodeURL")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (string, error)); ok {
+		return rf(ctx, state, nonce, codeVerifier)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) string); ok {
+		r0 = rf(ctx, state, nonce, codeVerifier)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, state, nonce, codeVerifier)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OIDCProvider_AuthCodeURL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthCodeURL'
+type OIDCProvider_AuthCodeURL_Call struct {
+	*mock.Call
+}
+
+// AuthCodeURL is a helper method to define mock.On call
+//   - ctx context.Context
+//   - state string
+//   - nonce string
+//   - codeVerifier string
+func (_e *OIDCProvider_Expecter) AuthCodeURL(ctx interface{}, state interface{}, nonce interface{}, codeVerifier interface{}) *OIDCProvider_AuthCodeURL_Call {
+	return &OIDCProvider_AuthCodeURL_Call{Call: _e.mock.On("AuthCodeURL", ctx, state, nonce, codeVerifier)}
+}
+
+func (_c *OIDCProvider_AuthCodeURL_Call) Run(run func(ctx context.Context, state string, nonce string, codeVerifier string)) *OIDCProvider_AuthCodeURL_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *OIDCProvider_AuthCodeURL_Call) Return(_a0 string, _a1 error) *OIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OIDCProvider_AuthCodeURL_Call) RunAndReturn(run func(context.Context, string, string, string) (string, error)) *OIDCProvider_AuthCodeURL_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Exchange provides a mock function with given fields: ctx, code, codeVerifier, nonce
+func (_m *OIDCProvider) Exchange(ctx context.Context, code string, codeVerifier string, nonce string) (*domain.OIDCIdentity, error) {
+	ret := _m.Called(ctx, code, codeVerifier, nonce)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exchange")
+	}
+
+	var r0 *domain.OIDCIdentity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) (*domain.OIDCIdentity, error)); ok {
+		return rf(ctx, code, codeVerifier, nonce)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) *domain.OIDCIdentity); ok {
+		r0 = rf(ctx, code, codeVerifier, nonce)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.OIDCIdentity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, string) error); ok {
+		r1 = rf(ctx, code, codeVerifier, nonce)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// OIDCProvider_Exchange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Exchange'
+type OIDCProvider_Exchange_Call struct {
+	*mock.Call
+}
+
+// Exchange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - code string
+//   - codeVerifier string
+//   - nonce string
+func (_e *OIDCProvider_Expecter) Exchange(ctx interface{}, code interface{}, codeVerifier interface{}, nonce interface{}) *OIDCProvider_Exchange_Call {
+	return &OIDCProvider_Exchange_Call{Call: _e.mock.On("Exchange", ctx, code, codeVerifier, nonce)}
+}
+
+func (_c *OIDCProvider_Exchange_Call) Run(run func(ctx context.Context, code string, codeVerifier string, nonce string)) *OIDCProvider_Exchange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *OIDCProvider_Exchange_Call) Return(_a0 *domain.OIDCIdentity, _a1 error) *OIDCProvider_Exchange_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *OIDCProvider_Exchange_Call) RunAndReturn(run func(context.Context, string, string, string) (*domain.OIDCIdentity, error)) *OIDCProvider_Exchange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewOIDCProvider creates a new instance of OIDCProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOIDCProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OIDCProvider {
+	mock := &OIDCProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,247 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// PasswordResetTokenRepository is an autogenerated mock type for the PasswordResetTokenRepository type
+type PasswordResetTokenRepository struct {
+	mock.Mock
+}
+
+type PasswordResetTokenRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PasswordResetTokenRepository) EXPECT() *PasswordResetTokenRepository_Expecter {
+	return &PasswordResetTokenRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreatePasswordResetToken provides a mock function with given fields: ctx, token
+func (_m *PasswordResetTokenRepository) CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreatePasswordResetToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.PasswordResetToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PasswordResetTokenRepository_CreatePasswordResetToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreatePasswordResetToken'
+type PasswordResetTokenRepository_CreatePasswordResetToken_Call struct {
+	*mock.Call
+}
+
+// CreatePasswordResetToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *domain.PasswordResetToken
+func (_e *PasswordResetTokenRepository_Expecter) CreatePasswordResetToken(ctx interface{}, token interface{}) *PasswordResetTokenRepository_CreatePasswordResetToken_Call {
+	return &PasswordResetTokenRepository_CreatePasswordResetToken_Call{Call: _e.mock.On("CreatePasswordResetToken", ctx, token)}
+}
+
+func (_c *PasswordResetTokenRepository_CreatePasswordResetToken_Call) Run(run func(ctx context.Context, token *domain.PasswordResetToken)) *PasswordResetTokenRepository_CreatePasswordResetToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.PasswordResetToken))
+	})
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_CreatePasswordResetToken_Call) Return(_a0 error) *PasswordResetTokenRepository_CreatePasswordResetToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_CreatePasswordResetToken_Call) RunAndReturn(run func(context.Context, *domain.PasswordResetToken) error) *PasswordResetTokenRepository_CreatePasswordResetToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetPasswordResetTokenByHash provides a mock function with given fields: ctx, hash
+func (_m *PasswordResetTokenRepository) GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPasswordResetTokenByHash")
+	}
+
+	var r0 *domain.PasswordResetToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.PasswordResetToken, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.PasswordResetToken); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.PasswordResetToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetPasswordResetTokenByHash'
+type PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call struct {
+	*mock.Call
+}
+
+// GetPasswordResetTokenByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *PasswordResetTokenRepository_Expecter) GetPasswordResetTokenByHash(ctx interface{}, hash interface{}) *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call {
+	return &PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call{Call: _e.mock.On("GetPasswordResetTokenByHash", ctx, hash)}
+}
+
+func (_c *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call) Run(run func(ctx context.Context, hash string)) *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call) Return(_a0 *domain.PasswordResetToken, _a1 error) *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call) RunAndReturn(run func(context.Context, string) (*domain.PasswordResetToken, error)) *PasswordResetTokenRepository_GetPasswordResetTokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidatePasswordResetTokens provides a mock function with given fields: ctx, userID
+func (_m *PasswordResetTokenRepository) InvalidatePasswordResetTokens(ctx context.Context, userID uint) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidatePasswordResetTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidatePasswordResetTokens'
+type PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call struct {
+	*mock.Call
+}
+
+// InvalidatePasswordResetTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *PasswordResetTokenRepository_Expecter) InvalidatePasswordResetTokens(ctx interface{}, userID interface{}) *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call {
+	return &PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call{Call: _e.mock.On("InvalidatePasswordResetTokens", ctx, userID)}
+}
+
+func (_c *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call) Run(run func(ctx context.Context, userID uint)) *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call) Return(_a0 error) *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call) RunAndReturn(run func(context.Context, uint) error) *PasswordResetTokenRepository_InvalidatePasswordResetTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkPasswordResetTokenUsed provides a mock function with given fields: ctx, id
+func (_m *PasswordResetTokenRepository) MarkPasswordResetTokenUsed(ctx context.Context, id uint) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkPasswordResetTokenUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkPasswordResetTokenUsed'
+type PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call struct {
+	*mock.Call
+}
+
+// MarkPasswordResetTokenUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *PasswordResetTokenRepository_Expecter) MarkPasswordResetTokenUsed(ctx interface{}, id interface{}) *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call {
+	return &PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call{Call: _e.mock.On("MarkPasswordResetTokenUsed", ctx, id)}
+}
+
+func (_c *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call) Run(run func(ctx context.Context, id uint)) *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call) Return(_a0 bool, _a1 error) *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call) RunAndReturn(run func(context.Context, uint) (bool, error)) *PasswordResetTokenRepository_MarkPasswordResetTokenUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPasswordResetTokenRepository creates a new instance of PasswordResetTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetTokenRepository {
+	mock := &PasswordResetTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
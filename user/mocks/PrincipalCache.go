@@ -0,0 +1,197 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// PrincipalCache is an autogenerated mock type for the PrincipalCache type
+type PrincipalCache struct {
+	mock.Mock
+}
+
+type PrincipalCache_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *PrincipalCache) EXPECT() *PrincipalCache_Expecter {
+	return &PrincipalCache_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function with given fields: ctx, id
+func (_m *PrincipalCache) Delete(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PrincipalCache_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type PrincipalCache_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *PrincipalCache_Expecter) Delete(ctx interface{}, id interface{}) *PrincipalCache_Delete_Call {
+	return &PrincipalCache_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+}
+
+func (_c *PrincipalCache_Delete_Call) Run(run func(ctx context.Context, id uint)) *PrincipalCache_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *PrincipalCache_Delete_Call) Return(_a0 error) *PrincipalCache_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PrincipalCache_Delete_Call) RunAndReturn(run func(context.Context, uint) error) *PrincipalCache_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function with given fields: ctx, id
+func (_m *PrincipalCache) Get(ctx context.Context, id uint) (*domain.User, bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.User
+	var r1 bool
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domain.User, bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.User); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) bool); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uint) error); ok {
+		r2 = rf(ctx, id)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// PrincipalCache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type PrincipalCache_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *PrincipalCache_Expecter) Get(ctx interface{}, id interface{}) *PrincipalCache_Get_Call {
+	return &PrincipalCache_Get_Call{Call: _e.mock.On("Get", ctx, id)}
+}
+
+func (_c *PrincipalCache_Get_Call) Run(run func(ctx context.Context, id uint)) *PrincipalCache_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *PrincipalCache_Get_Call) Return(_a0 *domain.User, _a1 bool, _a2 error) *PrincipalCache_Get_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *PrincipalCache_Get_Call) RunAndReturn(run func(context.Context, uint) (*domain.User, bool, error)) *PrincipalCache_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Set provides a mock function with given fields: ctx, _a1
+func (_m *PrincipalCache) Set(ctx context.Context, _a1 *domain.User) error {
+	ret := _m.Called(ctx, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Set")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PrincipalCache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
+type PrincipalCache_Set_Call struct {
+	*mock.Call
+}
+
+// Set is a helper method to define mock.On call
+//   - ctx context.Context
+//   - _a1 *domain.User
+func (_e *PrincipalCache_Expecter) Set(ctx interface{}, _a1 interface{}) *PrincipalCache_Set_Call {
+	return &PrincipalCache_Set_Call{Call: _e.mock.On("Set", ctx, _a1)}
+}
+
+func (_c *PrincipalCache_Set_Call) Run(run func(ctx context.Context, _a1 *domain.User)) *PrincipalCache_Set_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.User))
+	})
+	return _c
+}
+
+func (_c *PrincipalCache_Set_Call) Return(_a0 error) *PrincipalCache_Set_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *PrincipalCache_Set_Call) RunAndReturn(run func(context.Context, *domain.User) error) *PrincipalCache_Set_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewPrincipalCache creates a new instance of PrincipalCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPrincipalCache(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PrincipalCache {
+	mock := &PrincipalCache{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,294 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+	domain "github.com/tat-101/bb-assignment-back/domain"
+)
+
+// RefreshTokenRepository is an autogenerated mock type for the RefreshTokenRepository type
+type RefreshTokenRepository struct {
+	mock.Mock
+}
+
+type RefreshTokenRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RefreshTokenRepository) EXPECT() *RefreshTokenRepository_Expecter {
+	return &RefreshTokenRepository_Expecter{mock: &_m.Mock}
+}
+
+// CreateRefreshToken provides a mock function with given fields: ctx, token
+func (_m *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateRefreshToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.RefreshToken) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepository_CreateRefreshToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateRefreshToken'
+type RefreshTokenRepository_CreateRefreshToken_Call struct {
+	*mock.Call
+}
+
+// CreateRefreshToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token *domain.RefreshToken
+func (_e *RefreshTokenRepository_Expecter) CreateRefreshToken(ctx interface{}, token interface{}) *RefreshTokenRepository_CreateRefreshToken_Call {
+	return &RefreshTokenRepository_CreateRefreshToken_Call{Call: _e.mock.On("CreateRefreshToken", ctx, token)}
+}
+
+func (_c *RefreshTokenRepository_CreateRefreshToken_Call) Run(run func(ctx context.Context, token *domain.RefreshToken)) *RefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.RefreshToken))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepository_CreateRefreshToken_Call) Return(_a0 error) *RefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepository_CreateRefreshToken_Call) RunAndReturn(run func(context.Context, *domain.RefreshToken) error) *RefreshTokenRepository_CreateRefreshToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetRefreshTokenByHash provides a mock function with given fields: ctx, hash
+func (_m *RefreshTokenRepository) GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRefreshTokenByHash")
+	}
+
+	var r0 *domain.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.RefreshToken, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.RefreshToken); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshTokenRepository_GetRefreshTokenByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetRefreshTokenByHash'
+type RefreshTokenRepository_GetRefreshTokenByHash_Call struct {
+	*mock.Call
+}
+
+// GetRefreshTokenByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hash string
+func (_e *RefreshTokenRepository_Expecter) GetRefreshTokenByHash(ctx interface{}, hash interface{}) *RefreshTokenRepository_GetRefreshTokenByHash_Call {
+	return &RefreshTokenRepository_GetRefreshTokenByHash_Call{Call: _e.mock.On("GetRefreshTokenByHash", ctx, hash)}
+}
+
+func (_c *RefreshTokenRepository_GetRefreshTokenByHash_Call) Run(run func(ctx context.Context, hash string)) *RefreshTokenRepository_GetRefreshTokenByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepository_GetRefreshTokenByHash_Call) Return(_a0 *domain.RefreshToken, _a1 error) *RefreshTokenRepository_GetRefreshTokenByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RefreshTokenRepository_GetRefreshTokenByHash_Call) RunAndReturn(run func(context.Context, string) (*domain.RefreshToken, error)) *RefreshTokenRepository_GetRefreshTokenByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRefreshTokenUsed provides a mock function with given fields: ctx, id
+func (_m *RefreshTokenRepository) MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRefreshTokenUsed")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (bool, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) bool); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RefreshTokenRepository_MarkRefreshTokenUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRefreshTokenUsed'
+type RefreshTokenRepository_MarkRefreshTokenUsed_Call struct {
+	*mock.Call
+}
+
+// MarkRefreshTokenUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *RefreshTokenRepository_Expecter) MarkRefreshTokenUsed(ctx interface{}, id interface{}) *RefreshTokenRepository_MarkRefreshTokenUsed_Call {
+	return &RefreshTokenRepository_MarkRefreshTokenUsed_Call{Call: _e.mock.On("MarkRefreshTokenUsed", ctx, id)}
+}
+
+func (_c *RefreshTokenRepository_MarkRefreshTokenUsed_Call) Run(run func(ctx context.Context, id uint)) *RefreshTokenRepository_MarkRefreshTokenUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepository_MarkRefreshTokenUsed_Call) Return(_a0 bool, _a1 error) *RefreshTokenRepository_MarkRefreshTokenUsed_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RefreshTokenRepository_MarkRefreshTokenUsed_Call) RunAndReturn(run func(context.Context, uint) (bool, error)) *RefreshTokenRepository_MarkRefreshTokenUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshTokenFamily provides a mock function with given fields: ctx, familyID
+func (_m *RefreshTokenRepository) RevokeRefreshTokenFamily(ctx context.Context, familyID string) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokenFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepository_RevokeRefreshTokenFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshTokenFamily'
+type RefreshTokenRepository_RevokeRefreshTokenFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshTokenFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID string
+func (_e *RefreshTokenRepository_Expecter) RevokeRefreshTokenFamily(ctx interface{}, familyID interface{}) *RefreshTokenRepository_RevokeRefreshTokenFamily_Call {
+	return &RefreshTokenRepository_RevokeRefreshTokenFamily_Call{Call: _e.mock.On("RevokeRefreshTokenFamily", ctx, familyID)}
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokenFamily_Call) Run(run func(ctx context.Context, familyID string)) *RefreshTokenRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokenFamily_Call) Return(_a0 error) *RefreshTokenRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokenFamily_Call) RunAndReturn(run func(context.Context, string) error) *RefreshTokenRepository_RevokeRefreshTokenFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRefreshTokensByUser provides a mock function with given fields: ctx, userID
+func (_m *RefreshTokenRepository) RevokeRefreshTokensByUser(ctx context.Context, userID uint) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRefreshTokensByUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RefreshTokenRepository_RevokeRefreshTokensByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRefreshTokensByUser'
+type RefreshTokenRepository_RevokeRefreshTokensByUser_Call struct {
+	*mock.Call
+}
+
+// RevokeRefreshTokensByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *RefreshTokenRepository_Expecter) RevokeRefreshTokensByUser(ctx interface{}, userID interface{}) *RefreshTokenRepository_RevokeRefreshTokensByUser_Call {
+	return &RefreshTokenRepository_RevokeRefreshTokensByUser_Call{Call: _e.mock.On("RevokeRefreshTokensByUser", ctx, userID)}
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokensByUser_Call) Run(run func(ctx context.Context, userID uint)) *RefreshTokenRepository_RevokeRefreshTokensByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokensByUser_Call) Return(_a0 error) *RefreshTokenRepository_RevokeRefreshTokensByUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RefreshTokenRepository_RevokeRefreshTokensByUser_Call) RunAndReturn(run func(context.Context, uint) error) *RefreshTokenRepository_RevokeRefreshTokensByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRefreshTokenRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RefreshTokenRepository {
+	mock := &RefreshTokenRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,251 @@
+// Code generated by mockery v2.44.1. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RevocationStore is an autogenerated mock type for the RevocationStore type
+type RevocationStore struct {
+	mock.Mock
+}
+
+type RevocationStore_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *RevocationStore) EXPECT() *RevocationStore_Expecter {
+	return &RevocationStore_Expecter{mock: &_m.Mock}
+}
+
+// DeleteExpiredRevocations provides a mock function with given fields: ctx, now
+func (_m *RevocationStore) DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error) {
+	ret := _m.Called(ctx, now)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteExpiredRevocations")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, now)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, now)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, now)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevocationStore_DeleteExpiredRevocations_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteExpiredRevocations'
+type RevocationStore_DeleteExpiredRevocations_Call struct {
+	*mock.Call
+}
+
+// DeleteExpiredRevocations is a helper method to define mock.On call
+//   - ctx context.Context
+//   - now time.Time
+func (_e *RevocationStore_Expecter) DeleteExpiredRevocations(ctx interface{}, now interface{}) *RevocationStore_DeleteExpiredRevocations_Call {
+	return &RevocationStore_DeleteExpiredRevocations_Call{Call: _e.mock.On("DeleteExpiredRevocations", ctx, now)}
+}
+
+func (_c *RevocationStore_DeleteExpiredRevocations_Call) Run(run func(ctx context.Context, now time.Time)) *RevocationStore_DeleteExpiredRevocations_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RevocationStore_DeleteExpiredRevocations_Call) Return(_a0 int64, _a1 error) *RevocationStore_DeleteExpiredRevocations_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RevocationStore_DeleteExpiredRevocations_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *RevocationStore_DeleteExpiredRevocations_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, jti, userID, issuedAt
+func (_m *RevocationStore) IsTokenRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, jti, userID, issuedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, time.Time) (bool, error)); ok {
+		return rf(ctx, jti, userID, issuedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, time.Time) bool); ok {
+		r0 = rf(ctx, jti, userID, issuedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uint, time.Time) error); ok {
+		r1 = rf(ctx, jti, userID, issuedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevocationStore_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type RevocationStore_IsTokenRevoked_Call struct {
+	*mock.Call
+}
+
+// IsTokenRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - userID uint
+//   - issuedAt time.Time
+func (_e *RevocationStore_Expecter) IsTokenRevoked(ctx interface{}, jti interface{}, userID interface{}, issuedAt interface{}) *RevocationStore_IsTokenRevoked_Call {
+	return &RevocationStore_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", ctx, jti, userID, issuedAt)}
+}
+
+func (_c *RevocationStore_IsTokenRevoked_Call) Run(run func(ctx context.Context, jti string, userID uint, issuedAt time.Time)) *RevocationStore_IsTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RevocationStore_IsTokenRevoked_Call) Return(_a0 bool, _a1 error) *RevocationStore_IsTokenRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *RevocationStore_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string, uint, time.Time) (bool, error)) *RevocationStore_IsTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function with given fields: ctx, jti, userID, expiresAt
+func (_m *RevocationStore) RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error {
+	ret := _m.Called(ctx, jti, userID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uint, time.Time) error); ok {
+		r0 = rf(ctx, jti, userID, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevocationStore_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type RevocationStore_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - userID uint
+//   - expiresAt time.Time
+func (_e *RevocationStore_Expecter) RevokeToken(ctx interface{}, jti interface{}, userID interface{}, expiresAt interface{}) *RevocationStore_RevokeToken_Call {
+	return &RevocationStore_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, jti, userID, expiresAt)}
+}
+
+func (_c *RevocationStore_RevokeToken_Call) Run(run func(ctx context.Context, jti string, userID uint, expiresAt time.Time)) *RevocationStore_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uint), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RevocationStore_RevokeToken_Call) Return(_a0 error) *RevocationStore_RevokeToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RevocationStore_RevokeToken_Call) RunAndReturn(run func(context.Context, string, uint, time.Time) error) *RevocationStore_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserTokens provides a mock function with given fields: ctx, userID, issuedBefore, expiresAt
+func (_m *RevocationStore) RevokeUserTokens(ctx context.Context, userID uint, issuedBefore time.Time, expiresAt time.Time) error {
+	ret := _m.Called(ctx, userID, issuedBefore, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint, time.Time, time.Time) error); ok {
+		r0 = rf(ctx, userID, issuedBefore, expiresAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RevocationStore_RevokeUserTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserTokens'
+type RevocationStore_RevokeUserTokens_Call struct {
+	*mock.Call
+}
+
+// RevokeUserTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+//   - issuedBefore time.Time
+//   - expiresAt time.Time
+func (_e *RevocationStore_Expecter) RevokeUserTokens(ctx interface{}, userID interface{}, issuedBefore interface{}, expiresAt interface{}) *RevocationStore_RevokeUserTokens_Call {
+	return &RevocationStore_RevokeUserTokens_Call{Call: _e.mock.On("RevokeUserTokens", ctx, userID, issuedBefore, expiresAt)}
+}
+
+func (_c *RevocationStore_RevokeUserTokens_Call) Run(run func(ctx context.Context, userID uint, issuedBefore time.Time, expiresAt time.Time)) *RevocationStore_RevokeUserTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint), args[2].(time.Time), args[3].(time.Time))
+	})
+	return _c
+}
+
+func (_c *RevocationStore_RevokeUserTokens_Call) Return(_a0 error) *RevocationStore_RevokeUserTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *RevocationStore_RevokeUserTokens_Call) RunAndReturn(run func(context.Context, uint, time.Time, time.Time) error) *RevocationStore_RevokeUserTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewRevocationStore creates a new instance of RevocationStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRevocationStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RevocationStore {
+	mock := &RevocationStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	context "context"
+
 	mock "github.com/stretchr/testify/mock"
 	domain "github.com/tat-101/bb-assignment-back/domain"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -20,17 +24,64 @@ func (_m *UserRepository) EXPECT() *UserRepository_Expecter {
 	return &UserRepository_Expecter{mock: &_m.Mock}
 }
 
-// CreateUser provides a mock function with given fields: _a0
-func (_m *UserRepository) CreateUser(_a0 *domain.User) error {
-	ret := _m.Called(_a0)
+// ChangeUserStatus provides a mock function with given fields: ctx, change
+func (_m *UserRepository) ChangeUserStatus(ctx context.Context, change *domain.UserStatusChange) error {
+	ret := _m.Called(ctx, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeUserStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.UserStatusChange) error); ok {
+		r0 = rf(ctx, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserRepository_ChangeUserStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ChangeUserStatus'
+type UserRepository_ChangeUserStatus_Call struct {
+	*mock.Call
+}
+
+// ChangeUserStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - change *domain.UserStatusChange
+func (_e *UserRepository_Expecter) ChangeUserStatus(ctx interface{}, change interface{}) *UserRepository_ChangeUserStatus_Call {
+	return &UserRepository_ChangeUserStatus_Call{Call: _e.mock.On("ChangeUserStatus", ctx, change)}
+}
+
+func (_c *UserRepository_ChangeUserStatus_Call) Run(run func(ctx context.Context, change *domain.UserStatusChange)) *UserRepository_ChangeUserStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*domain.UserStatusChange))
+	})
+	return _c
+}
+
+func (_c *UserRepository_ChangeUserStatus_Call) Return(_a0 error) *UserRepository_ChangeUserStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserRepository_ChangeUserStatus_Call) RunAndReturn(run func(context.Context, *domain.UserStatusChange) error) *UserRepository_ChangeUserStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateUser provides a mock function with given fields: ctx, _a1
+func (_m *UserRepository) CreateUser(ctx context.Context, _a1 *domain.User) error {
+	ret := _m.Called(ctx, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for CreateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*domain.User) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User) error); ok {
+		r0 = rf(ctx, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -44,14 +95,15 @@ type UserRepository_CreateUser_Call struct {
 }
 
 // CreateUser is a helper method to define mock.On call
-//   - _a0 *domain.User
-func (_e *UserRepository_Expecter) CreateUser(_a0 interface{}) *UserRepository_CreateUser_Call {
-	return &UserRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", _a0)}
+//   - ctx context.Context
+//   - _a1 *domain.User
+func (_e *UserRepository_Expecter) CreateUser(ctx interface{}, _a1 interface{}) *UserRepository_CreateUser_Call {
+	return &UserRepository_CreateUser_Call{Call: _e.mock.On("CreateUser", ctx, _a1)}
 }
 
-func (_c *UserRepository_CreateUser_Call) Run(run func(_a0 *domain.User)) *UserRepository_CreateUser_Call {
+func (_c *UserRepository_CreateUser_Call) Run(run func(ctx context.Context, _a1 *domain.User)) *UserRepository_CreateUser_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(*domain.User))
+		run(args[0].(context.Context), args[1].(*domain.User))
 	})
 	return _c
 }
@@ -61,22 +113,22 @@ func (_c *UserRepository_CreateUser_Call) Return(_a0 error) *UserRepository_Crea
 	return _c
 }
 
-func (_c *UserRepository_CreateUser_Call) RunAndReturn(run func(*domain.User) error) *UserRepository_CreateUser_Call {
+func (_c *UserRepository_CreateUser_Call) RunAndReturn(run func(context.Context, *domain.User) error) *UserRepository_CreateUser_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// DeleteUserByID provides a mock function with given fields: id
-func (_m *UserRepository) DeleteUserByID(id string) error {
-	ret := _m.Called(id)
+// DeleteUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepository) DeleteUserByID(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for DeleteUserByID")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(string) error); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -90,14 +142,15 @@ type UserRepository_DeleteUserByID_Call struct {
 }
 
 // DeleteUserByID is a helper method to define mock.On call
-//   - id string
-func (_e *UserRepository_Expecter) DeleteUserByID(id interface{}) *UserRepository_DeleteUserByID_Call {
-	return &UserRepository_DeleteUserByID_Call{Call: _e.mock.On("DeleteUserByID", id)}
+//   - ctx context.Context
+//   - id uint
+func (_e *UserRepository_Expecter) DeleteUserByID(ctx interface{}, id interface{}) *UserRepository_DeleteUserByID_Call {
+	return &UserRepository_DeleteUserByID_Call{Call: _e.mock.On("DeleteUserByID", ctx, id)}
 }
 
-func (_c *UserRepository_DeleteUserByID_Call) Run(run func(id string)) *UserRepository_DeleteUserByID_Call {
+func (_c *UserRepository_DeleteUserByID_Call) Run(run func(ctx context.Context, id uint)) *UserRepository_DeleteUserByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -107,34 +160,34 @@ func (_c *UserRepository_DeleteUserByID_Call) Return(_a0 error) *UserRepository_
 	return _c
 }
 
-func (_c *UserRepository_DeleteUserByID_Call) RunAndReturn(run func(string) error) *UserRepository_DeleteUserByID_Call {
+func (_c *UserRepository_DeleteUserByID_Call) RunAndReturn(run func(context.Context, uint) error) *UserRepository_DeleteUserByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetAllUsers provides a mock function with given fields:
-func (_m *UserRepository) GetAllUsers() ([]domain.User, error) {
-	ret := _m.Called()
+// GetAllUsers provides a mock function with given fields: ctx, query
+func (_m *UserRepository) GetAllUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error) {
+	ret := _m.Called(ctx, query)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAllUsers")
 	}
 
-	var r0 []domain.User
+	var r0 *domain.UserPage
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]domain.User, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) (*domain.UserPage, error)); ok {
+		return rf(ctx, query)
 	}
-	if rf, ok := ret.Get(0).(func() []domain.User); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, domain.UserQuery) *domain.UserPage); ok {
+		r0 = rf(ctx, query)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.User)
+			r0 = ret.Get(0).(*domain.UserPage)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, domain.UserQuery) error); ok {
+		r1 = rf(ctx, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -148,30 +201,32 @@ type UserRepository_GetAllUsers_Call struct {
 }
 
 // GetAllUsers is a helper method to define mock.On call
-func (_e *UserRepository_Expecter) GetAllUsers() *UserRepository_GetAllUsers_Call {
-	return &UserRepository_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers")}
+//   - ctx context.Context
+//   - query domain.UserQuery
+func (_e *UserRepository_Expecter) GetAllUsers(ctx interface{}, query interface{}) *UserRepository_GetAllUsers_Call {
+	return &UserRepository_GetAllUsers_Call{Call: _e.mock.On("GetAllUsers", ctx, query)}
 }
 
-func (_c *UserRepository_GetAllUsers_Call) Run(run func()) *UserRepository_GetAllUsers_Call {
+func (_c *UserRepository_GetAllUsers_Call) Run(run func(ctx context.Context, query domain.UserQuery)) *UserRepository_GetAllUsers_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run()
+		run(args[0].(context.Context), args[1].(domain.UserQuery))
 	})
 	return _c
 }
 
-func (_c *UserRepository_GetAllUsers_Call) Return(_a0 []domain.User, _a1 error) *UserRepository_GetAllUsers_Call {
+func (_c *UserRepository_GetAllUsers_Call) Return(_a0 *domain.UserPage, _a1 error) *UserRepository_GetAllUsers_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *UserRepository_GetAllUsers_Call) RunAndReturn(run func() ([]domain.User, error)) *UserRepository_GetAllUsers_Call {
+func (_c *UserRepository_GetAllUsers_Call) RunAndReturn(run func(context.Context, domain.UserQuery) (*domain.UserPage, error)) *UserRepository_GetAllUsers_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByEmail provides a mock function with given fields: email
-func (_m *UserRepository) GetUserByEmail(email string) (*domain.User, error) {
-	ret := _m.Called(email)
+// GetUserByEmail provides a mock function with given fields: ctx, email
+func (_m *UserRepository) GetUserByEmail(ctx context.Context, email string) (*domain.User, error) {
+	ret := _m.Called(ctx, email)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByEmail")
@@ -179,19 +234,19 @@ func (_m *UserRepository) GetUserByEmail(email string) (*domain.User, error) {
 
 	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*domain.User, error)); ok {
-		return rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*domain.User, error)); ok {
+		return rf(ctx, email)
 	}
-	if rf, ok := ret.Get(0).(func(string) *domain.User); ok {
-		r0 = rf(email)
+	if rf, ok := ret.Get(0).(func(context.Context, string) *domain.User); ok {
+		r0 = rf(ctx, email)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(email)
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -205,14 +260,15 @@ type UserRepository_GetUserByEmail_Call struct {
 }
 
 // GetUserByEmail is a helper method to define mock.On call
+//   - ctx context.Context
 //   - email string
-func (_e *UserRepository_Expecter) GetUserByEmail(email interface{}) *UserRepository_GetUserByEmail_Call {
-	return &UserRepository_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", email)}
+func (_e *UserRepository_Expecter) GetUserByEmail(ctx interface{}, email interface{}) *UserRepository_GetUserByEmail_Call {
+	return &UserRepository_GetUserByEmail_Call{Call: _e.mock.On("GetUserByEmail", ctx, email)}
 }
 
-func (_c *UserRepository_GetUserByEmail_Call) Run(run func(email string)) *UserRepository_GetUserByEmail_Call {
+func (_c *UserRepository_GetUserByEmail_Call) Run(run func(ctx context.Context, email string)) *UserRepository_GetUserByEmail_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string))
+		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
@@ -222,14 +278,14 @@ func (_c *UserRepository_GetUserByEmail_Call) Return(_a0 *domain.User, _a1 error
 	return _c
 }
 
-func (_c *UserRepository_GetUserByEmail_Call) RunAndReturn(run func(string) (*domain.User, error)) *UserRepository_GetUserByEmail_Call {
+func (_c *UserRepository_GetUserByEmail_Call) RunAndReturn(run func(context.Context, string) (*domain.User, error)) *UserRepository_GetUserByEmail_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetUserByID provides a mock function with given fields: id
-func (_m *UserRepository) GetUserByID(id uint) (*domain.User, error) {
-	ret := _m.Called(id)
+// GetUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepository) GetUserByID(ctx context.Context, id uint) (*domain.User, error) {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetUserByID")
@@ -237,19 +293,19 @@ func (_m *UserRepository) GetUserByID(id uint) (*domain.User, error) {
 
 	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(uint) (*domain.User, error)); ok {
-		return rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) (*domain.User, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(uint) *domain.User); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(context.Context, uint) *domain.User); ok {
+		r0 = rf(ctx, id)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(uint) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -263,14 +319,15 @@ type UserRepository_GetUserByID_Call struct {
 }
 
 // GetUserByID is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id uint
-func (_e *UserRepository_Expecter) GetUserByID(id interface{}) *UserRepository_GetUserByID_Call {
-	return &UserRepository_GetUserByID_Call{Call: _e.mock.On("GetUserByID", id)}
+func (_e *UserRepository_Expecter) GetUserByID(ctx interface{}, id interface{}) *UserRepository_GetUserByID_Call {
+	return &UserRepository_GetUserByID_Call{Call: _e.mock.On("GetUserByID", ctx, id)}
 }
 
-func (_c *UserRepository_GetUserByID_Call) Run(run func(id uint)) *UserRepository_GetUserByID_Call {
+func (_c *UserRepository_GetUserByID_Call) Run(run func(ctx context.Context, id uint)) *UserRepository_GetUserByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(uint))
+		run(args[0].(context.Context), args[1].(uint))
 	})
 	return _c
 }
@@ -280,14 +337,224 @@ func (_c *UserRepository_GetUserByID_Call) Return(_a0 *domain.User, _a1 error) *
 	return _c
 }
 
-func (_c *UserRepository_GetUserByID_Call) RunAndReturn(run func(uint) (*domain.User, error)) *UserRepository_GetUserByID_Call {
+func (_c *UserRepository_GetUserByID_Call) RunAndReturn(run func(context.Context, uint) (*domain.User, error)) *UserRepository_GetUserByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserStatusHistory provides a mock function with given fields: ctx, userID
+func (_m *UserRepository) GetUserStatusHistory(ctx context.Context, userID uint) ([]domain.UserStatusChange, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserStatusHistory")
+	}
+
+	var r0 []domain.UserStatusChange
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) ([]domain.UserStatusChange, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint) []domain.UserStatusChange); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.UserStatusChange)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserRepository_GetUserStatusHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStatusHistory'
+type UserRepository_GetUserStatusHistory_Call struct {
+	*mock.Call
+}
+
+// GetUserStatusHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uint
+func (_e *UserRepository_Expecter) GetUserStatusHistory(ctx interface{}, userID interface{}) *UserRepository_GetUserStatusHistory_Call {
+	return &UserRepository_GetUserStatusHistory_Call{Call: _e.mock.On("GetUserStatusHistory", ctx, userID)}
+}
+
+func (_c *UserRepository_GetUserStatusHistory_Call) Run(run func(ctx context.Context, userID uint)) *UserRepository_GetUserStatusHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserRepository_GetUserStatusHistory_Call) Return(_a0 []domain.UserStatusChange, _a1 error) *UserRepository_GetUserStatusHistory_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserRepository_GetUserStatusHistory_Call) RunAndReturn(run func(context.Context, uint) ([]domain.UserStatusChange, error)) *UserRepository_GetUserStatusHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkEmailVerified provides a mock function with given fields: ctx, id
+func (_m *UserRepository) MarkEmailVerified(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserRepository_MarkEmailVerified_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkEmailVerified'
+type UserRepository_MarkEmailVerified_Call struct {
+	*mock.Call
+}
+
+// MarkEmailVerified is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserRepository_Expecter) MarkEmailVerified(ctx interface{}, id interface{}) *UserRepository_MarkEmailVerified_Call {
+	return &UserRepository_MarkEmailVerified_Call{Call: _e.mock.On("MarkEmailVerified", ctx, id)}
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) Run(run func(ctx context.Context, id uint)) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) Return(_a0 error) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserRepository_MarkEmailVerified_Call) RunAndReturn(run func(context.Context, uint) error) *UserRepository_MarkEmailVerified_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeDeletedUsers provides a mock function with given fields: ctx, deletedBefore
+func (_m *UserRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(ctx, deletedBefore)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedUsers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) (int64, error)); ok {
+		return rf(ctx, deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) int64); ok {
+		r0 = rf(ctx, deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UserRepository_PurgeDeletedUsers_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeDeletedUsers'
+type UserRepository_PurgeDeletedUsers_Call struct {
+	*mock.Call
+}
+
+// PurgeDeletedUsers is a helper method to define mock.On call
+//   - ctx context.Context
+//   - deletedBefore time.Time
+func (_e *UserRepository_Expecter) PurgeDeletedUsers(ctx interface{}, deletedBefore interface{}) *UserRepository_PurgeDeletedUsers_Call {
+	return &UserRepository_PurgeDeletedUsers_Call{Call: _e.mock.On("PurgeDeletedUsers", ctx, deletedBefore)}
+}
+
+func (_c *UserRepository_PurgeDeletedUsers_Call) Run(run func(ctx context.Context, deletedBefore time.Time)) *UserRepository_PurgeDeletedUsers_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time))
+	})
+	return _c
+}
+
+func (_c *UserRepository_PurgeDeletedUsers_Call) Return(_a0 int64, _a1 error) *UserRepository_PurgeDeletedUsers_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *UserRepository_PurgeDeletedUsers_Call) RunAndReturn(run func(context.Context, time.Time) (int64, error)) *UserRepository_PurgeDeletedUsers_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreUserByID provides a mock function with given fields: ctx, id
+func (_m *UserRepository) RestoreUserByID(ctx context.Context, id uint) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreUserByID")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UserRepository_RestoreUserByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreUserByID'
+type UserRepository_RestoreUserByID_Call struct {
+	*mock.Call
+}
+
+// RestoreUserByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uint
+func (_e *UserRepository_Expecter) RestoreUserByID(ctx interface{}, id interface{}) *UserRepository_RestoreUserByID_Call {
+	return &UserRepository_RestoreUserByID_Call{Call: _e.mock.On("RestoreUserByID", ctx, id)}
+}
+
+func (_c *UserRepository_RestoreUserByID_Call) Run(run func(ctx context.Context, id uint)) *UserRepository_RestoreUserByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uint))
+	})
+	return _c
+}
+
+func (_c *UserRepository_RestoreUserByID_Call) Return(_a0 error) *UserRepository_RestoreUserByID_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *UserRepository_RestoreUserByID_Call) RunAndReturn(run func(context.Context, uint) error) *UserRepository_RestoreUserByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateUserByID provides a mock function with given fields: id, updatedUser
-func (_m *UserRepository) UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error) {
-	ret := _m.Called(id, updatedUser)
+// UpdateUserByID provides a mock function with given fields: ctx, id, updatedUser
+func (_m *UserRepository) UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (*domain.User, error) {
+	ret := _m.Called(ctx, id, updatedUser)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateUserByID")
@@ -295,19 +562,19 @@ func (_m *UserRepository) UpdateUserByID(id string, updatedUser domain.User) (*d
 
 	var r0 *domain.User
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string, domain.User) (*domain.User, error)); ok {
-		return rf(id, updatedUser)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) (*domain.User, error)); ok {
+		return rf(ctx, id, updatedUser)
 	}
-	if rf, ok := ret.Get(0).(func(string, domain.User) *domain.User); ok {
-		r0 = rf(id, updatedUser)
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.User) *domain.User); ok {
+		r0 = rf(ctx, id, updatedUser)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.User)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, domain.User) error); ok {
-		r1 = rf(id, updatedUser)
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.User) error); ok {
+		r1 = rf(ctx, id, updatedUser)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -321,15 +588,16 @@ type UserRepository_UpdateUserByID_Call struct {
 }
 
 // UpdateUserByID is a helper method to define mock.On call
+//   - ctx context.Context
 //   - id string
 //   - updatedUser domain.User
-func (_e *UserRepository_Expecter) UpdateUserByID(id interface{}, updatedUser interface{}) *UserRepository_UpdateUserByID_Call {
-	return &UserRepository_UpdateUserByID_Call{Call: _e.mock.On("UpdateUserByID", id, updatedUser)}
+func (_e *UserRepository_Expecter) UpdateUserByID(ctx interface{}, id interface{}, updatedUser interface{}) *UserRepository_UpdateUserByID_Call {
+	return &UserRepository_UpdateUserByID_Call{Call: _e.mock.On("UpdateUserByID", ctx, id, updatedUser)}
 }
 
-func (_c *UserRepository_UpdateUserByID_Call) Run(run func(id string, updatedUser domain.User)) *UserRepository_UpdateUserByID_Call {
+func (_c *UserRepository_UpdateUserByID_Call) Run(run func(ctx context.Context, id string, updatedUser domain.User)) *UserRepository_UpdateUserByID_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(string), args[1].(domain.User))
+		run(args[0].(context.Context), args[1].(string), args[2].(domain.User))
 	})
 	return _c
 }
@@ -339,7 +607,7 @@ func (_c *UserRepository_UpdateUserByID_Call) Return(_a0 *domain.User, _a1 error
 	return _c
 }
 
-func (_c *UserRepository_UpdateUserByID_Call) RunAndReturn(run func(string, domain.User) (*domain.User, error)) *UserRepository_UpdateUserByID_Call {
+func (_c *UserRepository_UpdateUserByID_Call) RunAndReturn(run func(context.Context, string, domain.User) (*domain.User, error)) *UserRepository_UpdateUserByID_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,241 @@
+package user
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OIDCProvider is an OpenID Connect provider users can sign in with.
+//
+//go:generate mockery --name OIDCProvider
+type OIDCProvider interface {
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*domain.OIDCIdentity, error)
+}
+
+//go:generate mockery --name IdentityRepository
+type IdentityRepository interface {
+	// GetIdentity returns domain.ErrNotFound when the provider account is
+	// not linked to a user.
+	GetIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error
+}
+
+const DefaultOIDCLoginTTL = 10 * time.Minute
+
+var ErrOIDCLoginFailed = fmt.Errorf("%w: single sign-on failed", domain.ErrUnauthorized)
+
+// OIDCConfig controls sign-in with OpenID Connect providers.
+type OIDCConfig struct {
+	// Secret signs the login tokens that carry state, nonce and PKCE code
+	// verifier from the start of a login to its callback.
+	Secret   []byte
+	LoginTTL time.Duration
+}
+
+// WithOIDC enables sign-in with the providers, keyed by the name used in
+// their URLs.
+func WithOIDC(providers map[string]OIDCProvider, repo IdentityRepository, cfg OIDCConfig) Option {
+	return func(s *Service) {
+		cfg.LoginTTL = tools.Coalesce(cfg.LoginTTL, DefaultOIDCLoginTTL)
+		s.oidcProviders = providers
+		s.identities = repo
+		s.oidc = cfg
+	}
+}
+
+// BeginOIDCLogin returns where to send the user to sign in at provider, and
+// the login token CompleteOIDCLogin needs to accept their return.
+func (s *Service) BeginOIDCLogin(ctx context.Context, provider string) (login *domain.OIDCLogin, err error) {
+	ctx, span := startSpan(ctx, "BeginOIDCLogin", attribute.String("oidc.provider", provider))
+	defer func() { endSpan(span, err) }()
+
+	p, ok := s.oidcProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown provider %q", domain.ErrNotFound, provider)
+	}
+
+	state, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	// 32 random bytes make a PKCE code verifier of the minimum length.
+	verifier, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		return nil, err
+	}
+	loginToken, err := tools.SignToken(s.oidc.Secret, oidcLoginPurpose(provider),
+		strings.Join([]string{state, nonce, verifier}, "."), s.oidc.LoginTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.OIDCLogin{AuthURL: authURL, LoginToken: loginToken}, nil
+}
+
+// CompleteOIDCLogin finishes a login at provider: it checks that state is the
+// one issued with loginToken, redeems code and signs in the user the ID token
+// names, with the same checks and MFA challenge as a password login. Unknown
+// users are linked to the account with their email address if the provider
+// verified it and the account is not protected by MFA or a privileged role,
+// and created otherwise.
+func (s *Service) CompleteOIDCLogin(ctx context.Context, provider, code, state, loginToken string) (tokens *domain.AuthTokens, err error) {
+	ctx, span := startSpan(ctx, "CompleteOIDCLogin", attribute.String("oidc.provider", provider))
+	defer func() { endSpan(span, err) }()
+
+	p, ok := s.oidcProviders[provider]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown provider %q", domain.ErrNotFound, provider)
+	}
+
+	subject, err := tools.VerifySignedToken(s.oidc.Secret, oidcLoginPurpose(provider), loginToken)
+	parts := strings.Split(subject, ".")
+	if err != nil || len(parts) != 3 || subtle.ConstantTimeCompare([]byte(parts[0]), []byte(state)) != 1 {
+		s.observeLogin(OutcomeInvalidCredentials)
+		return nil, ErrOIDCLoginFailed
+	}
+	nonce, verifier := parts[1], parts[2]
+
+	identity, err := p.Exchange(ctx, code, verifier, nonce)
+	if err != nil {
+		s.observeLogin(OutcomeInvalidCredentials)
+		slog.WarnContext(ctx, "Single sign-on failed", "provider", provider, "error", err)
+		return nil, ErrOIDCLoginFailed
+	}
+
+	user, err := s.oidcUser(ctx, identity)
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int64("user.id", int64(user.ID)))
+
+	return s.signIn(ctx, user)
+}
+
+// oidcUser returns the user identity is linked to, linking or creating one
+// on first sign-in.
+func (s *Service) oidcUser(ctx context.Context, identity *domain.OIDCIdentity) (*domain.User, error) {
+	link, err := s.identities.GetIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.userRepo.GetUserByID(ctx, link.UserID)
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+	if identity.Email == "" {
+		return nil, fmt.Errorf("%w: the provider did not share an email address", domain.ErrBadParamInput)
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, identity.Email)
+	switch {
+	case err == nil && !identity.EmailVerified:
+		// Anyone can claim an address at some providers; linking on an
+		// unverified one would hand them the existing account.
+		return nil, fmt.Errorf("%w: an account with this email address already exists", domain.ErrConflict)
+	case err == nil:
+		if err := s.checkAutoLink(ctx, user); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		if user, err = s.createOIDCUser(ctx, identity); err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.identities.CreateIdentity(ctx, &domain.UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkAutoLink refuses to link a provider account to user by email address
+// alone when user is worth more than the provider account: a provider that
+// is compromised, or lax about who gets an address, must not become a way
+// around their MFA or into a privileged account. Nor is an account linked
+// before its address was verified, as whoever registered it may not own the
+// address and would keep signing in with their password.
+func (s *Service) checkAutoLink(ctx context.Context, user *domain.User) error {
+	if !user.IsVerified() || user.Status == domain.UserStatusPending {
+		return fmt.Errorf("%w: an account with this email address is waiting for it to be verified", domain.ErrConflict)
+	}
+	if user.IsPrivileged() || s.mfaRequired(user) {
+		return fmt.Errorf("%w: sign in with your password to use this account", domain.ErrConflict)
+	}
+	if s.mfaRepo == nil {
+		return nil
+	}
+	mfa, err := s.mfaRepo.GetMFA(ctx, user.ID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return nil
+	case err != nil:
+		return err
+	case mfa.IsEnabled():
+		return fmt.Errorf("%w: sign in with your password to use this account", domain.ErrConflict)
+	}
+	return nil
+}
+
+func (s *Service) createOIDCUser(ctx context.Context, identity *domain.OIDCIdentity) (*domain.User, error) {
+	// The user signs in through the provider; nobody knows this password.
+	password, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	user := &domain.User{
+		Name:     tools.Coalesce(identity.Name, identity.Email),
+		Email:    identity.Email,
+		Password: password,
+		// The provider vouches for them.
+		Status: domain.UserStatusActive,
+	}
+	if identity.EmailVerified {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	} else {
+		// Like a registration, the account waits for the address to be
+		// verified.
+		user.Status = domain.UserStatusPending
+	}
+	if err := user.HashPassword(); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return nil, err
+	}
+	s.recordUser(ctx, domain.AuditUserCreate, user.ID, nil, user)
+	if !user.IsVerified() {
+		if err := s.sendVerification(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "Failed to send verification email", "user_id", user.ID, "error", err)
+		}
+	}
+	return user, nil
+}
+
+func oidcLoginPurpose(provider string) string {
+	return "oidc-login:" + provider
+}
@@ -0,0 +1,251 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/oidc"
+	"github.com/tat-101/bb-assignment-back/oidc/oidctest"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func newOIDCService(t *testing.T, repo *mocks.UserRepository, identities *mocks.IdentityRepository, opts ...user.Option) (*user.Service, *oidctest.Issuer) {
+	t.Helper()
+	issuer := oidctest.NewIssuer("client", "secret")
+	t.Cleanup(issuer.Close)
+
+	provider := oidc.NewProvider(oidc.Config{
+		Name:         "corp",
+		Issuer:       issuer.URL,
+		ClientID:     issuer.ClientID,
+		ClientSecret: issuer.ClientSecret,
+		RedirectURL:  "http://localhost:3000/auth/oidc/corp/callback",
+	})
+	opts = append(opts, user.WithOIDC(map[string]user.OIDCProvider{"corp": provider}, identities, user.OIDCConfig{
+		Secret: []byte("oidc-secret"),
+	}))
+	return user.NewService(repo, opts...), issuer
+}
+
+// signIn runs a login at the issuer and returns the code and state it
+// redirects back with, and the login token.
+func signIn(t *testing.T, service *user.Service, issuer *oidctest.Issuer) (code, state, loginToken string) {
+	t.Helper()
+	login, err := service.BeginOIDCLogin(context.Background(), "corp")
+	require.NoError(t, err)
+	code, state, err = issuer.Authorize(login.AuthURL)
+	require.NoError(t, err)
+	return code, state, login.LoginToken
+}
+
+func TestService_OIDCLogin_LinkedUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(&domain.UserIdentity{UserID: 7}, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7, Email: "user@example.com", EmailVerifiedAt: verifiedAt()}, nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	tokens, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	mockIdentities.AssertNotCalled(t, "CreateIdentity", mock.Anything, mock.Anything)
+}
+
+func TestService_OIDCLogin_ChecksLikePasswordLogin(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	mockMFA := new(mocks.MFARepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities, user.WithMFA(mockMFA, user.MFAConfig{Secret: []byte("mfa-secret")}))
+
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(&domain.UserIdentity{UserID: 7}, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7, Email: "user@example.com", EmailVerifiedAt: verifiedAt()}, nil).Once()
+	mockMFA.On("GetMFA", mock.Anything, uint(7)).Return(&domain.MFA{UserID: 7, EnabledAt: verifiedAt()}, nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	tokens, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken, "the MFA code is still needed")
+	assert.NotEmpty(t, tokens.MFAToken)
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7, Email: "user@example.com", EmailVerifiedAt: verifiedAt(), Status: domain.UserStatusSuspended}, nil).Once()
+
+	code, state, loginToken = signIn(t, service, issuer)
+	_, err = service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	assert.ErrorIs(t, err, domain.ErrAccountInactive)
+}
+
+func TestService_OIDCLogin_CreatesUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+
+	var created *domain.User
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(nil, domain.ErrNotFound)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("CreateUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*domain.User)
+		created.ID = 9
+	}).Return(nil)
+	mockIdentities.On("CreateIdentity", mock.Anything, &domain.UserIdentity{
+		UserID: 9, Provider: "corp", Subject: "subject-1", Email: "user@example.com",
+	}).Return(nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	tokens, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.Equal(t, "Test User", created.Name)
+	assert.True(t, created.IsVerified())
+	mockIdentities.AssertExpectations(t)
+}
+
+func TestService_OIDCLogin_CreatesUnverifiedUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+	issuer.SetUser(oidctest.User{Subject: "subject-2", Email: "new@example.com", EmailVerified: false})
+
+	var created *domain.User
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-2").Return(nil, domain.ErrNotFound)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, domain.ErrNotFound)
+	mockUserRepo.On("CreateUser", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*domain.User)
+	}).Return(nil)
+	mockIdentities.On("CreateIdentity", mock.Anything, mock.Anything).Return(nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+	assert.Equal(t, domain.UserStatusPending, created.Status)
+}
+
+func TestService_OIDCLogin_LinksVerifiedEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(nil, domain.ErrNotFound)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 3, Email: "user@example.com", EmailVerifiedAt: verifiedAt()}, nil)
+	mockIdentities.On("CreateIdentity", mock.Anything, mock.MatchedBy(func(identity *domain.UserIdentity) bool {
+		return identity.UserID == 3
+	})).Return(nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	require.NoError(t, err)
+	mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+	mockIdentities.AssertExpectations(t)
+}
+
+func TestService_OIDCLogin_DoesNotLinkProtectedAccounts(t *testing.T) {
+	for name, tc := range map[string]struct {
+		user *domain.User
+		mfa  *domain.MFA
+	}{
+		"admin role":  {user: &domain.User{ID: 3, Roles: []domain.Role{{Name: "user"}, {Name: "admin"}}}},
+		"MFA enabled": {user: &domain.User{ID: 3}, mfa: &domain.MFA{UserID: 3, EnabledAt: verifiedAt()}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepository)
+			mockIdentities := new(mocks.IdentityRepository)
+			mockMFA := new(mocks.MFARepository)
+			service, issuer := newOIDCService(t, mockUserRepo, mockIdentities, user.WithMFA(mockMFA, user.MFAConfig{Secret: []byte("mfa-secret")}))
+
+			tc.user.Email = "user@example.com"
+			tc.user.EmailVerifiedAt = verifiedAt()
+			mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(nil, domain.ErrNotFound)
+			mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(tc.user, nil)
+			if tc.mfa != nil {
+				mockMFA.On("GetMFA", mock.Anything, uint(3)).Return(tc.mfa, nil)
+			} else {
+				mockMFA.On("GetMFA", mock.Anything, uint(3)).Return(nil, domain.ErrNotFound)
+			}
+
+			code, state, loginToken := signIn(t, service, issuer)
+			_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+			assert.ErrorIs(t, err, domain.ErrConflict)
+			mockIdentities.AssertNotCalled(t, "CreateIdentity", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_OIDCLogin_DoesNotLinkUnverifiedAccounts(t *testing.T) {
+	for name, registered := range map[string]*domain.User{
+		"email not verified": {ID: 3, Email: "user@example.com", Password: "attacker's", Status: domain.UserStatusPending},
+		"pending":            {ID: 3, Email: "user@example.com", Password: "attacker's", Status: domain.UserStatusPending, EmailVerifiedAt: verifiedAt()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepository)
+			mockIdentities := new(mocks.IdentityRepository)
+			service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+
+			mockIdentities.On("GetIdentity", mock.Anything, "corp", "subject-1").Return(nil, domain.ErrNotFound)
+			mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(registered, nil)
+
+			code, state, loginToken := signIn(t, service, issuer)
+			_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+			assert.ErrorIs(t, err, domain.ErrConflict)
+			mockIdentities.AssertNotCalled(t, "CreateIdentity", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_OIDCLogin_UnverifiedEmailOfExistingUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+	issuer.SetUser(oidctest.User{Subject: "attacker", Email: "user@example.com", EmailVerified: false})
+
+	mockIdentities.On("GetIdentity", mock.Anything, "corp", "attacker").Return(nil, domain.ErrNotFound)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 3, Email: "user@example.com"}, nil)
+
+	code, state, loginToken := signIn(t, service, issuer)
+	_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, state, loginToken)
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockIdentities.AssertNotCalled(t, "CreateIdentity", mock.Anything, mock.Anything)
+}
+
+func TestService_OIDCLogin_StateMismatch(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockIdentities := new(mocks.IdentityRepository)
+	service, issuer := newOIDCService(t, mockUserRepo, mockIdentities)
+
+	code, _, _ := signIn(t, service, issuer)
+	// A login token from another browser must not complete this login.
+	_, otherState, otherToken := signIn(t, service, issuer)
+
+	_, err := service.CompleteOIDCLogin(context.Background(), "corp", code, "forged", otherToken)
+	assert.ErrorIs(t, err, user.ErrOIDCLoginFailed)
+
+	_, err = service.CompleteOIDCLogin(context.Background(), "corp", code, otherState, "forged")
+	assert.ErrorIs(t, err, user.ErrOIDCLoginFailed)
+
+	_, err = service.CompleteOIDCLogin(context.Background(), "corp", code, otherState, otherToken)
+	assert.ErrorIs(t, err, user.ErrOIDCLoginFailed, "the code was issued for another PKCE verifier")
+	mockIdentities.AssertNotCalled(t, "GetIdentity", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_OIDCLogin_UnknownProvider(t *testing.T) {
+	service, _ := newOIDCService(t, new(mocks.UserRepository), new(mocks.IdentityRepository))
+
+	_, err := service.BeginOIDCLogin(context.Background(), "other")
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+}
@@ -0,0 +1,146 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/mailer"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const DefaultPasswordResetTTL = 30 * time.Minute
+
+// passwordResetSendTimeout bounds sending a reset link after ForgotPassword
+// has returned.
+const passwordResetSendTimeout = time.Minute
+
+var errInvalidResetToken = fmt.Errorf("%w: invalid or expired reset token", domain.ErrBadParamInput)
+
+// PasswordResetConfig controls the forgot-password flow.
+type PasswordResetConfig struct {
+	// URL is the page the link in the email points to. The token is added
+	// as the "token" query parameter.
+	URL string
+	TTL time.Duration
+}
+
+// WithPasswordReset enables resetting forgotten passwords with single-use
+// tokens stored in repo and sent through m.
+func WithPasswordReset(repo PasswordResetTokenRepository, m mailer.Mailer, cfg PasswordResetConfig) Option {
+	return func(s *Service) {
+		cfg.TTL = tools.Coalesce(cfg.TTL, DefaultPasswordResetTTL)
+		s.passwordResets = repo
+		s.mailer = m
+		s.passwordReset = cfg
+	}
+}
+
+// ForgotPassword emails a password reset link if email belongs to an
+// account. It does not reveal whether that is the case, so failures are
+// logged rather than returned, and the link is sent in the background: the
+// time it takes would otherwise tell known addresses apart. Wait blocks until
+// it has been sent.
+func (s *Service) ForgotPassword(ctx context.Context, email string) (err error) {
+	ctx, span := startSpan(ctx, "ForgotPassword")
+	defer func() { endSpan(span, err) }()
+
+	if s.passwordResets == nil {
+		return fmt.Errorf("%w: password reset is disabled", domain.ErrForbidden)
+	}
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+	s.background.Add(1)
+	go func() {
+		defer s.background.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), passwordResetSendTimeout)
+		defer cancel()
+		if err := s.sendPasswordReset(ctx, user); err != nil {
+			slog.ErrorContext(ctx, "Failed to send password reset email", "user_id", user.ID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the password reset links ForgotPassword is sending in
+// the background have been sent, or until ctx is done.
+func (s *Service) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.background.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ResetPassword sets a new password for the user a reset token was issued
+// to. The token, and any other outstanding token of the user, can not be
+// used again, and the user's existing sessions are revoked.
+func (s *Service) ResetPassword(ctx context.Context, token, password string) (err error) {
+	ctx, span := startSpan(ctx, "ResetPassword")
+	defer func() { endSpan(span, err) }()
+
+	if s.passwordResets == nil {
+		return fmt.Errorf("%w: password reset is disabled", domain.ErrForbidden)
+	}
+	stored, err := s.passwordResets.GetPasswordResetTokenByHash(ctx, tools.HashToken(token))
+	if err != nil || stored.UsedAt != nil || time.Now().After(stored.ExpiresAt) {
+		return errInvalidResetToken
+	}
+	span.SetAttributes(attribute.Int64("user.id", int64(stored.UserID)))
+
+	ok, err := s.passwordResets.MarkPasswordResetTokenUsed(ctx, stored.ID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errInvalidResetToken
+	}
+
+	id := strconv.FormatUint(uint64(stored.UserID), 10)
+	if _, err := s.userRepo.UpdateUserByID(ctx, id, domain.User{Password: password}); err != nil {
+		return err
+	}
+	if err := s.passwordResets.InvalidatePasswordResetTokens(ctx, stored.UserID); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditPasswordReset, stored.UserID, nil, nil)
+	return s.signOutEverywhere(ctx, stored.UserID)
+}
+
+func (s *Service) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	token, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return err
+	}
+	err = s.passwordResets.CreatePasswordResetToken(ctx, &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: tools.HashToken(token),
+		ExpiresAt: time.Now().Add(s.passwordReset.TTL),
+	})
+	if err != nil {
+		return err
+	}
+	link, err := withToken(s.passwordReset.URL, token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. "+
+			"If it was you, choose a new password within %s using this link:\n%s\n\n"+
+			"Otherwise you can ignore this email.\n", user.Name, s.passwordReset.TTL, link),
+	})
+}
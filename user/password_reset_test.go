@@ -0,0 +1,170 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/mailer"
+	mailermocks "github.com/tat-101/bb-assignment-back/mailer/mocks"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func newPasswordResetService(repo *mocks.UserRepository, resets *mocks.PasswordResetTokenRepository, m mailer.Mailer, opts ...user.Option) *user.Service {
+	opts = append(opts, user.WithPasswordReset(resets, m, user.PasswordResetConfig{
+		URL: "https://app.example.com/reset-password",
+	}))
+	return user.NewService(repo, opts...)
+}
+
+func TestService_ForgotPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockResetRepo := new(mocks.PasswordResetTokenRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newPasswordResetService(mockUserRepo, mockResetRepo, mockMailer)
+
+	var stored *domain.PasswordResetToken
+	var sent mailer.Message
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	mockResetRepo.On("CreatePasswordResetToken", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(*domain.PasswordResetToken)
+	}).Return(nil)
+	mockMailer.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(mailer.Message)
+	}).Return(nil)
+
+	err := service.ForgotPassword(context.Background(), "user@example.com")
+
+	require.NoError(t, err)
+	require.NoError(t, service.Wait(context.Background()))
+	token := tokenFrom(t, sent)
+	assert.Equal(t, uint(7), stored.UserID)
+	assert.Equal(t, tools.HashToken(token), stored.TokenHash, "only the hash of the token is stored")
+	assert.WithinDuration(t, time.Now().Add(user.DefaultPasswordResetTTL), stored.ExpiresAt, time.Minute)
+	assert.Equal(t, "user@example.com", sent.To)
+}
+
+func TestService_ForgotPassword_DoesNotRevealAccounts(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockResetRepo := new(mocks.PasswordResetTokenRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newPasswordResetService(mockUserRepo, mockResetRepo, mockMailer)
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "unknown@example.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	mockResetRepo.On("CreatePasswordResetToken", mock.Anything, mock.Anything).Return(nil)
+	mockMailer.On("Send", mock.Anything, mock.Anything).Return(errors.New("smtp down"))
+
+	assert.NoError(t, service.ForgotPassword(context.Background(), "unknown@example.com"))
+	assert.NoError(t, service.ForgotPassword(context.Background(), "user@example.com"))
+	require.NoError(t, service.Wait(context.Background()))
+	mockMailer.AssertNumberOfCalls(t, "Send", 1)
+}
+
+func TestService_ForgotPassword_SendsInBackground(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockResetRepo := new(mocks.PasswordResetTokenRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newPasswordResetService(mockUserRepo, mockResetRepo, mockMailer)
+
+	release := make(chan time.Time)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	mockResetRepo.On("CreatePasswordResetToken", mock.Anything, mock.Anything).Return(nil)
+	mockMailer.On("Send", mock.Anything, mock.Anything).WaitUntil(release).Return(nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, service.ForgotPassword(ctx, "user@example.com"))
+	cancel()
+
+	waitCtx, stop := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stop()
+	assert.ErrorIs(t, service.Wait(waitCtx), context.DeadlineExceeded, "the response does not wait for the mail")
+
+	release <- time.Now()
+	require.NoError(t, service.Wait(context.Background()))
+	mockMailer.AssertExpectations(t)
+}
+
+func TestService_ForgotPassword_Disabled(t *testing.T) {
+	service := user.NewService(new(mocks.UserRepository))
+
+	err := service.ForgotPassword(context.Background(), "user@example.com")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+}
+
+func TestService_ResetPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockResetRepo := new(mocks.PasswordResetTokenRepository)
+	mockRefreshRepo := new(mocks.RefreshTokenRepository)
+	mockStore := new(mocks.RevocationStore)
+	service := newPasswordResetService(mockUserRepo, mockResetRepo, new(mailermocks.Mailer),
+		user.WithRefreshTokens(mockRefreshRepo, time.Hour),
+		user.WithRevocationStore(mockStore),
+	)
+
+	stored := &domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: time.Now().Add(time.Minute)}
+	mockResetRepo.On("GetPasswordResetTokenByHash", mock.Anything, tools.HashToken("reset-token")).Return(stored, nil)
+	mockResetRepo.On("MarkPasswordResetTokenUsed", mock.Anything, uint(3)).Return(true, nil)
+	mockUserRepo.On("UpdateUserByID", mock.Anything, "7", domain.User{Password: "new-password"}).Return(&domain.User{ID: 7}, nil)
+	mockResetRepo.On("InvalidatePasswordResetTokens", mock.Anything, uint(7)).Return(nil)
+	mockStore.On("RevokeUserTokens", mock.Anything, uint(7), mock.Anything, mock.Anything).Return(nil)
+	mockRefreshRepo.On("RevokeRefreshTokensByUser", mock.Anything, uint(7)).Return(nil)
+
+	err := service.ResetPassword(context.Background(), "reset-token", "new-password")
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockResetRepo.AssertExpectations(t)
+	mockStore.AssertExpectations(t)
+	mockRefreshRepo.AssertExpectations(t)
+}
+
+func TestService_ResetPassword_InvalidToken(t *testing.T) {
+	usedAt := time.Now()
+	tests := []struct {
+		name   string
+		stored *domain.PasswordResetToken
+		err    error
+	}{
+		{"unknown", nil, errors.New("record not found")},
+		{"expired", &domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: time.Now().Add(-time.Minute)}, nil},
+		{"used", &domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: time.Now().Add(time.Minute), UsedAt: &usedAt}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepository)
+			mockResetRepo := new(mocks.PasswordResetTokenRepository)
+			service := newPasswordResetService(mockUserRepo, mockResetRepo, new(mailermocks.Mailer))
+
+			mockResetRepo.On("GetPasswordResetTokenByHash", mock.Anything, mock.Anything).Return(tt.stored, tt.err)
+
+			err := service.ResetPassword(context.Background(), "reset-token", "new-password")
+
+			assert.ErrorIs(t, err, domain.ErrBadParamInput)
+			mockUserRepo.AssertNotCalled(t, "UpdateUserByID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestService_ResetPassword_ConcurrentUse(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockResetRepo := new(mocks.PasswordResetTokenRepository)
+	service := newPasswordResetService(mockUserRepo, mockResetRepo, new(mailermocks.Mailer))
+
+	stored := &domain.PasswordResetToken{ID: 3, UserID: 7, ExpiresAt: time.Now().Add(time.Minute)}
+	mockResetRepo.On("GetPasswordResetTokenByHash", mock.Anything, mock.Anything).Return(stored, nil)
+	mockResetRepo.On("MarkPasswordResetTokenUsed", mock.Anything, uint(3)).Return(false, nil)
+
+	err := service.ResetPassword(context.Background(), "reset-token", "new-password")
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	mockUserRepo.AssertNotCalled(t, "UpdateUserByID", mock.Anything, mock.Anything, mock.Anything)
+}
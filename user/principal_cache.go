@@ -0,0 +1,72 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+)
+
+// PrincipalCache holds users that stateful token validation would otherwise
+// load from the database on every request. Failing caches only cost the
+// database lookups they would have saved.
+//
+//go:generate mockery --name PrincipalCache
+type PrincipalCache interface {
+	Get(ctx context.Context, id uint) (*domain.User, bool, error)
+	Set(ctx context.Context, user *domain.User) error
+	Delete(ctx context.Context, id uint) error
+}
+
+// Results of principal cache lookups reported to Metrics.
+const (
+	CacheHit  = "hit"
+	CacheMiss = "miss"
+)
+
+// WithPrincipalCache caches the users ValidateToken loads. Changes made
+// through the service evict the user; changes made elsewhere show once the
+// entry expires.
+func WithPrincipalCache(cache PrincipalCache) Option {
+	return func(s *Service) {
+		s.principals = cache
+	}
+}
+
+// principalUser loads the user an access token names, from the cache when
+// possible.
+func (s *Service) principalUser(ctx context.Context, id uint) (*domain.User, error) {
+	if s.principals == nil {
+		return s.userRepo.GetUserByID(ctx, id)
+	}
+
+	user, ok, err := s.principals.Get(ctx, id)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to read principal cache", "error", err)
+	}
+	if ok {
+		s.observePrincipalCache(CacheHit)
+		return user, nil
+	}
+	s.observePrincipalCache(CacheMiss)
+
+	user, err = s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.principals.Set(ctx, user); err != nil {
+		slog.WarnContext(ctx, "Failed to write principal cache", "error", err)
+	}
+	return user, nil
+}
+
+// InvalidatePrincipal evicts a user whose account, roles or permissions
+// changed from the principal cache.
+func (s *Service) InvalidatePrincipal(ctx context.Context, id uint) {
+	if s.principals == nil {
+		return
+	}
+	if err := s.principals.Delete(ctx, id); err != nil {
+		slog.ErrorContext(ctx, "Failed to invalidate principal cache", "user_id", id, "error", err)
+	}
+}
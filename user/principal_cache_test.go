@@ -0,0 +1,77 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/cache"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func TestService_ValidateToken_PrincipalCache(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMetrics := new(mocks.Metrics)
+	service := user.NewService(mockUserRepo,
+		user.WithKeyRing(testKeys),
+		user.WithMetrics(mockMetrics),
+		user.WithPrincipalCache(cache.NewLRU(10, time.Minute)),
+	)
+	token := accessToken(t, testKeys, 7, "user@example.com")
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7, Email: "user@example.com"}, nil).Once()
+	mockMetrics.On("ObserveTokenValidation", user.OutcomeSuccess)
+	mockMetrics.On("ObservePrincipalCache", user.CacheMiss).Once()
+	mockMetrics.On("ObservePrincipalCache", user.CacheHit).Twice()
+
+	for range 3 {
+		principal, err := service.ValidateToken(context.Background(), token)
+		require.NoError(t, err)
+		assert.Equal(t, uint(7), principal.User.ID)
+	}
+
+	mockUserRepo.AssertExpectations(t)
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestService_PrincipalCache_Invalidation(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockCache := new(mocks.PrincipalCache)
+	service := user.NewService(mockUserRepo, user.WithPrincipalCache(mockCache))
+
+	mockUserRepo.On("UpdateUserByID", mock.Anything, "7", mock.Anything).Return(&domain.User{ID: 7}, nil)
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(8)).Return(nil)
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(9)).Return(errors.New("db down"))
+	mockCache.On("Delete", mock.Anything, uint(7)).Return(nil).Once()
+	mockCache.On("Delete", mock.Anything, uint(8)).Return(errors.New("redis down")).Once()
+
+	_, err := service.UpdateUserByID(context.Background(), "7", domain.User{Name: "New Name"})
+	assert.NoError(t, err)
+	assert.NoError(t, service.DeleteUserByID(context.Background(), "8"), "a failed eviction does not fail the delete")
+	assert.Error(t, service.DeleteUserByID(context.Background(), "9"))
+
+	mockCache.AssertExpectations(t)
+	mockCache.AssertNotCalled(t, "Delete", mock.Anything, uint(9))
+}
+
+func TestService_PrincipalCache_Unavailable(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockCache := new(mocks.PrincipalCache)
+	service := user.NewService(mockUserRepo, user.WithKeyRing(testKeys), user.WithPrincipalCache(mockCache))
+	token := accessToken(t, testKeys, 7, "user@example.com")
+
+	mockCache.On("Get", mock.Anything, uint(7)).Return(nil, false, errors.New("redis down"))
+	mockCache.On("Set", mock.Anything, mock.Anything).Return(errors.New("redis down"))
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7}, nil)
+
+	principal, err := service.ValidateToken(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), principal.UserID)
+}
@@ -0,0 +1,47 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword sets a new password for the user once their current one
+// checks out, and signs them out everywhere. A wrong current password counts
+// as a failed login, so it can not be guessed faster than a login.
+func (s *Service) ChangePassword(ctx context.Context, userID uint, currentPassword, newPassword string) (err error) {
+	ctx, span := startSpan(ctx, "ChangePassword", attribute.Int64("user.id", int64(userID)))
+	defer func() { endSpan(span, err) }()
+
+	if newPassword == "" {
+		return fmt.Errorf("%w: the new password is empty", domain.ErrBadParamInput)
+	}
+	user, err := s.userRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	keys := s.loginKeys(ctx, user.Email)
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		return err
+	}
+
+	_, compareSpan := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword))
+	compareSpan.End()
+	if err != nil {
+		s.recordLoginFailure(ctx, keys)
+		return fmt.Errorf("%w: the current password is wrong", domain.ErrBadParamInput)
+	}
+	s.resetLoginAttempts(ctx, keys)
+
+	id := strconv.FormatUint(uint64(userID), 10)
+	if _, err := s.userRepo.UpdateUserByID(ctx, id, domain.User{Password: newPassword}); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditPasswordChange, userID, nil, nil)
+	return s.signOutEverywhere(ctx, userID)
+}
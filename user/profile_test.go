@@ -0,0 +1,50 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/internal/repository"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func TestService_ChangePassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRefreshTokens := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockRefreshTokens, 0))
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("UpdateUserByID", mock.Anything, "7", domain.User{Password: "new-secret"}).Return(&domain.User{ID: 7}, nil)
+	mockRefreshTokens.On("RevokeRefreshTokensByUser", mock.Anything, uint(7)).Return(nil)
+
+	err := service.ChangePassword(context.Background(), 7, "password123", "new-secret")
+
+	require.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+	mockRefreshTokens.AssertExpectations(t)
+}
+
+func TestService_ChangePassword_WrongPassword(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	attempts := repository.NewMemoryLoginAttemptRepository()
+	service := user.NewService(mockUserRepo, user.WithLoginLockout(attempts, testLockout))
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+
+	for range 3 {
+		err := service.ChangePassword(context.Background(), 7, "wrong", "new-secret")
+		assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	}
+	// Past the free attempts even the right password has to wait.
+	err := service.ChangePassword(context.Background(), 7, "password123", "new-secret")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests)
+
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+	assert.ErrorIs(t, err, domain.ErrTooManyRequests, "the failures count against logins too")
+	mockUserRepo.AssertNotCalled(t, "UpdateUserByID", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/mailer"
+	"github.com/tat-101/bb-assignment-back/tools"
+)
+
+// Registration modes.
+const (
+	RegistrationOpen       = "open"
+	RegistrationInviteOnly = "invite"
+	RegistrationDisabled   = "disabled"
+)
+
+const (
+	DefaultVerificationTTL = 24 * time.Hour
+	DefaultInvitationTTL   = 7 * 24 * time.Hour
+
+	verifyEmailPurpose = "verify-email"
+	invitationPurpose  = "invitation"
+)
+
+// RegistrationConfig controls self-service registration.
+type RegistrationConfig struct {
+	// Mode is RegistrationOpen, RegistrationInviteOnly or RegistrationDisabled.
+	Mode string
+	// Secret signs verification and invitation tokens.
+	Secret []byte
+	// VerifyURL and InviteURL are the pages the links in emails point to.
+	// The token is added as the "token" query parameter.
+	VerifyURL       string
+	InviteURL       string
+	VerificationTTL time.Duration
+	InvitationTTL   time.Duration
+}
+
+// WithRegistration enables self-service registration, sending verification
+// and invitation links through m.
+func WithRegistration(m mailer.Mailer, cfg RegistrationConfig) Option {
+	return func(s *Service) {
+		cfg.VerificationTTL = tools.Coalesce(cfg.VerificationTTL, DefaultVerificationTTL)
+		cfg.InvitationTTL = tools.Coalesce(cfg.InvitationTTL, DefaultInvitationTTL)
+		s.mailer = m
+		s.registration = cfg
+	}
+}
+
+// Register creates an unverified account and emails the user a link to
+// verify it. In invite-only mode invitation must be a token from Invite for
+// the same email address.
+func (s *Service) Register(ctx context.Context, user *domain.User, invitation string) (err error) {
+	ctx, span := startSpan(ctx, "Register")
+	defer func() { endSpan(span, err) }()
+
+	switch s.registration.Mode {
+	case RegistrationOpen:
+	case RegistrationInviteOnly:
+		email, err := tools.VerifySignedToken(s.registration.Secret, invitationPurpose, invitation)
+		if err != nil || !strings.EqualFold(email, user.Email) {
+			return fmt.Errorf("%w: a valid invitation is required", domain.ErrForbidden)
+		}
+	default:
+		return fmt.Errorf("%w: registration is disabled", domain.ErrForbidden)
+	}
+
+	if existing, err := s.userRepo.GetUserByEmail(ctx, user.Email); err == nil && existing != nil {
+		return fmt.Errorf("%w: email is already registered", domain.ErrConflict)
+	}
+
+	user.ID = 0
+	// The repository gives new users the default role.
+	user.Roles = nil
+	user.Status = domain.UserStatusPending
+	user.EmailVerifiedAt = nil
+	if err := user.HashPassword(); err != nil {
+		return err
+	}
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditUserRegister, user.ID, nil, user)
+
+	// The account exists either way; a lost email can be sent again.
+	if err := s.sendVerification(ctx, user); err != nil {
+		slog.ErrorContext(ctx, "Failed to send verification email", "user_id", user.ID, "error", err)
+	}
+	return nil
+}
+
+// VerifyEmail marks the account a verification token was issued for as
+// verified. Verifying twice is not an error.
+func (s *Service) VerifyEmail(ctx context.Context, token string) (err error) {
+	ctx, span := startSpan(ctx, "VerifyEmail")
+	defer func() { endSpan(span, err) }()
+
+	email, err := tools.VerifySignedToken(s.registration.Secret, verifyEmailPurpose, token)
+	if err != nil {
+		return fmt.Errorf("%w: invalid or expired verification token", domain.ErrBadParamInput)
+	}
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("%w: invalid or expired verification token", domain.ErrBadParamInput)
+	}
+	if user.IsVerified() {
+		return nil
+	}
+	if err := s.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditUserVerifyEmail, user.ID, nil, nil)
+	if user.AccountStatus() == domain.UserStatusPending {
+		return s.changeStatus(ctx, user, domain.UserStatusActive, "Email address verified")
+	}
+	return nil
+}
+
+// ResendVerification sends a new verification link if email belongs to an
+// unverified account. It does not reveal whether that is the case.
+func (s *Service) ResendVerification(ctx context.Context, email string) (err error) {
+	ctx, span := startSpan(ctx, "ResendVerification")
+	defer func() { endSpan(span, err) }()
+
+	if s.mailer == nil {
+		return fmt.Errorf("%w: registration is disabled", domain.ErrForbidden)
+	}
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil || user.IsVerified() {
+		return nil
+	}
+	return s.sendVerification(ctx, user)
+}
+
+// Invite emails an invitation to register to email.
+func (s *Service) Invite(ctx context.Context, email string) (err error) {
+	ctx, span := startSpan(ctx, "Invite")
+	defer func() { endSpan(span, err) }()
+
+	if s.mailer == nil || s.registration.Mode == RegistrationDisabled {
+		return fmt.Errorf("%w: registration is disabled", domain.ErrForbidden)
+	}
+	if _, err := s.userRepo.GetUserByEmail(ctx, email); err == nil {
+		return fmt.Errorf("%w: email is already registered", domain.ErrConflict)
+	}
+
+	token, err := tools.SignToken(s.registration.Secret, invitationPurpose, email, s.registration.InvitationTTL)
+	if err != nil {
+		return err
+	}
+	link, err := withToken(s.registration.InviteURL, token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      email,
+		Subject: "You have been invited",
+		Body: fmt.Sprintf("You have been invited to create an account.\n\n"+
+			"Register within %s using this link:\n%s\n", s.registration.InvitationTTL, link),
+	})
+}
+
+func (s *Service) sendVerification(ctx context.Context, user *domain.User) error {
+	if s.mailer == nil {
+		return errors.New("no mailer configured")
+	}
+	token, err := tools.SignToken(s.registration.Secret, verifyEmailPurpose, user.Email, s.registration.VerificationTTL)
+	if err != nil {
+		return err
+	}
+	link, err := withToken(s.registration.VerifyURL, token)
+	if err != nil {
+		return err
+	}
+	return s.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease verify your email address within %s using this link:\n%s\n",
+			user.Name, s.registration.VerificationTTL, link),
+	})
+}
+
+func withToken(base, token string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("token", token)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
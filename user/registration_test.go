@@ -0,0 +1,196 @@
+package user_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/mailer"
+	mailermocks "github.com/tat-101/bb-assignment-back/mailer/mocks"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+var registrationSecret = []byte("registration-secret")
+
+func newRegistrationService(repo *mocks.UserRepository, m mailer.Mailer, mode string) *user.Service {
+	return user.NewService(repo, user.WithRegistration(m, user.RegistrationConfig{
+		Mode:      mode,
+		Secret:    registrationSecret,
+		VerifyURL: "https://app.example.com/verify-email",
+		InviteURL: "https://app.example.com/register",
+	}))
+}
+
+// tokenFrom extracts the token query parameter from the link in a message.
+func tokenFrom(t *testing.T, msg mailer.Message) string {
+	t.Helper()
+	start := strings.Index(msg.Body, "https://")
+	require.NotEqual(t, -1, start, "no link in %q", msg.Body)
+	link, err := url.Parse(strings.Fields(msg.Body[start:])[0])
+	require.NoError(t, err)
+	return link.Query().Get("token")
+}
+
+func TestService_Register_Open(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newRegistrationService(mockUserRepo, mockMailer, user.RegistrationOpen)
+
+	var sent mailer.Message
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("CreateUser", mock.Anything, mock.AnythingOfType("*domain.User")).Return(nil)
+	mockMailer.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(mailer.Message)
+	}).Return(nil)
+
+	newUser := &domain.User{Name: "New", Email: "new@example.com", Password: "secret1", Roles: []domain.Role{{Name: "admin"}}}
+	err := service.Register(context.Background(), newUser, "")
+
+	assert.NoError(t, err)
+	assert.Empty(t, newUser.Roles, "the repository assigns the default role")
+	assert.False(t, newUser.IsVerified())
+	assert.NotEqual(t, "secret1", newUser.Password)
+	assert.Equal(t, "new@example.com", sent.To)
+	assert.NotEmpty(t, tokenFrom(t, sent))
+	mockUserRepo.AssertExpectations(t)
+	mockMailer.AssertExpectations(t)
+}
+
+func TestService_Register_DuplicateEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := newRegistrationService(mockUserRepo, new(mailermocks.Mailer), user.RegistrationOpen)
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "taken@example.com").Return(&domain.User{ID: 3}, nil)
+
+	err := service.Register(context.Background(), &domain.User{Email: "taken@example.com", Password: "secret1"}, "")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestService_Register_Disabled(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	err := service.Register(context.Background(), &domain.User{Email: "new@example.com", Password: "secret1"}, "")
+
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+	mockUserRepo.AssertNotCalled(t, "CreateUser", mock.Anything, mock.Anything)
+}
+
+func TestService_Register_InviteOnly(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newRegistrationService(mockUserRepo, mockMailer, user.RegistrationInviteOnly)
+
+	var invitation mailer.Message
+	mockUserRepo.On("GetUserByEmail", mock.Anything, mock.Anything).Return(nil, errors.New("record not found"))
+	mockUserRepo.On("CreateUser", mock.Anything, mock.Anything).Return(nil)
+	mockMailer.On("Send", mock.Anything, mock.MatchedBy(func(msg mailer.Message) bool {
+		return msg.Subject == "You have been invited"
+	})).Run(func(args mock.Arguments) {
+		invitation = args.Get(1).(mailer.Message)
+	}).Return(nil).Once()
+	mockMailer.On("Send", mock.Anything, mock.Anything).Return(nil)
+
+	require.NoError(t, service.Invite(context.Background(), "invited@example.com"))
+	token := tokenFrom(t, invitation)
+
+	err := service.Register(context.Background(), &domain.User{Email: "new@example.com", Password: "secret1"}, "")
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	err = service.Register(context.Background(), &domain.User{Email: "other@example.com", Password: "secret1"}, token)
+	assert.ErrorIs(t, err, domain.ErrForbidden)
+
+	err = service.Register(context.Background(), &domain.User{Email: "Invited@example.com", Password: "secret1"}, token)
+	assert.NoError(t, err)
+	mockUserRepo.AssertNumberOfCalls(t, "CreateUser", 1)
+}
+
+func TestService_Invite_ExistingUser(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newRegistrationService(mockUserRepo, mockMailer, user.RegistrationInviteOnly)
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "taken@example.com").Return(&domain.User{ID: 3}, nil)
+
+	err := service.Invite(context.Background(), "taken@example.com")
+
+	assert.ErrorIs(t, err, domain.ErrConflict)
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestService_VerifyEmail(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newRegistrationService(mockUserRepo, mockMailer, user.RegistrationOpen)
+
+	var sent mailer.Message
+	unverified := &domain.User{ID: 4, Email: "new@example.com", Status: domain.UserStatusPending}
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(unverified, nil)
+	mockUserRepo.On("MarkEmailVerified", mock.Anything, uint(4)).Return(nil).Once()
+	mockUserRepo.On("ChangeUserStatus", mock.Anything, mock.MatchedBy(func(change *domain.UserStatusChange) bool {
+		return change.UserID == 4 && change.FromStatus == domain.UserStatusPending && change.ToStatus == domain.UserStatusActive
+	})).Return(nil).Once()
+	mockMailer.On("Send", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		sent = args.Get(1).(mailer.Message)
+	}).Return(nil)
+
+	require.NoError(t, service.ResendVerification(context.Background(), "new@example.com"))
+	token := tokenFrom(t, sent)
+
+	assert.NoError(t, service.VerifyEmail(context.Background(), token))
+	assert.Equal(t, domain.UserStatusActive, unverified.Status)
+
+	unverified.EmailVerifiedAt = verifiedAt()
+	assert.NoError(t, service.VerifyEmail(context.Background(), token), "verifying twice is not an error")
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestService_VerifyEmail_InvalidToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := newRegistrationService(mockUserRepo, new(mailermocks.Mailer), user.RegistrationOpen)
+
+	err := service.VerifyEmail(context.Background(), "not-a-token")
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	mockUserRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+}
+
+func TestService_ResendVerification_DoesNotRevealAccounts(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMailer := new(mailermocks.Mailer)
+	service := newRegistrationService(mockUserRepo, mockMailer, user.RegistrationOpen)
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "unknown@example.com").Return(nil, errors.New("record not found"))
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "verified@example.com").
+		Return(&domain.User{ID: 5, Email: "verified@example.com", EmailVerifiedAt: verifiedAt()}, nil)
+
+	assert.NoError(t, service.ResendVerification(context.Background(), "unknown@example.com"))
+	assert.NoError(t, service.ResendVerification(context.Background(), "verified@example.com"))
+	mockMailer.AssertNotCalled(t, "Send", mock.Anything, mock.Anything)
+}
+
+func TestService_AuthenticateUser_Unverified(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMetrics := new(mocks.Metrics)
+	service := user.NewService(mockUserRepo, user.WithMetrics(mockMetrics))
+
+	newUser := &domain.User{Email: "new@example.com", Password: "password123"}
+	require.NoError(t, newUser.HashPassword())
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "new@example.com").Return(newUser, nil)
+	mockMetrics.On("ObserveLogin", user.OutcomeUnverified).Once()
+
+	tokens, err := service.AuthenticateUser(context.Background(), "new@example.com", "password123")
+
+	assert.ErrorIs(t, err, domain.ErrEmailNotVerified)
+	assert.Nil(t, tokens)
+	mockMetrics.AssertExpectations(t)
+}
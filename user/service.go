@@ -1,92 +1,419 @@
 package user
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/mailer"
 	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/bcrypt"
 )
 
 //go:generate mockery --name UserRepository
 type UserRepository interface {
-	CreateUser(user *domain.User) error
-	// TODO: improve should have skip limit
-	GetAllUsers() ([]domain.User, error)
-	GetUserByID(id uint) (*domain.User, error)
-	GetUserByEmail(email string) (*domain.User, error)
-	UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error)
-	DeleteUserByID(id string) error
+	CreateUser(ctx context.Context, user *domain.User) error
+	GetAllUsers(ctx context.Context, query domain.UserQuery) (*domain.UserPage, error)
+	GetUserByID(ctx context.Context, id uint) (*domain.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*domain.User, error)
+	UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (*domain.User, error)
+	// DeleteUserByID soft deletes the user, returning domain.ErrNotFound
+	// when there is no such user.
+	DeleteUserByID(ctx context.Context, id uint) error
+	RestoreUserByID(ctx context.Context, id uint) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error)
+	MarkEmailVerified(ctx context.Context, id uint) error
+	// ChangeUserStatus returns domain.ErrConflict when the user's status is
+	// no longer change.FromStatus.
+	ChangeUserStatus(ctx context.Context, change *domain.UserStatusChange) error
+	GetUserStatusHistory(ctx context.Context, userID uint) ([]domain.UserStatusChange, error)
 }
 
+//go:generate mockery --name RefreshTokenRepository
+type RefreshTokenRepository interface {
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*domain.RefreshToken, error)
+	// MarkRefreshTokenUsed reports false when the token was already used.
+	MarkRefreshTokenUsed(ctx context.Context, id uint) (bool, error)
+	RevokeRefreshTokenFamily(ctx context.Context, familyID string) error
+	RevokeRefreshTokensByUser(ctx context.Context, userID uint) error
+}
+
+//go:generate mockery --name RevocationStore
+type RevocationStore interface {
+	RevokeToken(ctx context.Context, jti string, userID uint, expiresAt time.Time) error
+	RevokeUserTokens(ctx context.Context, userID uint, issuedBefore, expiresAt time.Time) error
+	IsTokenRevoked(ctx context.Context, jti string, userID uint, issuedAt time.Time) (bool, error)
+	DeleteExpiredRevocations(ctx context.Context, now time.Time) (int64, error)
+}
+
+//go:generate mockery --name PasswordResetTokenRepository
+type PasswordResetTokenRepository interface {
+	CreatePasswordResetToken(ctx context.Context, token *domain.PasswordResetToken) error
+	GetPasswordResetTokenByHash(ctx context.Context, hash string) (*domain.PasswordResetToken, error)
+	// MarkPasswordResetTokenUsed reports false when the token was already used.
+	MarkPasswordResetTokenUsed(ctx context.Context, id uint) (bool, error)
+	InvalidatePasswordResetTokens(ctx context.Context, userID uint) error
+}
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+
+	// TokenValidationStateful loads the user and checks revocations for
+	// every token; TokenValidationStateless trusts the claims of any token
+	// that is signed and unexpired.
+	TokenValidationStateful  = "stateful"
+	TokenValidationStateless = "stateless"
+
+	DefaultAccessTokenTTL  = 15 * time.Minute
+	DefaultRefreshTokenTTL = 30 * 24 * time.Hour
+)
+
 type Service struct {
-	userRepo UserRepository
+	userRepo         UserRepository
+	refreshTokenRepo RefreshTokenRepository
+	revocations      RevocationStore
+	accessTokenTTL   time.Duration
+	refreshTokenTTL  time.Duration
+	metrics          Metrics
+	mailer           mailer.Mailer
+	registration     RegistrationConfig
+	passwordResets   PasswordResetTokenRepository
+	passwordReset    PasswordResetConfig
+	mfaRepo          MFARepository
+	mfa              MFAConfig
+	oidcProviders    map[string]OIDCProvider
+	identities       IdentityRepository
+	oidc             OIDCConfig
+	keys             *tools.KeyRing
+	tokenValidation  string
+	principals       PrincipalCache
+	loginAttempts    LoginAttemptStore
+	lockout          LockoutConfig
+	auditor          Auditor
+	deletedRetention time.Duration
+	// background tracks mail sent after the request has been answered.
+	background sync.WaitGroup
 }
 
-func NewService(u UserRepository) *Service {
-	return &Service{
-		userRepo: u,
+// Option configures optional Service dependencies.
+type Option func(*Service)
+
+// WithRefreshTokens enables refresh token issuing and rotation.
+func WithRefreshTokens(repo RefreshTokenRepository, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.refreshTokenRepo = repo
+		s.refreshTokenTTL = tools.Coalesce(ttl, DefaultRefreshTokenTTL)
+	}
+}
+
+// WithRevocationStore makes ValidateToken reject revoked tokens and enables
+// logout and session revocation.
+func WithRevocationStore(store RevocationStore) Option {
+	return func(s *Service) {
+		s.revocations = store
+	}
+}
+
+// WithAccessTokenTTL sets how long issued access tokens are valid.
+func WithAccessTokenTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.accessTokenTTL = tools.Coalesce(ttl, DefaultAccessTokenTTL)
 	}
 }
 
-func (s *Service) GetAllUsers() ([]domain.User, error) {
-	return s.userRepo.GetAllUsers()
+// WithKeyRing sets the keys access tokens are signed and verified with.
+// Without it the service signs with a key of its own that is lost on exit.
+func WithKeyRing(keys *tools.KeyRing) Option {
+	return func(s *Service) {
+		s.keys = keys
+	}
 }
 
-// CreateUser creates a new user in the repository
-func (s *Service) CreateUser(user *domain.User) error {
+// WithTokenValidation sets whether ValidateToken is stateful, the default,
+// or stateless.
+func WithTokenValidation(mode string) Option {
+	return func(s *Service) {
+		s.tokenValidation = tools.Coalesce(mode, TokenValidationStateful)
+	}
+}
+
+func NewService(u UserRepository, opts ...Option) *Service {
+	s := &Service{
+		userRepo:         u,
+		accessTokenTTL:   DefaultAccessTokenTTL,
+		tokenValidation:  TokenValidationStateful,
+		refreshTokenTTL:  DefaultRefreshTokenTTL,
+		registration:     RegistrationConfig{Mode: RegistrationDisabled},
+		deletedRetention: DefaultDeletedUserRetention,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.keys == nil {
+		keys, err := tools.GenerateKeyRing("", "")
+		if err != nil {
+			panic("failed to generate a signing key: " + err.Error())
+		}
+		s.keys = keys
+	}
+	return s
+}
+
+// GetAllUsers returns one page of users matching the query. Missing paging
+// and sorting options fall back to the newest users first.
+func (s *Service) GetAllUsers(ctx context.Context, query domain.UserQuery) (page *domain.UserPage, err error) {
+	ctx, span := startSpan(ctx, "GetAllUsers")
+	defer func() { endSpan(span, err) }()
+
+	switch {
+	case query.Limit <= 0:
+		query.Limit = DefaultPageSize
+	case query.Limit > MaxPageSize:
+		query.Limit = MaxPageSize
+	}
+	if query.Offset < 0 {
+		return nil, fmt.Errorf("%w: offset must not be negative", domain.ErrBadParamInput)
+	}
+
+	query.Sort = tools.Coalesce(query.Sort, "id")
+	if _, ok := domain.UserSortColumns[query.Sort]; !ok {
+		return nil, fmt.Errorf("%w: invalid sort field %q", domain.ErrBadParamInput, query.Sort)
+	}
+	query.Order = tools.Coalesce(query.Order, "desc")
+	if query.Order != "asc" && query.Order != "desc" {
+		return nil, fmt.Errorf("%w: invalid sort order %q", domain.ErrBadParamInput, query.Order)
+	}
+
+	return s.userRepo.GetAllUsers(ctx, query)
+}
+
+// CreateUser creates a new user in the repository. Users created this way
+// are set up by an administrator and count as verified.
+func (s *Service) CreateUser(ctx context.Context, user *domain.User) (err error) {
+	ctx, span := startSpan(ctx, "CreateUser")
+	defer func() { endSpan(span, err) }()
+
+	if user.EmailVerifiedAt == nil {
+		now := time.Now()
+		user.EmailVerifiedAt = &now
+	}
+	user.Status = domain.UserStatusActive
+
+	_, hashSpan := tracer.Start(ctx, "bcrypt.GenerateFromPassword")
 	user.HashPassword()
-	return s.userRepo.CreateUser(user)
+	hashSpan.End()
+
+	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditUserCreate, user.ID, nil, user)
+	return nil
 }
 
 // GetUserByID retrieves a user by their ID from the repository
-func (s *Service) GetUserByID(id uint) (*domain.User, error) {
-	return s.userRepo.GetUserByID(id)
+func (s *Service) GetUserByID(ctx context.Context, id uint) (user *domain.User, err error) {
+	ctx, span := startSpan(ctx, "GetUserByID", attribute.Int64("user.id", int64(id)))
+	defer func() { endSpan(span, err) }()
+
+	return s.userRepo.GetUserByID(ctx, id)
 }
 
 // GetUserByEmail retrieves a user by their email from the repository
-func (s *Service) GetUserByEmail(email string) (*domain.User, error) {
-	return s.userRepo.GetUserByEmail(email)
+func (s *Service) GetUserByEmail(ctx context.Context, email string) (user *domain.User, err error) {
+	ctx, span := startSpan(ctx, "GetUserByEmail")
+	defer func() { endSpan(span, err) }()
+
+	return s.userRepo.GetUserByEmail(ctx, email)
 }
 
 // UpdateUserByID updates a user's information by their ID in the repository
-func (s *Service) UpdateUserByID(id string, updatedUser domain.User) (*domain.User, error) {
-	return s.userRepo.UpdateUserByID(id, updatedUser)
+func (s *Service) UpdateUserByID(ctx context.Context, id string, updatedUser domain.User) (user *domain.User, err error) {
+	ctx, span := startSpan(ctx, "UpdateUserByID", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+
+	before := s.auditedUser(ctx, id)
+	user, err = s.userRepo.UpdateUserByID(ctx, id, updatedUser)
+	if err != nil {
+		return nil, err
+	}
+	s.InvalidatePrincipal(ctx, user.ID)
+	s.recordUser(ctx, domain.AuditUserUpdate, user.ID, before, user)
+	return user, nil
 }
 
-// DeleteUserByID deletes a user by their ID from the repository
-func (s *Service) DeleteUserByID(id string) error {
-	return s.userRepo.DeleteUserByID(id)
+// DeleteUserByID soft deletes a user by their ID. They can be restored until
+// they are purged.
+func (s *Service) DeleteUserByID(ctx context.Context, id string) (err error) {
+	ctx, span := startSpan(ctx, "DeleteUserByID", attribute.String("user.id", id))
+	defer func() { endSpan(span, err) }()
+
+	userID, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return fmt.Errorf("%w: invalid user ID %q", domain.ErrBadParamInput, id)
+	}
+	before := s.auditedUser(ctx, id)
+	if err := s.userRepo.DeleteUserByID(ctx, uint(userID)); err != nil {
+		return err
+	}
+	s.InvalidatePrincipal(ctx, uint(userID))
+	s.recordUser(ctx, domain.AuditUserDelete, uint(userID), before, nil)
+
+	// Otherwise stateless access tokens keep working until they expire, and
+	// refresh tokens work again once the user is restored.
+	if err := s.signOutEverywhere(ctx, uint(userID)); err != nil {
+		slog.ErrorContext(ctx, "Failed to revoke sessions of deleted user", "user_id", userID, "error", err)
+	}
+	return nil
 }
 
-func (s *Service) AuthenticateUser(email, password string) (string, error) {
-	user, err := s.userRepo.GetUserByEmail(email)
+func (s *Service) AuthenticateUser(ctx context.Context, email, password string) (tokens *domain.AuthTokens, err error) {
+	ctx, span := startSpan(ctx, "AuthenticateUser")
+	defer func() { endSpan(span, err) }()
+
+	// Locks are checked before the password, so a locked account gives
+	// nothing away about it.
+	keys := s.loginKeys(ctx, email)
+	if err := s.checkLoginLock(ctx, keys); err != nil {
+		if errors.Is(err, domain.ErrTooManyRequests) {
+			s.observeLogin(OutcomeLocked)
+			s.recordLogin(ctx, domain.AuditLoginLocked, email, nil)
+		} else {
+			s.observeLogin(OutcomeError)
+		}
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserByEmail(ctx, email)
+	if err != nil {
+		s.observeLogin(OutcomeInvalidCredentials)
+		s.recordLogin(ctx, domain.AuditLoginFailed, email, nil)
+		s.recordLoginFailure(ctx, keys)
+		return nil, errors.New("invalid credentials")
+	}
+
+	_, compareSpan := tracer.Start(ctx, "bcrypt.CompareHashAndPassword")
+	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password))
+	compareSpan.End()
 	if err != nil {
-		return "", errors.New("invalid credentials")
+		s.observeLogin(OutcomeInvalidCredentials)
+		s.recordLogin(ctx, domain.AuditLoginFailed, email, user)
+		s.recordLoginFailure(ctx, keys)
+		return nil, errors.New("invalid credentials")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", errors.New("invalid credentials")
+	tokens, err = s.signIn(ctx, user)
+	// A right password alone does not clear the failures: those of a second
+	// factor would otherwise be forgotten with every new challenge. VerifyMFA
+	// clears them when it completes the login.
+	if err == nil && tokens.MFAToken == "" {
+		s.resetLoginAttempts(ctx, keys)
 	}
+	return tokens, err
+}
 
-	token, err := tools.GenerateJWT(user.Email)
+// signIn finishes the login of an authenticated user: it checks that they
+// may sign in, and returns an MFA challenge when they need one and tokens
+// otherwise. Every way of logging in ends here.
+func (s *Service) signIn(ctx context.Context, user *domain.User) (*domain.AuthTokens, error) {
+	if !user.IsVerified() {
+		s.observeLogin(OutcomeUnverified)
+		return nil, domain.ErrEmailNotVerified
+	}
+	if err := user.CheckStatus(); err != nil {
+		s.observeLogin(OutcomeInactive)
+		s.recordLogin(ctx, domain.AuditLoginFailed, user.Email, user)
+		return nil, err
+	}
+
+	challenge, err := s.mfaChallenge(ctx, user)
 	if err != nil {
-		return "", err
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	if challenge != nil {
+		s.observeLogin(OutcomeMFARequired)
+		return challenge, nil
 	}
 
-	return token, nil
+	tokens, err := s.issueTokens(ctx, user, "")
+	if err != nil {
+		s.observeLogin(OutcomeError)
+		return nil, err
+	}
+	s.observeLogin(OutcomeSuccess)
+	s.recordLogin(ctx, domain.AuditLogin, user.Email, user)
+	return tokens, nil
 }
 
-func (s *Service) ValidateToken(token string) (*domain.User, error) {
-	claims, err := tools.ValidateJWT(token)
+// ValidateToken returns the principal token was issued to. In stateful mode
+// the user is loaded and the token checked against revocations; in stateless
+// mode the signed claims are trusted until the token expires.
+func (s *Service) ValidateToken(ctx context.Context, token string) (principal *domain.Principal, err error) {
+	ctx, span := startSpan(ctx, "ValidateToken")
+	defer func() { endSpan(span, err) }()
+
+	claims, err := s.keys.ValidateJWT(token)
 	if err != nil {
+		s.observeTokenValidation(OutcomeInvalidToken)
 		return nil, errors.New("invalid token")
 	}
+	userID, err := claims.UserID()
+	if err != nil {
+		s.observeTokenValidation(OutcomeInvalidToken)
+		return nil, errors.New("invalid token")
+	}
+	span.SetAttributes(attribute.Int64("user.id", int64(userID)))
 
-	user, err := s.userRepo.GetUserByEmail(claims.Email)
+	principal = &domain.Principal{
+		UserID:      userID,
+		Email:       claims.Email,
+		Roles:       claims.Roles,
+		Permissions: claims.Permissions,
+		SessionID:   claims.SessionID,
+		TokenID:     claims.ID,
+		IssuedAt:    claims.IssuedAt.Time,
+		ExpiresAt:   claims.ExpiresAt.Time,
+	}
+	if s.tokenValidation == TokenValidationStateless {
+		s.observeTokenValidation(OutcomeSuccess)
+		return principal, nil
+	}
+
+	user, err := s.principalUser(ctx, userID)
 	if err != nil {
+		s.observeTokenValidation(OutcomeUserNotFound)
 		return nil, errors.New("user not found")
 	}
+	if err := user.CheckStatus(); err != nil {
+		s.observeTokenValidation(OutcomeInactive)
+		return nil, err
+	}
 
-	return user, nil
+	if s.revocations != nil {
+		revoked, err := s.revocations.IsTokenRevoked(ctx, claims.ID, user.ID, claims.IssuedAt.Time)
+		if err != nil {
+			s.observeTokenValidation(OutcomeError)
+			return nil, err
+		}
+		if revoked {
+			s.observeTokenValidation(OutcomeRevoked)
+			return nil, errors.New("token revoked")
+		}
+	}
+
+	// The user's current email, roles and permissions win over the claims.
+	current := domain.NewPrincipal(user)
+	principal.Email, principal.Roles, principal.Permissions, principal.User =
+		current.Email, current.Roles, current.Permissions, user
+
+	s.observeTokenValidation(OutcomeSuccess)
+	return principal, nil
 }
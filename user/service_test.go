@@ -1,14 +1,28 @@
 package user_test
 
 import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
 	"errors"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"github.com/tat-101/bb-assignment-back/domain"
 	"github.com/tat-101/bb-assignment-back/tools"
 	"github.com/tat-101/bb-assignment-back/user"
 	"github.com/tat-101/bb-assignment-back/user/mocks"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -16,29 +30,57 @@ func TestService_GetAllUsers(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	service := user.NewService(mockUserRepo)
 
-	expectedUsers := []domain.User{
-		{ID: 1, Email: "user1@example.com", Name: "User One"},
-		{ID: 2, Email: "user2@example.com", Name: "User Two"},
+	expectedPage := &domain.UserPage{
+		Items: []domain.User{
+			{ID: 1, Email: "user1@example.com", Name: "User One"},
+			{ID: 2, Email: "user2@example.com", Name: "User Two"},
+		},
+		Total: 2,
 	}
 
-	mockUserRepo.On("GetAllUsers").Return(expectedUsers, nil)
+	expectedQuery := domain.UserQuery{Limit: user.DefaultPageSize, Sort: "id", Order: "desc"}
+	mockUserRepo.On("GetAllUsers", mock.Anything, expectedQuery).Return(expectedPage, nil)
 
-	users, err := service.GetAllUsers()
+	page, err := service.GetAllUsers(context.Background(), domain.UserQuery{})
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedUsers, users)
+	assert.Equal(t, expectedPage, page)
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestService_GetAllUsers_ClampsLimit(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	expectedQuery := domain.UserQuery{Limit: user.MaxPageSize, Sort: "name", Order: "asc"}
+	mockUserRepo.On("GetAllUsers", mock.Anything, expectedQuery).Return(&domain.UserPage{}, nil)
+
+	_, err := service.GetAllUsers(context.Background(), domain.UserQuery{Limit: 1000, Sort: "name", Order: "asc"})
+
+	assert.NoError(t, err)
+	mockUserRepo.AssertExpectations(t)
+}
+
+func TestService_GetAllUsers_InvalidSort(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	page, err := service.GetAllUsers(context.Background(), domain.UserQuery{Sort: "password"})
+
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	assert.Nil(t, page)
+	mockUserRepo.AssertNotCalled(t, "GetAllUsers", mock.Anything)
+}
+
 func TestService_CreateUser(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	service := user.NewService(mockUserRepo)
 
 	newUser := &domain.User{Email: "newuser@example.com", Name: "New User"}
 
-	mockUserRepo.On("CreateUser", newUser).Return(nil)
+	mockUserRepo.On("CreateUser", mock.Anything, newUser).Return(nil)
 
-	err := service.CreateUser(newUser)
+	err := service.CreateUser(context.Background(), newUser)
 
 	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
@@ -50,9 +92,9 @@ func TestService_GetUserByID(t *testing.T) {
 
 	expectedUser := &domain.User{ID: 1, Email: "user@example.com", Name: "User One"}
 
-	mockUserRepo.On("GetUserByID", uint(1)).Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(expectedUser, nil)
 
-	user, err := service.GetUserByID(1)
+	user, err := service.GetUserByID(context.Background(), 1)
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
@@ -65,9 +107,9 @@ func TestService_GetUserByEmail(t *testing.T) {
 
 	expectedUser := &domain.User{Email: "user@example.com", Name: "User One"}
 
-	mockUserRepo.On("GetUserByEmail", "user@example.com").Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(expectedUser, nil)
 
-	user, err := service.GetUserByEmail("user@example.com")
+	user, err := service.GetUserByEmail(context.Background(), "user@example.com")
 
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
@@ -80,9 +122,9 @@ func TestService_UpdateUserByID(t *testing.T) {
 
 	updatedUser := domain.User{Email: "updated@example.com", Name: "Updated User"}
 
-	mockUserRepo.On("UpdateUserByID", "1", updatedUser).Return(&updatedUser, nil)
+	mockUserRepo.On("UpdateUserByID", mock.Anything, "1", updatedUser).Return(&updatedUser, nil)
 
-	newUser, err := service.UpdateUserByID("1", updatedUser)
+	newUser, err := service.UpdateUserByID(context.Background(), "1", updatedUser)
 
 	assert.NoError(t, err)
 	assert.Equal(t, newUser.Name, updatedUser.Name)
@@ -93,9 +135,9 @@ func TestService_DeleteUserByID(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	service := user.NewService(mockUserRepo)
 
-	mockUserRepo.On("DeleteUserByID", "1").Return(nil)
+	mockUserRepo.On("DeleteUserByID", mock.Anything, uint(1)).Return(nil)
 
-	err := service.DeleteUserByID("1")
+	err := service.DeleteUserByID(context.Background(), "1")
 
 	assert.NoError(t, err)
 	mockUserRepo.AssertExpectations(t)
@@ -110,13 +152,14 @@ func TestService_AuthenticateUser(t *testing.T) {
 
 	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
 	expectedUser := &domain.User{
-		Email:    "user@example.com",
-		Password: string(hashedPassword),
+		Email:           "user@example.com",
+		Password:        string(hashedPassword),
+		EmailVerifiedAt: verifiedAt(),
 	}
 
-	mockUserRepo.On("GetUserByEmail", email).Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(expectedUser, nil)
 
-	token, err := service.AuthenticateUser(email, password)
+	token, err := service.AuthenticateUser(context.Background(), email, password)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -135,44 +178,184 @@ func TestService_AuthenticateUser_Fail(t *testing.T) {
 		Password: "$2a$10$12345678901234567890123456789012345678901234567890",
 	}
 
-	mockUserRepo.On("GetUserByEmail", email).Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, email).Return(expectedUser, nil)
 
-	token, err := service.AuthenticateUser(email, password)
+	token, err := service.AuthenticateUser(context.Background(), email, password)
 
 	assert.Error(t, err)
 	assert.Empty(t, token)
 	mockUserRepo.AssertExpectations(t)
 }
 
-func TestService_ValidateToken_Success(t *testing.T) {
+func TestService_AuthenticateUser_Metrics(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
-	service := user.NewService(mockUserRepo)
+	mockMetrics := new(mocks.Metrics)
+	service := user.NewService(mockUserRepo, user.WithMetrics(mockMetrics))
 
-	email := "test@example.com"
-	token, err := tools.GenerateJWT(email)
-	// fmt.Println("token", token)
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	expectedUser := &domain.User{Email: "user@example.com", Password: string(hashedPassword), EmailVerifiedAt: verifiedAt()}
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "unknown@example.com").Return(nil, errors.New("record not found"))
+	mockMetrics.On("ObserveLogin", user.OutcomeSuccess).Once()
+	mockMetrics.On("ObserveLogin", user.OutcomeInvalidCredentials).Twice()
 
+	_, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
 	assert.NoError(t, err)
+	_, err = service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	assert.Error(t, err)
+	_, err = service.AuthenticateUser(context.Background(), "unknown@example.com", "password123")
+	assert.Error(t, err)
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestService_ValidateToken_Metrics(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockMetrics := new(mocks.Metrics)
+	service := user.NewService(mockUserRepo, user.WithMetrics(mockMetrics), user.WithKeyRing(testKeys))
+
+	token := accessToken(t, testKeys, 1, "test@example.com")
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(&domain.User{ID: 1, Email: "test@example.com"}, nil)
+	mockMetrics.On("ObserveTokenValidation", user.OutcomeSuccess).Once()
+	mockMetrics.On("ObserveTokenValidation", user.OutcomeInvalidToken).Once()
+
+	_, err := service.ValidateToken(context.Background(), token)
+	assert.NoError(t, err)
+	_, err = service.ValidateToken(context.Background(), "not-a-token")
+	assert.Error(t, err)
+
+	mockMetrics.AssertExpectations(t)
+}
+
+func TestService_ValidateToken_Success(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo, user.WithKeyRing(testKeys))
+
+	token := accessToken(t, testKeys, 1, "old@example.com")
 
 	expectedUser := &domain.User{
-		Email: email,
+		ID:    1,
+		Email: "test@example.com",
 		Name:  "Test User",
+		Roles: []domain.Role{{Name: "admin", Permissions: []domain.Permission{{Name: domain.PermissionUsersRead}}}},
 	}
 
-	mockUserRepo.On("GetUserByEmail", email).Return(expectedUser, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(expectedUser, nil)
 
-	user, err := service.ValidateToken(token)
+	principal, err := service.ValidateToken(context.Background(), token)
 
 	assert.NoError(t, err)
-	assert.Equal(t, expectedUser, user)
+	assert.Equal(t, expectedUser, principal.User)
+	assert.Equal(t, uint(1), principal.UserID)
+	assert.Equal(t, "test@example.com", principal.Email, "a changed email does not end the session")
+	assert.Equal(t, []string{"admin"}, principal.Roles)
+	assert.True(t, principal.HasPermission(domain.PermissionUsersRead))
 	mockUserRepo.AssertExpectations(t)
 }
 
+func TestService_ValidateToken_KeyRotation(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	_, oldKey, _ := ed25519.GenerateKey(nil)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	oldKeys, err := tools.NewKeyRing(tools.KeyRingConfig{SigningKey: oldKey, Issuer: "bb", Audience: "api"})
+	assert.NoError(t, err)
+	rotated, err := tools.NewKeyRing(tools.KeyRingConfig{
+		SigningKey:       newKey,
+		VerificationKeys: []crypto.PublicKey{oldKey.Public()},
+		Issuer:           "bb",
+		Audience:         "api",
+	})
+	assert.NoError(t, err)
+	service := user.NewService(mockUserRepo, user.WithKeyRing(rotated))
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(&domain.User{ID: 1, Email: "test@example.com"}, nil)
+
+	oldToken := accessToken(t, oldKeys, 1, "test@example.com")
+	_, err = service.ValidateToken(context.Background(), oldToken)
+	assert.NoError(t, err, "tokens signed with the previous key stay valid")
+
+	newToken := accessToken(t, rotated, 1, "test@example.com")
+	_, err = service.ValidateToken(context.Background(), newToken)
+	assert.NoError(t, err)
+	_, err = oldKeys.ValidateJWT(newToken)
+	assert.Error(t, err, "the old ring does not know the new key")
+
+	jwks := rotated.JWKS()
+	if assert.Len(t, jwks.Keys, 2) {
+		assert.Equal(t, rotated.KeyID(), jwks.Keys[0]["kid"])
+		assert.Equal(t, "RS256", jwks.Keys[0]["alg"])
+		assert.Equal(t, oldKeys.KeyID(), jwks.Keys[1]["kid"])
+		assert.Equal(t, "EdDSA", jwks.Keys[1]["alg"])
+	}
+}
+
+func TestService_ValidateToken_IssuerAndAudience(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	_, key, _ := ed25519.GenerateKey(nil)
+	_, stranger, _ := ed25519.GenerateKey(nil)
+	keys, err := tools.NewKeyRing(tools.KeyRingConfig{SigningKey: key, Issuer: "bb", Audience: "api"})
+	assert.NoError(t, err)
+	service := user.NewService(mockUserRepo, user.WithKeyRing(keys))
+
+	for name, cfg := range map[string]tools.KeyRingConfig{
+		"other issuer":   {SigningKey: key, Issuer: "other", Audience: "api"},
+		"other audience": {SigningKey: key, Issuer: "bb", Audience: "other"},
+		"unknown key":    {SigningKey: stranger, Issuer: "bb", Audience: "api"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			other, err := tools.NewKeyRing(cfg)
+			assert.NoError(t, err)
+			token := accessToken(t, other, 1, "test@example.com")
+
+			_, err = service.ValidateToken(context.Background(), token)
+
+			assert.EqualError(t, err, "invalid token")
+		})
+	}
+	mockUserRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+}
+
+func TestService_ValidateToken_Stateless(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo,
+		user.WithKeyRing(testKeys),
+		user.WithRevocationStore(mockRevocations),
+		user.WithTokenValidation(user.TokenValidationStateless),
+	)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "test@example.com").Return(&domain.User{
+		ID:              5,
+		Email:           "test@example.com",
+		Password:        string(hashedPassword),
+		Roles:           []domain.Role{{Name: "user", Permissions: []domain.Permission{{Name: domain.PermissionUsersRead}}}},
+		EmailVerifiedAt: verifiedAt(),
+	}, nil)
+	tokens, err := service.AuthenticateUser(context.Background(), "test@example.com", "password123")
+	require.NoError(t, err)
+
+	principal, err := service.ValidateToken(context.Background(), tokens.AccessToken)
+
+	assert.NoError(t, err)
+	assert.Nil(t, principal.User)
+	assert.Equal(t, uint(5), principal.UserID)
+	assert.Equal(t, "test@example.com", principal.Email)
+	assert.Equal(t, []string{"user"}, principal.Roles)
+	assert.Equal(t, []string{domain.PermissionUsersRead}, principal.Permissions)
+	assert.NotEmpty(t, principal.SessionID)
+	assert.NotEmpty(t, principal.TokenID)
+	mockUserRepo.AssertNotCalled(t, "GetUserByID", mock.Anything, mock.Anything)
+	mockRevocations.AssertNotCalled(t, "IsTokenRevoked", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestService_ValidateToken_InvalidToken(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
 	service := user.NewService(mockUserRepo)
 
-	user, err := service.ValidateToken("invalidToken")
+	user, err := service.ValidateToken(context.Background(), "invalidToken")
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
@@ -181,17 +364,59 @@ func TestService_ValidateToken_InvalidToken(t *testing.T) {
 
 func TestService_ValidateToken_UserNotFound(t *testing.T) {
 	mockUserRepo := new(mocks.UserRepository)
-	service := user.NewService(mockUserRepo)
+	service := user.NewService(mockUserRepo, user.WithKeyRing(testKeys))
 
-	email := "notfound@example.com"
-	token, _ := tools.GenerateJWT(email)
+	token := accessToken(t, testKeys, 404, "notfound@example.com")
 
-	mockUserRepo.On("GetUserByEmail", email).Return(nil, errors.New("user not found"))
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(404)).Return(nil, errors.New("user not found"))
 
-	user, err := service.ValidateToken(token)
+	user, err := service.ValidateToken(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Nil(t, user)
 	assert.Equal(t, "user not found", err.Error())
 	mockUserRepo.AssertExpectations(t)
 }
+
+func TestService_Tracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").
+		Return(&domain.User{Email: "user@example.com", Password: string(hashedPassword)}, nil)
+
+	_, err := service.AuthenticateUser(context.Background(), "user@example.com", "wrong")
+	assert.Error(t, err)
+
+	spans := recorder.Ended()
+	names := make([]string, len(spans))
+	for i, span := range spans {
+		names[i] = span.Name()
+	}
+	assert.Equal(t, []string{"bcrypt.CompareHashAndPassword", "user.Service/AuthenticateUser"}, names)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+}
+
+// accessToken signs an access token for the user with keys.
+func accessToken(t *testing.T, keys *tools.KeyRing, userID uint, email string) string {
+	t.Helper()
+	token, err := keys.GenerateJWT(tools.Claims{
+		Email:            email,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: strconv.FormatUint(uint64(userID), 10)},
+	}, time.Hour)
+	require.NoError(t, err)
+	return token
+}
+
+// testKeys signs the access tokens tests hand to services built with it.
+var testKeys, _ = tools.GenerateKeyRing("", "")
+
+func verifiedAt() *time.Time {
+	now := time.Now()
+	return &now
+}
@@ -0,0 +1,93 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrRevocationNotEnabled = errors.New("token revocation is not enabled")
+
+// Logout revokes the given access token until it expires. When refreshToken
+// is set, the refresh token family it belongs to is revoked as well.
+func (s *Service) Logout(ctx context.Context, token, refreshToken string) (err error) {
+	ctx, span := startSpan(ctx, "Logout")
+	defer func() { endSpan(span, err) }()
+
+	if s.revocations == nil {
+		return ErrRevocationNotEnabled
+	}
+
+	claims, err := s.keys.ValidateJWT(token)
+	if err != nil {
+		return errors.New("invalid token")
+	}
+	userID, err := claims.UserID()
+	if err != nil {
+		return errors.New("invalid token")
+	}
+
+	if err := s.revocations.RevokeToken(ctx, claims.ID, userID, claims.ExpiresAt.Time); err != nil {
+		return err
+	}
+
+	if refreshToken == "" || s.refreshTokenRepo == nil {
+		return nil
+	}
+	stored, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, tools.HashToken(refreshToken))
+	if err != nil || stored.UserID != userID {
+		// The access token is already revoked; an unknown refresh token
+		// has nothing left to log out of.
+		return nil
+	}
+	return s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, stored.FamilyID)
+}
+
+// RevokeUserSessions invalidates every access token issued to the user so far
+// and all of their refresh tokens.
+func (s *Service) RevokeUserSessions(ctx context.Context, userID uint) (err error) {
+	ctx, span := startSpan(ctx, "RevokeUserSessions", attribute.Int64("user.id", int64(userID)))
+	defer func() { endSpan(span, err) }()
+
+	if s.revocations == nil {
+		return ErrRevocationNotEnabled
+	}
+
+	now := time.Now()
+	if err := s.revocations.RevokeUserTokens(ctx, userID, now, now.Add(s.accessTokenTTL)); err != nil {
+		return err
+	}
+	s.recordUser(ctx, domain.AuditSessionsRevoke, userID, nil, nil)
+
+	if s.refreshTokenRepo == nil {
+		return nil
+	}
+	return s.refreshTokenRepo.RevokeRefreshTokensByUser(ctx, userID)
+}
+
+// signOutEverywhere revokes the user's sessions, or only their refresh
+// tokens when access tokens can not be revoked.
+func (s *Service) signOutEverywhere(ctx context.Context, userID uint) error {
+	switch {
+	case s.revocations != nil:
+		return s.RevokeUserSessions(ctx, userID)
+	case s.refreshTokenRepo != nil:
+		return s.refreshTokenRepo.RevokeRefreshTokensByUser(ctx, userID)
+	}
+	return nil
+}
+
+// PruneRevocations deletes revocations whose tokens have expired anyway.
+func (s *Service) PruneRevocations(ctx context.Context) (pruned int64, err error) {
+	ctx, span := startSpan(ctx, "PruneRevocations")
+	defer func() { endSpan(span, err) }()
+
+	if s.revocations == nil {
+		return 0, nil
+	}
+	return s.revocations.DeleteExpiredRevocations(ctx, time.Now())
+}
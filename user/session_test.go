@@ -0,0 +1,113 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func TestService_Logout(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo,
+		user.WithRefreshTokens(mockTokenRepo, time.Hour),
+		user.WithRevocationStore(mockRevocations),
+		user.WithKeyRing(testKeys),
+	)
+
+	token := accessToken(t, testKeys, 7, "user@example.com")
+	claims, _ := testKeys.ValidateJWT(token)
+
+	mockRevocations.On("RevokeToken", mock.Anything, claims.ID, uint(7), claims.ExpiresAt.Time).Return(nil)
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("refresh")).
+		Return(&domain.RefreshToken{UserID: 7, FamilyID: "family"}, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+	err := service.Logout(context.Background(), token, "refresh")
+
+	assert.NoError(t, err)
+	mockRevocations.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_Logout_IgnoresForeignRefreshToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo,
+		user.WithRefreshTokens(mockTokenRepo, time.Hour),
+		user.WithRevocationStore(mockRevocations),
+		user.WithKeyRing(testKeys),
+	)
+
+	token := accessToken(t, testKeys, 7, "user@example.com")
+
+	mockRevocations.On("RevokeToken", mock.Anything, mock.Anything, uint(7), mock.Anything).Return(nil)
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("someone-else")).
+		Return(&domain.RefreshToken{UserID: 8, FamilyID: "other"}, nil)
+
+	err := service.Logout(context.Background(), token, "someone-else")
+
+	assert.NoError(t, err)
+	mockTokenRepo.AssertNotCalled(t, "RevokeRefreshTokenFamily", "other")
+}
+
+func TestService_RevokeUserSessions(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo,
+		user.WithRefreshTokens(mockTokenRepo, time.Hour),
+		user.WithRevocationStore(mockRevocations),
+	)
+
+	mockRevocations.On("RevokeUserTokens", mock.Anything, uint(7), mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(nil)
+	mockTokenRepo.On("RevokeRefreshTokensByUser", mock.Anything, uint(7)).Return(nil)
+
+	err := service.RevokeUserSessions(context.Background(), 7)
+
+	assert.NoError(t, err)
+	mockRevocations.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_ValidateToken_Revoked(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo, user.WithRevocationStore(mockRevocations), user.WithKeyRing(testKeys))
+
+	token := accessToken(t, testKeys, 7, "user@example.com")
+	claims, _ := testKeys.ValidateJWT(token)
+
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(&domain.User{ID: 7, Email: "user@example.com"}, nil)
+	mockRevocations.On("IsTokenRevoked", mock.Anything, claims.ID, uint(7), claims.IssuedAt.Time).Return(true, nil)
+
+	user, err := service.ValidateToken(context.Background(), token)
+
+	assert.Error(t, err)
+	assert.Nil(t, user)
+	assert.Equal(t, "token revoked", err.Error())
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestService_PruneRevocations(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo, user.WithRevocationStore(mockRevocations))
+
+	mockRevocations.On("DeleteExpiredRevocations", mock.Anything, mock.AnythingOfType("time.Time")).Return(int64(3), nil)
+
+	pruned, err := service.PruneRevocations(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pruned)
+	mockRevocations.AssertExpectations(t)
+}
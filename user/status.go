@@ -0,0 +1,85 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/tat-101/bb-assignment-back/audit"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ChangeUserStatus moves a user to status for reason, if their current
+// status allows it. A user who is no longer active is signed out everywhere.
+func (s *Service) ChangeUserStatus(ctx context.Context, id uint, status, reason string) (user *domain.User, err error) {
+	ctx, span := startSpan(ctx, "ChangeUserStatus",
+		attribute.Int64("user.id", int64(id)), attribute.String("user.status", status))
+	defer func() { endSpan(span, err) }()
+
+	if _, ok := domain.UserStatusTransitions[status]; !ok {
+		return nil, fmt.Errorf("%w: unknown status %q", domain.ErrBadParamInput, status)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: a reason is required", domain.ErrBadParamInput)
+	}
+	user, err = s.userRepo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.changeStatus(ctx, user, status, reason); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetUserStatusHistory returns a user's status changes, newest first.
+func (s *Service) GetUserStatusHistory(ctx context.Context, id uint) (changes []domain.UserStatusChange, err error) {
+	ctx, span := startSpan(ctx, "GetUserStatusHistory", attribute.Int64("user.id", int64(id)))
+	defer func() { endSpan(span, err) }()
+
+	if _, err := s.userRepo.GetUserByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.userRepo.GetUserStatusHistory(ctx, id)
+}
+
+func (s *Service) changeStatus(ctx context.Context, user *domain.User, status, reason string) error {
+	from := user.AccountStatus()
+	if !domain.CanChangeUserStatus(from, status) {
+		return fmt.Errorf("%w: a %s user can not become %s", domain.ErrConflict, from, status)
+	}
+	change := &domain.UserStatusChange{
+		UserID:     user.ID,
+		FromStatus: from,
+		ToStatus:   status,
+		Reason:     reason,
+	}
+	if principal := domain.PrincipalFrom(ctx); principal != nil {
+		change.ActorID = &principal.UserID
+	}
+	if err := s.userRepo.ChangeUserStatus(ctx, change); err != nil {
+		return err
+	}
+	user.Status = status
+	s.InvalidatePrincipal(ctx, user.ID)
+	s.record(ctx, audit.Entry{
+		Action:     domain.AuditUserStatus,
+		TargetType: domain.AuditTargetUser,
+		TargetID:   strconv.FormatUint(uint64(user.ID), 10),
+		Before:     map[string]string{"status": from},
+		After:      map[string]string{"status": status, "reason": reason},
+	})
+
+	// Otherwise tokens issued before would work again once the user is
+	// reactivated.
+	if status != domain.UserStatusActive && s.revocations != nil {
+		if err := s.RevokeUserSessions(ctx, user.ID); err != nil {
+			slog.ErrorContext(ctx, "Failed to revoke sessions of inactive user", "user_id", user.ID, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+)
+
+func TestService_ChangeUserStatus(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockRevocations := new(mocks.RevocationStore)
+	service := user.NewService(mockUserRepo, user.WithRevocationStore(mockRevocations))
+
+	actorID := uint(1)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(verifiedUser(t, 7, "user"), nil)
+	mockUserRepo.On("ChangeUserStatus", mock.Anything, &domain.UserStatusChange{
+		UserID:     7,
+		FromStatus: domain.UserStatusActive,
+		ToStatus:   domain.UserStatusSuspended,
+		Reason:     "Chargeback",
+		ActorID:    &actorID,
+	}).Return(nil)
+	mockRevocations.On("RevokeUserTokens", mock.Anything, uint(7), mock.Anything, mock.Anything).Return(nil)
+
+	ctx := domain.WithPrincipal(context.Background(), &domain.Principal{UserID: 1})
+	suspended, err := service.ChangeUserStatus(ctx, 7, domain.UserStatusSuspended, "  Chargeback ")
+
+	require.NoError(t, err)
+	assert.Equal(t, domain.UserStatusSuspended, suspended.Status)
+	mockUserRepo.AssertExpectations(t)
+	mockRevocations.AssertExpectations(t)
+}
+
+func TestService_ChangeUserStatus_Invalid(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	service := user.NewService(mockUserRepo)
+
+	pending := verifiedUser(t, 7, "user")
+	pending.Status = domain.UserStatusPending
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(pending, nil)
+
+	_, err := service.ChangeUserStatus(context.Background(), 7, "banned", "Spam")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput)
+	_, err = service.ChangeUserStatus(context.Background(), 7, domain.UserStatusSuspended, " ")
+	assert.ErrorIs(t, err, domain.ErrBadParamInput, "a reason is required")
+	_, err = service.ChangeUserStatus(context.Background(), 7, domain.UserStatusLocked, "Spam")
+	assert.ErrorIs(t, err, domain.ErrConflict, "pending users can not be locked")
+
+	mockUserRepo.AssertNotCalled(t, "ChangeUserStatus", mock.Anything, mock.Anything)
+}
+
+func TestService_InactiveUsers(t *testing.T) {
+	for _, status := range []string{domain.UserStatusSuspended, domain.UserStatusLocked} {
+		t.Run(status, func(t *testing.T) {
+			mockUserRepo := new(mocks.UserRepository)
+			mockMetrics := new(mocks.Metrics)
+			service := user.NewService(mockUserRepo, user.WithMetrics(mockMetrics))
+
+			active := verifiedUser(t, 7, "user")
+			mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(active, nil).Once()
+			mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(active, nil).Once()
+			mockMetrics.On("ObserveLogin", mock.Anything)
+			mockMetrics.On("ObserveTokenValidation", mock.Anything)
+
+			tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+			require.NoError(t, err)
+			_, err = service.ValidateToken(context.Background(), tokens.AccessToken)
+			require.NoError(t, err)
+
+			inactive := verifiedUser(t, 7, "user")
+			inactive.Status = status
+			mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(inactive, nil)
+			mockUserRepo.On("GetUserByID", mock.Anything, uint(7)).Return(inactive, nil)
+
+			_, err = service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+			assert.ErrorIs(t, err, domain.ErrAccountInactive)
+			_, err = service.ValidateToken(context.Background(), tokens.AccessToken)
+			assert.ErrorIs(t, err, domain.ErrAccountInactive, "the token stops working at once")
+
+			mockMetrics.AssertCalled(t, "ObserveLogin", user.OutcomeInactive)
+			mockMetrics.AssertCalled(t, "ObserveTokenValidation", user.OutcomeInactive)
+		})
+	}
+}
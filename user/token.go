@@ -0,0 +1,123 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reused")
+)
+
+// issueTokens creates an access token for the user and, when refresh tokens
+// are enabled, a refresh token in familyID. An empty familyID starts a new
+// family, which is what a fresh login does.
+// The family ID is the session ID in the access token.
+func (s *Service) issueTokens(ctx context.Context, user *domain.User, familyID string) (*domain.AuthTokens, error) {
+	if err := user.CheckStatus(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if familyID == "" {
+		familyID, err = tools.GenerateRandomToken(16)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	accessToken, err := s.keys.GenerateJWT(tools.Claims{
+		Email:       user.Email,
+		Roles:       user.RoleNames(),
+		Permissions: user.PermissionNames(),
+		SessionID:   familyID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject: strconv.FormatUint(uint64(user.ID), 10),
+		},
+	}, s.accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+	tokens := &domain.AuthTokens{
+		AccessToken: accessToken,
+		ExpiresAt:   now.Add(s.accessTokenTTL),
+	}
+
+	if s.refreshTokenRepo == nil {
+		return tokens, nil
+	}
+
+	refreshToken, err := tools.GenerateRandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	err = s.refreshTokenRepo.CreateRefreshToken(ctx, &domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: tools.HashToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTokenTTL),
+	})
+	if err != nil {
+		return nil, err
+	}
+	tokens.RefreshToken = refreshToken
+
+	return tokens, nil
+}
+
+// RefreshToken exchanges a refresh token for a new access token and a new
+// refresh token. Every refresh token can be used once; presenting a spent one
+// is treated as theft and revokes every token in its family.
+func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (tokens *domain.AuthTokens, err error) {
+	ctx, span := startSpan(ctx, "RefreshToken")
+	defer func() { endSpan(span, err) }()
+
+	if s.refreshTokenRepo == nil || refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.refreshTokenRepo.GetRefreshTokenByHash(ctx, tools.HashToken(refreshToken))
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if stored.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+	if stored.UsedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	// Another request may have used the token between the read and here.
+	ok, err := s.refreshTokenRepo.MarkRefreshTokenUsed(ctx, stored.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+
+	user, err := s.userRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	return s.issueTokens(ctx, user, stored.FamilyID)
+}
+
+func (s *Service) revokeReusedFamily(ctx context.Context, familyID string) error {
+	if err := s.refreshTokenRepo.RevokeRefreshTokenFamily(ctx, familyID); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
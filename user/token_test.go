@@ -0,0 +1,126 @@
+package user_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tat-101/bb-assignment-back/domain"
+	"github.com/tat-101/bb-assignment-back/tools"
+	"github.com/tat-101/bb-assignment-back/user"
+	"github.com/tat-101/bb-assignment-back/user/mocks"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestService_AuthenticateUser_IssuesRefreshToken(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockTokenRepo, time.Hour))
+
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	expectedUser := &domain.User{ID: 1, Email: "user@example.com", Password: string(hashedPassword), EmailVerifiedAt: verifiedAt()}
+
+	mockUserRepo.On("GetUserByEmail", mock.Anything, "user@example.com").Return(expectedUser, nil)
+	mockTokenRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.UserID == 1 && token.FamilyID != "" && token.TokenHash != ""
+	})).Return(nil)
+
+	tokens, err := service.AuthenticateUser(context.Background(), "user@example.com", "password123")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken_Rotates(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockTokenRepo, time.Hour), user.WithKeyRing(testKeys))
+
+	stored := &domain.RefreshToken{
+		ID:        5,
+		UserID:    1,
+		FamilyID:  "family",
+		TokenHash: tools.HashToken("old"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("old")).Return(stored, nil)
+	mockTokenRepo.On("MarkRefreshTokenUsed", mock.Anything, uint(5)).Return(true, nil)
+	mockUserRepo.On("GetUserByID", mock.Anything, uint(1)).Return(&domain.User{ID: 1, Email: "user@example.com"}, nil)
+	mockTokenRepo.On("CreateRefreshToken", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+		return token.FamilyID == "family" && token.TokenHash != tools.HashToken("old")
+	})).Return(nil)
+
+	tokens, err := service.RefreshToken(context.Background(), "old")
+
+	assert.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEqual(t, "old", tokens.RefreshToken)
+	claims, err := testKeys.ValidateJWT(tokens.AccessToken)
+	assert.NoError(t, err)
+	assert.Equal(t, "1", claims.Subject)
+	assert.Equal(t, "family", claims.SessionID, "refreshing keeps the session")
+	mockUserRepo.AssertExpectations(t)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken_ReuseRevokesFamily(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockTokenRepo, time.Hour))
+
+	usedAt := time.Now().Add(-time.Minute)
+	stored := &domain.RefreshToken{
+		ID:        5,
+		UserID:    1,
+		FamilyID:  "family",
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("spent")).Return(stored, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+	tokens, err := service.RefreshToken(context.Background(), "spent")
+
+	assert.ErrorIs(t, err, user.ErrRefreshTokenReused)
+	assert.Nil(t, tokens)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken_ConcurrentUseRevokesFamily(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockTokenRepo, time.Hour))
+
+	stored := &domain.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(time.Hour)}
+
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("racy")).Return(stored, nil)
+	mockTokenRepo.On("MarkRefreshTokenUsed", mock.Anything, uint(5)).Return(false, nil)
+	mockTokenRepo.On("RevokeRefreshTokenFamily", mock.Anything, "family").Return(nil)
+
+	_, err := service.RefreshToken(context.Background(), "racy")
+
+	assert.ErrorIs(t, err, user.ErrRefreshTokenReused)
+	mockTokenRepo.AssertExpectations(t)
+}
+
+func TestService_RefreshToken_Expired(t *testing.T) {
+	mockUserRepo := new(mocks.UserRepository)
+	mockTokenRepo := new(mocks.RefreshTokenRepository)
+	service := user.NewService(mockUserRepo, user.WithRefreshTokens(mockTokenRepo, time.Hour))
+
+	stored := &domain.RefreshToken{ID: 5, UserID: 1, FamilyID: "family", ExpiresAt: time.Now().Add(-time.Minute)}
+
+	mockTokenRepo.On("GetRefreshTokenByHash", mock.Anything, tools.HashToken("expired")).Return(stored, nil)
+
+	_, err := service.RefreshToken(context.Background(), "expired")
+
+	assert.ErrorIs(t, err, user.ErrInvalidRefreshToken)
+	mockTokenRepo.AssertExpectations(t)
+}